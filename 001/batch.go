@@ -0,0 +1,141 @@
+package main
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type BlendMode int
+
+const (
+	BlendAlpha BlendMode = iota
+	BlendAdditive
+)
+
+func (m BlendMode) String() string {
+	switch m {
+	case BlendAdditive:
+		return "additive"
+	default:
+		return "alpha"
+	}
+}
+
+func (m BlendMode) blend() ebiten.Blend {
+	if m == BlendAdditive {
+		return ebiten.BlendLighter
+	}
+	return ebiten.BlendSourceOver
+}
+
+// indices are uint16, so a single draw call can't address more vertices.
+const maxBatchVertices = math.MaxUint16 / 4 * 4
+
+// Batch accumulates quads sharing one texture and draws them with a single
+// DrawTriangles call. The vertex and index slices are reused every frame.
+type Batch struct {
+	Texture *ebiten.Image
+	// Src is the region of Texture mapped onto every quad.
+	Src            image.Rectangle
+	Blend          BlendMode
+	Filter         ebiten.Filter
+	ColorScaleMode ebiten.ColorScaleMode
+
+	vertices  []ebiten.Vertex
+	indices   []uint16
+	dst       *ebiten.Image
+	op        ebiten.DrawTrianglesOptions
+	drawCalls int
+}
+
+func NewBatch(texture *ebiten.Image) *Batch {
+	return &Batch{
+		Texture: texture,
+		Src:     texture.Bounds(),
+	}
+}
+
+// Begin starts a new batch drawn onto dst.
+func (b *Batch) Begin(dst *ebiten.Image) {
+	b.dst = dst
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+	b.drawCalls = 0
+}
+
+// AddQuad adds an axis aligned rectangle from (x0, y0) to (x1, y1).
+func (b *Batch) AddQuad(x0, y0, x1, y1 float32, cr, cg, cb, ca float32) {
+	b.addQuad(x0, y0, x1, y0, x0, y1, x1, y1, cr, cg, cb, ca)
+}
+
+// AddSegment adds a line from (x1, y1) to (x2, y2) as a quad of the given width.
+func (b *Batch) AddSegment(x1, y1, x2, y2, width float32, cr, cg, cb, ca float32) {
+	dx, dy := x2-x1, y2-y1
+	l := float32(math.Hypot(float64(dx), float64(dy)))
+	if l == 0 {
+		return
+	}
+	nx, ny := -dy/l*width/2, dx/l*width/2
+	b.addQuad(
+		x1+nx, y1+ny,
+		x2+nx, y2+ny,
+		x1-nx, y1-ny,
+		x2-nx, y2-ny,
+		cr, cg, cb, ca,
+	)
+}
+
+// addQuad adds the corners in the order top-left, top-right, bottom-left,
+// bottom-right of the source region.
+func (b *Batch) addQuad(x0, y0, x1, y1, x2, y2, x3, y3 float32, cr, cg, cb, ca float32) {
+	if len(b.vertices)+4 > maxBatchVertices {
+		b.Flush()
+	}
+	sx0, sy0 := float32(b.Src.Min.X), float32(b.Src.Min.Y)
+	sx1, sy1 := float32(b.Src.Max.X), float32(b.Src.Max.Y)
+	i := uint16(len(b.vertices))
+	b.vertices = append(b.vertices,
+		ebiten.Vertex{DstX: x0, DstY: y0, SrcX: sx0, SrcY: sy0, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+		ebiten.Vertex{DstX: x1, DstY: y1, SrcX: sx1, SrcY: sy0, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+		ebiten.Vertex{DstX: x2, DstY: y2, SrcX: sx0, SrcY: sy1, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+		ebiten.Vertex{DstX: x3, DstY: y3, SrcX: sx1, SrcY: sy1, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+	)
+	b.indices = append(b.indices, i, i+1, i+2, i+1, i+3, i+2)
+}
+
+// Flush draws everything added since the last flush.
+func (b *Batch) Flush() {
+	if len(b.indices) == 0 {
+		return
+	}
+	if b.dst != nil {
+		b.op.Blend = b.Blend.blend()
+		b.op.Filter = b.Filter
+		b.op.ColorScaleMode = b.ColorScaleMode
+		b.dst.DrawTriangles(b.vertices, b.indices, b.Texture, &b.op)
+	}
+	b.drawCalls++
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+}
+
+// End flushes the remaining quads.
+func (b *Batch) End() {
+	b.Flush()
+	b.dst = nil
+}
+
+func (b *Batch) Vertices() []ebiten.Vertex {
+	return b.vertices
+}
+
+func (b *Batch) Indices() []uint16 {
+	return b.indices
+}
+
+// DrawCalls returns the number of flushes since Begin.
+func (b *Batch) DrawCalls() int {
+	return b.drawCalls
+}
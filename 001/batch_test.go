@@ -0,0 +1,148 @@
+package main
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func newTestRenderer() *Renderer {
+	r := &Renderer{camera: NewCamera()}
+	r.viewProj = r.camera.ViewProjectionMat4(float64(screenWidth) / screenHeight)
+	return r
+}
+
+func TestBatchParticles(t *testing.T) {
+	r := newTestRenderer()
+	b := &Batch{Src: image.Rect(0, 0, 80, 80)}
+	particles := []*Particle{
+		{loc: NewVec3D(0, 0, 0), radius: 40, lifeSpan: 100},
+		{loc: NewVec3D(100, -50, 0), radius: 20, lifeSpan: 100, age: 50},
+	}
+
+	b.Begin(nil)
+	for _, p := range particles {
+		r.addParticle(b, p, p.radius*p.AgePer()*0.01)
+	}
+
+	vs, is := b.Vertices(), b.Indices()
+	if len(vs) != 8 || len(is) != 12 {
+		t.Fatalf("got %d vertices and %d indices, want 8 and 12", len(vs), len(is))
+	}
+
+	type want struct {
+		dstX, dstY, srcX, srcY float32
+		r, g, b, a             float32
+	}
+	wants := []want{
+		// radius 40 * diam 0.4 = 16px at the center of the screen
+		{292, 292, 0, 0, 1, 0.75, 0, 0.1},
+		{308, 292, 80, 0, 1, 0.75, 0, 0.1},
+		{292, 308, 0, 80, 1, 0.75, 0, 0.1},
+		{308, 308, 80, 80, 1, 0.75, 0, 0.1},
+		// radius 20 * diam 0.1 = 2px, half way through its life
+		{399, 249, 0, 0, 0.5, 0.375, 0.5, 0.1},
+		{401, 249, 80, 0, 0.5, 0.375, 0.5, 0.1},
+		{399, 251, 0, 80, 0.5, 0.375, 0.5, 0.1},
+		{401, 251, 80, 80, 0.5, 0.375, 0.5, 0.1},
+	}
+	for i, w := range wants {
+		v := vs[i]
+		got := want{v.DstX, v.DstY, v.SrcX, v.SrcY, v.ColorR, v.ColorG, v.ColorB, v.ColorA}
+		if !near32(got.dstX, w.dstX) || !near32(got.dstY, w.dstY) ||
+			got.srcX != w.srcX || got.srcY != w.srcY ||
+			!near32(got.r, w.r) || !near32(got.g, w.g) || !near32(got.b, w.b) || !near32(got.a, w.a) {
+			t.Errorf("vertex %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	wantIndices := []uint16{0, 1, 2, 1, 3, 2, 4, 5, 6, 5, 7, 6}
+	for i := range wantIndices {
+		if is[i] != wantIndices[i] {
+			t.Fatalf("indices = %v, want %v", is, wantIndices)
+		}
+	}
+
+	b.End()
+	if b.DrawCalls() != 1 {
+		t.Errorf("DrawCalls = %d, want 1", b.DrawCalls())
+	}
+	if len(b.Vertices()) != 0 || len(b.Indices()) != 0 {
+		t.Errorf("buffers are not reset after End")
+	}
+}
+
+func TestBatchTrail(t *testing.T) {
+	r := newTestRenderer()
+	b := &Batch{Src: image.Rect(1, 1, 2, 2)}
+	p := &Particle{trail: []Vec3D{
+		NewVec3D(0, 0, 0),
+		NewVec3D(10, 0, 0),
+		NewVec3D(20, 0, 0),
+	}}
+
+	b.Begin(nil)
+	r.addParticleTrail(b, p)
+	vs, is := b.Vertices(), b.Indices()
+	if len(vs) != 8 || len(is) != 12 {
+		t.Fatalf("got %d vertices and %d indices, want 8 and 12", len(vs), len(is))
+	}
+
+	for seg := 0; seg < 2; seg++ {
+		tt := float32(seg+1) / 3
+		halfWidth := (tt*4 + 0.4) / 2
+		x1, x2 := float32(300+seg*10), float32(310+seg*10)
+		corners := [][2]float32{
+			{x1, 300 + halfWidth},
+			{x2, 300 + halfWidth},
+			{x1, 300 - halfWidth},
+			{x2, 300 - halfWidth},
+		}
+		for i, c := range corners {
+			v := vs[seg*4+i]
+			if !near32(v.DstX, c[0]) || !near32(v.DstY, c[1]) {
+				t.Errorf("segment %d corner %d = (%v, %v), want (%v, %v)", seg, i, v.DstX, v.DstY, c[0], c[1])
+			}
+			if v.ColorR != 1 || v.ColorG != 0.5 || !near32(v.ColorB, 1-tt) || !near32(v.ColorA, tt*0.9+0.1) {
+				t.Errorf("segment %d corner %d color = (%v, %v, %v, %v)", seg, i, v.ColorR, v.ColorG, v.ColorB, v.ColorA)
+			}
+		}
+	}
+	b.End()
+}
+
+func TestBatchSkipsDegenerateSegments(t *testing.T) {
+	b := &Batch{}
+	b.Begin(nil)
+	b.AddSegment(10, 10, 10, 10, 2, 1, 1, 1, 1)
+	if len(b.Vertices()) != 0 {
+		t.Errorf("zero length segment added %d vertices", len(b.Vertices()))
+	}
+	b.End()
+	if b.DrawCalls() != 0 {
+		t.Errorf("empty batch DrawCalls = %d, want 0", b.DrawCalls())
+	}
+}
+
+func TestBatchFlushesWhenFull(t *testing.T) {
+	b := &Batch{}
+	b.Begin(nil)
+	quads := maxBatchVertices/4 + 1
+	for i := 0; i < quads; i++ {
+		b.AddQuad(0, 0, 1, 1, 1, 1, 1, 1)
+	}
+	if len(b.Vertices()) != 4 {
+		t.Errorf("after overflow: %d vertices pending, want 4", len(b.Vertices()))
+	}
+	if b.Indices()[0] != 0 {
+		t.Errorf("indices restart at %d after flush, want 0", b.Indices()[0])
+	}
+	b.End()
+	if b.DrawCalls() != 2 {
+		t.Errorf("DrawCalls = %d, want 2", b.DrawCalls())
+	}
+}
+
+func near32(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-3
+}
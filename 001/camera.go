@@ -0,0 +1,47 @@
+package main
+
+import "math"
+
+// Camera orbits around the origin at Distance, looking at it.
+// +y points down on the screen just like the particles' world.
+type Camera struct {
+	Distance   float64
+	Yaw, Pitch float64 // radians
+	FOV        float64 // vertical field of view in radians
+	Near, Far  float64
+}
+
+// NewCamera returns a camera that shows the z=0 plane at 1:1 scale,
+// which is what the sketch looked like with its original zFar=1000.
+func NewCamera() *Camera {
+	const distance = 1000
+	return &Camera{
+		Distance: distance,
+		FOV:      2 * math.Atan(screenHeight/2/float64(distance)),
+		Near:     1,
+		Far:      distance * 5,
+	}
+}
+
+func (c *Camera) ViewMat4() Mat4 {
+	return TranslationMat4(0, 0, -c.Distance).
+		Mul(RotationXMat4(c.Pitch)).
+		Mul(RotationYMat4(c.Yaw))
+}
+
+func (c *Camera) ViewProjectionMat4(aspect float64) Mat4 {
+	return PerspectiveMat4(c.FOV, aspect, c.Near, c.Far).Mul(c.ViewMat4())
+}
+
+// Project converts v to screen coordinates using the view projection
+// matrix m. perspective is the scale of v relative to an object at the
+// origin and is 0 when v is behind the camera.
+func (c *Camera) Project(m Mat4, v Vec3D, width, height float64) (x, y, perspective float64) {
+	p, w := m.Transform(v)
+	if w <= 0 {
+		return 0, 0, 0
+	}
+	x = width/2 + p.x/w*width/2
+	y = height/2 + p.y/w*height/2
+	return x, y, c.Distance / w
+}
@@ -0,0 +1,55 @@
+package main
+
+import "math"
+
+// Curve is a value that changes over time. t is the number of frames
+// since the emitter started emitting.
+type Curve interface {
+	At(t float64) float64
+}
+
+// ConstantCurve always returns Value.
+type ConstantCurve struct {
+	Value float64
+}
+
+func (c ConstantCurve) At(t float64) float64 {
+	return c.Value
+}
+
+// RampCurve goes linearly from From to To over Duration frames and then
+// stays at To.
+type RampCurve struct {
+	From, To float64
+	Duration float64
+}
+
+func (c RampCurve) At(t float64) float64 {
+	if c.Duration <= 0 || t >= c.Duration {
+		return c.To
+	}
+	if t <= 0 {
+		return c.From
+	}
+	return c.From + (c.To-c.From)*t/c.Duration
+}
+
+// BurstCurve returns Count on the first frame and then every Interval
+// frames, and 0 otherwise. A zero Interval means a single burst.
+type BurstCurve struct {
+	Count    float64
+	Interval float64
+}
+
+func (c BurstCurve) At(t float64) float64 {
+	if t == 0 {
+		return c.Count
+	}
+	if c.Interval <= 0 || t < 0 {
+		return 0
+	}
+	if math.Mod(t, c.Interval) == 0 {
+		return c.Count
+	}
+	return 0
+}
@@ -6,6 +6,39 @@ type Emitter struct {
 	loc        Vec3D
 	vel        Vec3D
 	velToMouse Vec3D
+	config     *ParticleConfig
+
+	// Shape is where particles are spawned around the emitter.
+	Shape Shape
+	// Speed is the initial speed along the direction given by Shape.
+	Speed float64
+	// Rate is the number of particles emitted per frame.
+	Rate Curve
+	// Lifetime is the base life span of a spawned particle in frames.
+	// LifetimeJitter adds a random amount in [0, LifetimeJitter).
+	Lifetime       Curve
+	LifetimeJitter float64
+
+	rand  *rand.Rand
+	time  float64
+	carry float64
+}
+
+// NewEmitter creates an emitter with the original look of the sketch.
+// All randomness is driven by r.
+func NewEmitter(r *rand.Rand) *Emitter {
+	return &Emitter{
+		Shape:          BoxShape{HalfSize: NewVec3D(2.5, 2.5, 2.5)},
+		Rate:           ConstantCurve{Value: 10},
+		Lifetime:       ConstantCurve{Value: 20},
+		LifetimeJitter: 60,
+		rand:           r,
+	}
+}
+
+// SetConfig sets the physics used by particles emitted from now on.
+func (e *Emitter) SetConfig(c *ParticleConfig) {
+	e.config = c
 }
 
 func (e *Emitter) Move(toX, toY float64) {
@@ -14,16 +47,54 @@ func (e *Emitter) Move(toX, toY float64) {
 	e.loc.AddSelf(e.vel)
 }
 
+// Reset restarts the rate and lifetime curves from time 0.
+func (e *Emitter) Reset() {
+	e.time = 0
+	e.carry = 0
+}
+
+// Update advances the emitter by one frame and returns the particles
+// emitted according to Rate. Fractional rates are carried over to
+// the following frames.
+func (e *Emitter) Update() []*Particle {
+	rate := 0.0
+	if e.Rate != nil {
+		rate = e.Rate.At(e.time)
+	}
+	e.carry += rate
+	n := int(e.carry)
+	if n < 0 {
+		n = 0
+	}
+	e.carry -= float64(n)
+
+	particles := e.Emit(uint(n))
+	e.time++
+	return particles
+}
+
 func (e *Emitter) Emit(amount uint) []*Particle {
-	particles := make([]*Particle, 0)
+	if e.rand == nil {
+		e.rand = rand.New(rand.NewSource(1))
+	}
+	rnd := e.rand
+	shape := e.Shape
+	if shape == nil {
+		shape = PointShape{}
+	}
+	lifetime := 0.0
+	if e.Lifetime != nil {
+		lifetime = e.Lifetime.At(e.time)
+	}
+
+	particles := make([]*Particle, 0, amount)
 	for i := uint(0); i < amount; i++ {
-		r := rand.Float64()*60 + 20
-		loc := e.loc.Clone().Add(
-			NewVec3D(rand.Float64()-0.5, rand.Float64()-0.5, rand.Float64()-0.5).Scale(5.0),
-		)
+		r := lifetime + rnd.Float64()*e.LifetimeJitter
+		offset, dir := shape.Sample(rnd)
+		loc := e.loc.Clone().Add(offset)
 		vel := e.vel.Scale(.5).Add(
-			NewVec3D(rand.Float64()-0.5, rand.Float64()-0.5, rand.Float64()-0.5).Scale(10.0),
-		)
+			NewVec3D(rnd.Float64()-0.5, rnd.Float64()-0.5, rnd.Float64()-0.5).Scale(10.0),
+		).Add(dir.Scale(e.Speed))
 
 		particles = append(particles, &Particle{
 			age:      0,
@@ -31,6 +102,7 @@ func (e *Emitter) Emit(amount uint) []*Particle {
 			radius:   r,
 			loc:      loc,
 			vel:      vel,
+			config:   e.config,
 		})
 	}
 	return particles
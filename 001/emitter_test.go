@@ -0,0 +1,164 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEmitterShapes(t *testing.T) {
+	const eps = 1e-9
+	tests := []struct {
+		name   string
+		shape  Shape
+		inside func(v Vec3D) bool
+	}{
+		{
+			name:   "point",
+			shape:  PointShape{},
+			inside: func(v Vec3D) bool { return v.Length() < eps },
+		},
+		{
+			name:   "sphere",
+			shape:  SphereShape{Radius: 30},
+			inside: func(v Vec3D) bool { return v.Length() <= 30+eps },
+		},
+		{
+			name:  "disc",
+			shape: DiscShape{Radius: 30},
+			inside: func(v Vec3D) bool {
+				return v.Length() <= 30+eps && math.Abs(v.z) < eps
+			},
+		},
+		{
+			name:  "box",
+			shape: BoxShape{HalfSize: NewVec3D(10, 20, 5)},
+			inside: func(v Vec3D) bool {
+				return math.Abs(v.x) <= 10 && math.Abs(v.y) <= 20 && math.Abs(v.z) <= 5
+			},
+		},
+		{
+			name:  "line",
+			shape: LineShape{From: NewVec3D(-10, 0, 0), To: NewVec3D(10, 20, 0)},
+			inside: func(v Vec3D) bool {
+				return v.x >= -10 && v.x <= 10 && math.Abs(v.y-(v.x+10)) < eps && math.Abs(v.z) < eps
+			},
+		},
+		{
+			name:  "cone",
+			shape: ConeShape{Direction: NewVec3D(0, -2, 0), Angle: math.Pi / 6, Length: 40},
+			inside: func(v Vec3D) bool {
+				l := v.Length()
+				if l < eps {
+					return true
+				}
+				// angle between v and the axis (0, -1, 0)
+				return l <= 40+eps && -v.y/l >= math.Cos(math.Pi/6)-eps
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEmitter(rand.New(rand.NewSource(1)))
+			e.loc = NewVec3D(100, -50, 3)
+			e.Shape = tt.shape
+			for _, p := range e.Emit(1000) {
+				offset := p.loc.Sub(e.loc)
+				if !tt.inside(offset) {
+					t.Fatalf("spawned outside of the shape: offset %v", offset)
+				}
+			}
+		})
+	}
+}
+
+func TestConeShapeDirection(t *testing.T) {
+	s := ConeShape{Direction: NewVec3D(1, 0, 0), Angle: 0.2, Length: 10}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		_, dir := s.Sample(r)
+		if math.Abs(dir.Length()-1) > 1e-9 {
+			t.Fatalf("direction is not a unit vector: %v", dir)
+		}
+		if dir.x < math.Cos(0.2)-1e-9 {
+			t.Fatalf("direction outside of the cone: %v", dir)
+		}
+	}
+}
+
+func TestEmitterRateCurves(t *testing.T) {
+	tests := []struct {
+		name string
+		rate Curve
+		want []int
+	}{
+		{
+			name: "constant",
+			rate: ConstantCurve{Value: 10},
+			want: []int{10, 10, 10, 10},
+		},
+		{
+			name: "fractional constant",
+			rate: ConstantCurve{Value: 0.5},
+			want: []int{0, 1, 0, 1},
+		},
+		{
+			name: "ramp",
+			rate: RampCurve{From: 0, To: 4, Duration: 4},
+			want: []int{0, 1, 2, 3, 4, 4},
+		},
+		{
+			name: "single burst",
+			rate: BurstCurve{Count: 50},
+			want: []int{50, 0, 0, 0},
+		},
+		{
+			name: "repeated burst",
+			rate: BurstCurve{Count: 5, Interval: 3},
+			want: []int{5, 0, 0, 5, 0, 0, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEmitter(rand.New(rand.NewSource(1)))
+			e.Rate = tt.rate
+			for frame, want := range tt.want {
+				if got := len(e.Update()); got != want {
+					t.Errorf("frame %d: emitted %d, want %d", frame, got, want)
+				}
+			}
+
+			e.Reset()
+			if got := len(e.Update()); got != tt.want[0] {
+				t.Errorf("after Reset: emitted %d, want %d", got, tt.want[0])
+			}
+		})
+	}
+}
+
+func TestEmitterLifetimeCurve(t *testing.T) {
+	e := NewEmitter(rand.New(rand.NewSource(1)))
+	e.Rate = ConstantCurve{Value: 1}
+	e.Lifetime = RampCurve{From: 10, To: 30, Duration: 2}
+	e.LifetimeJitter = 0
+	want := []float64{10, 20, 30, 30}
+	for frame, w := range want {
+		ps := e.Update()
+		if ps[0].lifeSpan != w {
+			t.Errorf("frame %d: lifeSpan %v, want %v", frame, ps[0].lifeSpan, w)
+		}
+	}
+}
+
+func TestEmitterSeed(t *testing.T) {
+	a := NewEmitter(rand.New(rand.NewSource(42)))
+	b := NewEmitter(rand.New(rand.NewSource(42)))
+	a.Shape = SphereShape{Radius: 10}
+	b.Shape = SphereShape{Radius: 10}
+	pa, pb := a.Emit(100), b.Emit(100)
+	for i := range pa {
+		if pa[i].loc != pb[i].loc || pa[i].vel != pb[i].vel || pa[i].lifeSpan != pb[i].lifeSpan {
+			t.Fatalf("particle %d differs with the same seed", i)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import "math"
+
+// Force returns the acceleration applied to a particle at loc moving at vel.
+type Force interface {
+	Apply(loc, vel Vec3D) Vec3D
+}
+
+// ConstantForce pushes every particle in the same direction, like wind.
+type ConstantForce struct {
+	Accel Vec3D
+}
+
+func (f ConstantForce) Apply(loc, vel Vec3D) Vec3D {
+	return f.Accel
+}
+
+// VortexForce spins particles around an axis passing through Center.
+type VortexForce struct {
+	Center   Vec3D
+	Axis     Vec3D
+	Strength float64
+}
+
+func (f VortexForce) Apply(loc, vel Vec3D) Vec3D {
+	axisLen := f.Axis.Length()
+	if axisLen == 0 {
+		return Vec3D{}
+	}
+	tangent := f.Axis.Scale(1 / axisLen).Cross(loc.Sub(f.Center))
+	l := tangent.Length()
+	if l == 0 {
+		return Vec3D{}
+	}
+	return tangent.Scale(f.Strength / l)
+}
+
+// AttractorForce pulls particles toward Center with an inverse square falloff.
+// MinDistance keeps the force finite when a particle passes through Center.
+type AttractorForce struct {
+	Center      Vec3D
+	Strength    float64
+	MinDistance float64
+}
+
+func (f AttractorForce) Apply(loc, vel Vec3D) Vec3D {
+	dir := f.Center.Sub(loc)
+	d := dir.Length()
+	if d == 0 {
+		return Vec3D{}
+	}
+	r := math.Max(d, f.MinDistance)
+	return dir.Scale(f.Strength / (r * r) / d)
+}
+
+// NoiseForce is a cheap pseudo noise field based on the sum of coordinates.
+// ref to https://beautifl.net/run/192/
+type NoiseForce struct {
+	Amplitude float64
+	Frequency float64
+}
+
+func (f NoiseForce) Apply(loc, vel Vec3D) Vec3D {
+	d := (loc.x + loc.y + loc.z) * f.Frequency
+	return Vec3D{
+		math.Cos(d) * f.Amplitude,
+		math.Sin(d) * f.Amplitude,
+		math.Cos(d) * -f.Amplitude,
+	}
+}
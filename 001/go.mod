@@ -4,6 +4,8 @@ go 1.21.0
 
 require (
 	github.com/demouth/colorgradient-go v0.0.0-20240229125148-56f55d6f31e2
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
+	github.com/demouth/ebitengine-sketch/lib/recorder v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.6.6
 )
 
@@ -16,3 +18,7 @@ require (
 	golang.org/x/sync v0.3.0 // indirect
 	golang.org/x/sys v0.12.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
+
+replace github.com/demouth/ebitengine-sketch/lib/recorder => ../lib/recorder
@@ -2,11 +2,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	_ "image/png"
 	"log"
 	"math"
 
+	"github.com/demouth/ebitengine-sketch/lib/recorder"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -16,59 +19,8 @@ const (
 	screenWidth  = 600
 	screenHeight = 600
 	maxAngle     = 256
-	ALLOWFLOOR   = true
-	ALLOWGRAVITY = true
-	floorLevel   = 200
-	gravity      = .35
 )
 
-type Particle struct {
-	vel      Vec3D // velocity
-	loc      Vec3D // position
-	age      float64
-	lifeSpan float64
-	radius   float64
-	trail    []Vec3D
-}
-
-func (p *Particle) Move() {
-	if ALLOWGRAVITY {
-		p.vel.y += gravity
-	}
-	if ALLOWFLOOR {
-		if p.loc.y > floorLevel {
-			p.loc.y = floorLevel
-			p.vel.Scale(0.75)
-			p.vel.y *= -0.5
-		}
-	}
-
-	// ref to https://beautifl.net/run/192/
-	d := p.loc.x + p.loc.y + p.loc.z
-	p.vel.x += math.Cos(d) * 0.5
-	p.vel.y += math.Sin(d)*0.5 + 0.35
-	p.vel.z += math.Cos(d) * -0.5
-
-	p.loc.AddSelf(p.vel)
-	p.trail = append(p.trail, p.loc.Clone())
-	const max = 10
-	if len(p.trail) > max {
-		p.trail = p.trail[len(p.trail)-max:]
-	}
-	p.age += 1
-}
-
-func (p *Particle) IsDead() bool {
-	return p.AgePer() <= 0
-}
-
-// range from 1.0 (birth) to 0.0 (death)
-func (p *Particle) AgePer() float64 {
-	a := p.age / p.lifeSpan
-	agePer := 1 - a
-	return agePer
-}
-
 type Game struct {
 	touchIDs      []ebiten.TouchID
 	particles     []*Particle
@@ -76,6 +28,7 @@ type Game struct {
 	renderer      *Renderer
 	emitter       *Emitter
 	isMouseDown   bool
+	isEmitting    bool
 	isTouching    bool
 	isTouchDevice bool
 }
@@ -84,7 +37,8 @@ func (g *Game) init() {
 	defer func() {
 		g.inited = true
 	}()
-	g.emitter = &Emitter{}
+	g.emitter = NewEmitter(seed.New())
+	g.emitter.SetConfig(DefaultParticleConfig())
 	g.renderer = NewRenderer()
 }
 
@@ -93,6 +47,15 @@ func (g *Game) Update() error {
 		g.init()
 	}
 
+	g.updateCamera()
+	if inpututil.IsKeyJustPressed(ebiten.KeyB) {
+		if g.renderer.BlendMode() == BlendAlpha {
+			g.renderer.SetBlendMode(BlendAdditive)
+		} else {
+			g.renderer.SetBlendMode(BlendAlpha)
+		}
+	}
+
 	g.isTouching = false
 	g.touchIDs = ebiten.AppendTouchIDs(g.touchIDs[:0])
 	for _ = range g.touchIDs {
@@ -118,8 +81,12 @@ func (g *Game) Update() error {
 		g.emitter.Move(float64(mouseX), float64(mouseY))
 	}
 	if g.isTouching || g.isMouseDown {
-		g.particles = append(g.particles, g.emitter.Emit(10)...)
+		if !g.isEmitting {
+			g.emitter.Reset()
+		}
+		g.particles = append(g.particles, g.emitter.Update()...)
 	}
+	g.isEmitting = g.isTouching || g.isMouseDown
 
 	newParticles := make([]*Particle, 0)
 	for _, p := range g.particles {
@@ -133,13 +100,37 @@ func (g *Game) Update() error {
 	return nil
 }
 
+// updateCamera rotates the camera with the arrow keys and changes
+// the field of view with the mouse wheel.
+func (g *Game) updateCamera() {
+	const rotSpeed = math.Pi / 180
+	c := g.renderer.Camera()
+	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+		c.Yaw -= rotSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+		c.Yaw += rotSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+		c.Pitch -= rotSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+		c.Pitch += rotSpeed
+	}
+	_, wy := ebiten.Wheel()
+	c.FOV -= wy * math.Pi / 180
+	c.FOV = math.Max(math.Pi/18, math.Min(math.Pi*2/3, c.FOV))
+}
+
 func (g *Game) Draw(screen *ebiten.Image) {
 	g.renderer.Draw(screen, g.particles, g.emitter)
 
 	msg := fmt.Sprintf(
-		"particles: %d\nFPS: %0.2f",
+		"particles: %d\nblend: %s (B)\nFPS: %0.2f\n%s",
 		len(g.particles),
+		g.renderer.BlendMode(),
 		ebiten.ActualFPS(),
+		seed.Label(),
 	)
 	ebitenutil.DebugPrint(screen, msg)
 }
@@ -148,11 +139,19 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return screenWidth, screenHeight
 }
 
+var recordFormat = flag.String("record", "gif", "the format of recordings started with F9: gif or png")
+
 func main() {
+	flag.Parse()
+	format, err := recorder.ParseFormat(*recordFormat)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("Ebitengine Demo")
 	ebiten.SetWindowResizable(true)
-	if err := ebiten.RunGame(&Game{}); err != nil {
+	if err := ebiten.RunGame(recorder.New(&Game{}, recorder.Options{Format: format})); err != nil {
 		log.Fatal(err)
 	}
 }
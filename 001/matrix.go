@@ -0,0 +1,133 @@
+package main
+
+import "math"
+
+// Mat4 is a 4x4 matrix stored in row-major order.
+// Vectors are treated as columns, so a.Mul(b) applies b first and then a.
+type Mat4 [16]float64
+
+func IdentityMat4() Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+func TranslationMat4(x, y, z float64) Mat4 {
+	return Mat4{
+		1, 0, 0, x,
+		0, 1, 0, y,
+		0, 0, 1, z,
+		0, 0, 0, 1,
+	}
+}
+
+func ScaleMat4(x, y, z float64) Mat4 {
+	return Mat4{
+		x, 0, 0, 0,
+		0, y, 0, 0,
+		0, 0, z, 0,
+		0, 0, 0, 1,
+	}
+}
+
+func RotationXMat4(angle float64) Mat4 {
+	s, c := math.Sincos(angle)
+	return Mat4{
+		1, 0, 0, 0,
+		0, c, -s, 0,
+		0, s, c, 0,
+		0, 0, 0, 1,
+	}
+}
+
+func RotationYMat4(angle float64) Mat4 {
+	s, c := math.Sincos(angle)
+	return Mat4{
+		c, 0, s, 0,
+		0, 1, 0, 0,
+		-s, 0, c, 0,
+		0, 0, 0, 1,
+	}
+}
+
+func RotationZMat4(angle float64) Mat4 {
+	s, c := math.Sincos(angle)
+	return Mat4{
+		c, -s, 0, 0,
+		s, c, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// RotationMat4 rotates by angle radians around axis (right-handed).
+func RotationMat4(axis Vec3D, angle float64) Mat4 {
+	a := axis.Normalize()
+	s, c := math.Sincos(angle)
+	t := 1 - c
+	x, y, z := a.x, a.y, a.z
+	return Mat4{
+		t*x*x + c, t*x*y - s*z, t*x*z + s*y, 0,
+		t*x*y + s*z, t*y*y + c, t*y*z - s*x, 0,
+		t*x*z - s*y, t*y*z + s*x, t*z*z + c, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// PerspectiveMat4 is an OpenGL style projection looking down -z.
+// fovY is the vertical field of view in radians.
+func PerspectiveMat4(fovY, aspect, near, far float64) Mat4 {
+	f := 1 / math.Tan(fovY/2)
+	return Mat4{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, (far + near) / (near - far), 2 * far * near / (near - far),
+		0, 0, -1, 0,
+	}
+}
+
+func (m Mat4) Mul(n Mat4) Mat4 {
+	var r Mat4
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 4; col++ {
+			var sum float64
+			for k := 0; k < 4; k++ {
+				sum += m[row*4+k] * n[k*4+col]
+			}
+			r[row*4+col] = sum
+		}
+	}
+	return r
+}
+
+func (m Mat4) Transpose() Mat4 {
+	var r Mat4
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 4; col++ {
+			r[col*4+row] = m[row*4+col]
+		}
+	}
+	return r
+}
+
+// Transform multiplies the point v (w=1) and returns the result and its w.
+// No perspective division is done.
+func (m Mat4) Transform(v Vec3D) (Vec3D, float64) {
+	return Vec3D{
+		m[0]*v.x + m[1]*v.y + m[2]*v.z + m[3],
+		m[4]*v.x + m[5]*v.y + m[6]*v.z + m[7],
+		m[8]*v.x + m[9]*v.y + m[10]*v.z + m[11],
+	}, m[12]*v.x + m[13]*v.y + m[14]*v.z + m[15]
+}
+
+// TransformDir multiplies the direction v (w=0), ignoring translation.
+func (m Mat4) TransformDir(v Vec3D) Vec3D {
+	return Vec3D{
+		m[0]*v.x + m[1]*v.y + m[2]*v.z,
+		m[4]*v.x + m[5]*v.y + m[6]*v.z,
+		m[8]*v.x + m[9]*v.y + m[10]*v.z,
+	}
+}
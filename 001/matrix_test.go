@@ -0,0 +1,158 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func matNear(a, b Mat4) bool {
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMat4Mul(t *testing.T) {
+	a := Mat4{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+		13, 14, 15, 16,
+	}
+	b := Mat4{
+		16, 15, 14, 13,
+		12, 11, 10, 9,
+		8, 7, 6, 5,
+		4, 3, 2, 1,
+	}
+	want := Mat4{
+		80, 70, 60, 50,
+		240, 214, 188, 162,
+		400, 358, 316, 274,
+		560, 502, 444, 386,
+	}
+	if got := a.Mul(b); !matNear(got, want) {
+		t.Errorf("Mul = %v, want %v", got, want)
+	}
+	if got := a.Mul(IdentityMat4()); !matNear(got, a) {
+		t.Errorf("a * I = %v, want %v", got, a)
+	}
+	if got := IdentityMat4().Mul(a); !matNear(got, a) {
+		t.Errorf("I * a = %v, want %v", got, a)
+	}
+	if got := a.Transpose().Transpose(); !matNear(got, a) {
+		t.Errorf("Transpose twice = %v, want %v", got, a)
+	}
+}
+
+func TestMat4Transform(t *testing.T) {
+	v := NewVec3D(1, 2, 3)
+	tests := []struct {
+		name string
+		m    Mat4
+		want Vec3D
+	}{
+		{"identity", IdentityMat4(), v},
+		{"translation", TranslationMat4(10, -20, 30), NewVec3D(11, -18, 33)},
+		{"scale", ScaleMat4(2, 3, -1), NewVec3D(2, 6, -3)},
+		{"rotate x", RotationXMat4(math.Pi / 2), NewVec3D(1, -3, 2)},
+		{"rotate y", RotationYMat4(math.Pi / 2), NewVec3D(3, 2, -1)},
+		{"rotate z", RotationZMat4(math.Pi / 2), NewVec3D(-2, 1, 3)},
+		{"rotate axis x", RotationMat4(NewVec3D(5, 0, 0), math.Pi/2), NewVec3D(1, -3, 2)},
+		{"rotate axis y", RotationMat4(NewVec3D(0, 1, 0), math.Pi/2), NewVec3D(3, 2, -1)},
+		{"rotate axis z", RotationMat4(NewVec3D(0, 0, 1), math.Pi/2), NewVec3D(-2, 1, 3)},
+		{"rotate diagonal", RotationMat4(NewVec3D(1, 1, 1), 2*math.Pi/3), NewVec3D(3, 1, 2)},
+		{"translate after rotate", TranslationMat4(1, 0, 0).Mul(RotationZMat4(math.Pi)), NewVec3D(0, -2, 3)},
+	}
+	for _, tt := range tests {
+		got, w := tt.m.Transform(v)
+		if !vecNear(got, tt.want) || math.Abs(w-1) > epsilon {
+			t.Errorf("%s: Transform = %v (w=%v), want %v (w=1)", tt.name, got, w, tt.want)
+		}
+	}
+
+	if got := TranslationMat4(10, 10, 10).TransformDir(v); !vecNear(got, v) {
+		t.Errorf("TransformDir ignores translation: got %v, want %v", got, v)
+	}
+}
+
+func TestRotationKeepsLength(t *testing.T) {
+	v := NewVec3D(3, -4, 12)
+	for i := 0; i < 16; i++ {
+		a := float64(i) * math.Pi / 8
+		m := RotationMat4(NewVec3D(1, -2, 0.5), a)
+		got, _ := m.Transform(v)
+		if math.Abs(got.Length()-13) > epsilon {
+			t.Errorf("angle %v: length = %v, want 13", a, got.Length())
+		}
+		back, _ := RotationMat4(NewVec3D(1, -2, 0.5), -a).Mul(m).Transform(v)
+		if !vecNear(back, v) {
+			t.Errorf("angle %v: inverse rotation = %v, want %v", a, back, v)
+		}
+	}
+}
+
+func TestPerspectiveMat4(t *testing.T) {
+	const near, far = 1.0, 100.0
+	m := PerspectiveMat4(math.Pi/2, 2, near, far)
+
+	tests := []struct {
+		name  string
+		v     Vec3D
+		wantX float64
+		wantY float64
+		wantZ float64
+	}{
+		{"near plane", NewVec3D(0, 0, -near), 0, 0, -1},
+		{"far plane", NewVec3D(0, 0, -far), 0, 0, 1},
+		{"right edge", NewVec3D(20, 0, -10), 1, 0, (far + near) / (far - near) * (1 - 2*far*near/(far+near)/10)},
+		{"top edge", NewVec3D(0, 10, -10), 0, 1, (far + near) / (far - near) * (1 - 2*far*near/(far+near)/10)},
+	}
+	for _, tt := range tests {
+		p, w := m.Transform(tt.v)
+		if math.Abs(w+tt.v.z) > epsilon {
+			t.Errorf("%s: w = %v, want %v", tt.name, w, -tt.v.z)
+		}
+		got := p.Scale(1 / w)
+		want := NewVec3D(tt.wantX, tt.wantY, tt.wantZ)
+		if !vecNear(got, want) {
+			t.Errorf("%s: ndc = %v, want %v", tt.name, got, want)
+		}
+	}
+}
+
+func TestCameraProject(t *testing.T) {
+	c := NewCamera()
+	m := c.ViewProjectionMat4(float64(screenWidth) / screenHeight)
+
+	// the default camera matches the original zFar=1000 projection
+	for _, v := range []Vec3D{
+		NewVec3D(0, 0, 0),
+		NewVec3D(100, -50, 0),
+		NewVec3D(-30, 80, 200),
+		NewVec3D(10, 10, -500),
+	} {
+		perspective := 1000 / (1000 - v.z)
+		wantX := v.x*perspective + screenWidth/2
+		wantY := v.y*perspective + screenHeight/2
+		x, y, p := c.Project(m, v, screenWidth, screenHeight)
+		if math.Abs(x-wantX) > 1e-6 || math.Abs(y-wantY) > 1e-6 || math.Abs(p-perspective) > 1e-9 {
+			t.Errorf("Project(%v) = (%v, %v, %v), want (%v, %v, %v)", v, x, y, p, wantX, wantY, perspective)
+		}
+	}
+
+	// behind the camera
+	if _, _, p := c.Project(m, NewVec3D(0, 0, 2000), screenWidth, screenHeight); p != 0 {
+		t.Errorf("perspective behind the camera = %v, want 0", p)
+	}
+
+	// a quarter turn brings the x axis onto the view axis
+	c.Yaw = math.Pi / 2
+	m = c.ViewProjectionMat4(float64(screenWidth) / screenHeight)
+	x, y, p := c.Project(m, NewVec3D(0, 0, 100), screenWidth, screenHeight)
+	if math.Abs(x-(screenWidth/2+100)) > 1e-6 || math.Abs(y-screenHeight/2) > 1e-6 || math.Abs(p-1) > 1e-9 {
+		t.Errorf("rotated Project = (%v, %v, %v)", x, y, p)
+	}
+}
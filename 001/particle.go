@@ -0,0 +1,92 @@
+package main
+
+// ParticleConfig describes the physics applied to a particle on every Move.
+type ParticleConfig struct {
+	// Gravity is added to the velocity every step.
+	Gravity Vec3D
+
+	// Floor is a horizontal plane at y = FloorLevel (+y points down).
+	// Particles that fall below it bounce back with FloorRestitution.
+	FloorEnabled     bool
+	FloorLevel       float64
+	FloorRestitution float64
+
+	// Drag is the fraction of velocity lost every step (0 = no drag).
+	Drag float64
+
+	// Forces are evaluated in order after the floor check.
+	Forces []Force
+
+	// TrailLength is the number of past positions kept for drawing.
+	TrailLength int
+}
+
+// DefaultParticleConfig returns the configuration that reproduces the
+// original look of the sketch.
+func DefaultParticleConfig() *ParticleConfig {
+	return &ParticleConfig{
+		Gravity:          NewVec3D(0, .35, 0),
+		FloorEnabled:     true,
+		FloorLevel:       200,
+		FloorRestitution: 0.5,
+		Forces: []Force{
+			NoiseForce{Amplitude: 0.5, Frequency: 1},
+			ConstantForce{Accel: NewVec3D(0, .35, 0)},
+		},
+		TrailLength: 10,
+	}
+}
+
+var defaultParticleConfig = DefaultParticleConfig()
+
+type Particle struct {
+	vel      Vec3D // velocity
+	loc      Vec3D // position
+	age      float64
+	lifeSpan float64
+	radius   float64
+	trail    []Vec3D
+	config   *ParticleConfig
+}
+
+func (p *Particle) Move() {
+	c := p.config
+	if c == nil {
+		c = defaultParticleConfig
+	}
+
+	p.vel.AddSelf(c.Gravity)
+	if c.FloorEnabled {
+		if p.loc.y > c.FloorLevel {
+			p.loc.y = c.FloorLevel
+			p.vel.y *= -c.FloorRestitution
+		}
+	}
+
+	for _, f := range c.Forces {
+		p.vel.AddSelf(f.Apply(p.loc, p.vel))
+	}
+	if c.Drag != 0 {
+		p.vel.ScaleSelf(1 - c.Drag)
+	}
+
+	p.loc.AddSelf(p.vel)
+	if c.TrailLength > 0 {
+		p.trail = append(p.trail, p.loc.Clone())
+		if len(p.trail) > c.TrailLength {
+			p.trail = p.trail[len(p.trail)-c.TrailLength:]
+		}
+	}
+	p.age += 1
+}
+
+func (p *Particle) IsDead() bool {
+	return p.AgePer() <= 0
+}
+
+// range from 1.0 (birth) to 0.0 (death)
+func (p *Particle) AgePer() float64 {
+	a := p.age / p.lifeSpan
+	agePer := 1 - a
+	return agePer
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+const epsilon = 1e-9
+
+func vecNear(a, b Vec3D) bool {
+	return math.Abs(a.x-b.x) < epsilon &&
+		math.Abs(a.y-b.y) < epsilon &&
+		math.Abs(a.z-b.z) < epsilon
+}
+
+func TestParticleMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *ParticleConfig
+		loc     Vec3D
+		vel     Vec3D
+		steps   int
+		wantLoc Vec3D
+		wantVel Vec3D
+	}{
+		{
+			name:    "no forces",
+			config:  &ParticleConfig{},
+			vel:     NewVec3D(1, 2, 3),
+			steps:   2,
+			wantLoc: NewVec3D(2, 4, 6),
+			wantVel: NewVec3D(1, 2, 3),
+		},
+		{
+			name:    "gravity",
+			config:  &ParticleConfig{Gravity: NewVec3D(0, 1, 0)},
+			steps:   3,
+			wantLoc: NewVec3D(0, 6, 0),
+			wantVel: NewVec3D(0, 3, 0),
+		},
+		{
+			name: "floor bounce",
+			config: &ParticleConfig{
+				FloorEnabled:     true,
+				FloorLevel:       10,
+				FloorRestitution: 0.5,
+			},
+			loc:     NewVec3D(0, 12, 0),
+			vel:     NewVec3D(0, 4, 0),
+			steps:   1,
+			wantLoc: NewVec3D(0, 8, 0),
+			wantVel: NewVec3D(0, -2, 0),
+		},
+		{
+			name: "floor disabled",
+			config: &ParticleConfig{
+				FloorLevel:       10,
+				FloorRestitution: 0.5,
+			},
+			loc:     NewVec3D(0, 12, 0),
+			vel:     NewVec3D(0, 4, 0),
+			steps:   1,
+			wantLoc: NewVec3D(0, 16, 0),
+			wantVel: NewVec3D(0, 4, 0),
+		},
+		{
+			name:    "drag",
+			config:  &ParticleConfig{Drag: 0.5},
+			vel:     NewVec3D(4, 0, 0),
+			steps:   2,
+			wantLoc: NewVec3D(3, 0, 0),
+			wantVel: NewVec3D(1, 0, 0),
+		},
+		{
+			name: "constant force",
+			config: &ParticleConfig{
+				Gravity: NewVec3D(0, 1, 0),
+				Forces:  []Force{ConstantForce{Accel: NewVec3D(1, 0, 0)}},
+			},
+			steps:   2,
+			wantLoc: NewVec3D(3, 3, 0),
+			wantVel: NewVec3D(2, 2, 0),
+		},
+		{
+			name: "vortex",
+			config: &ParticleConfig{
+				Forces: []Force{VortexForce{Axis: NewVec3D(0, 0, 1), Strength: 2}},
+			},
+			loc:     NewVec3D(1, 0, 0),
+			steps:   1,
+			wantLoc: NewVec3D(1, 2, 0),
+			wantVel: NewVec3D(0, 2, 0),
+		},
+		{
+			name: "attractor",
+			config: &ParticleConfig{
+				Forces: []Force{AttractorForce{Center: NewVec3D(10, 0, 0), Strength: 4, MinDistance: 1}},
+			},
+			steps:   1,
+			wantLoc: NewVec3D(0.04, 0, 0),
+			wantVel: NewVec3D(0.04, 0, 0),
+		},
+		{
+			name: "attractor at center",
+			config: &ParticleConfig{
+				Forces: []Force{AttractorForce{Center: NewVec3D(1, 1, 1), Strength: 4, MinDistance: 1}},
+			},
+			loc:     NewVec3D(1, 1, 1),
+			steps:   1,
+			wantLoc: NewVec3D(1, 1, 1),
+			wantVel: NewVec3D(0, 0, 0),
+		},
+		{
+			name: "noise",
+			config: &ParticleConfig{
+				Forces: []Force{NoiseForce{Amplitude: 0.5, Frequency: 1}},
+			},
+			steps:   1,
+			wantLoc: NewVec3D(0.5, 0, -0.5),
+			wantVel: NewVec3D(0.5, 0, -0.5),
+		},
+		{
+			name:    "default config",
+			config:  DefaultParticleConfig(),
+			steps:   1,
+			wantLoc: NewVec3D(0.5, 0.7, -0.5),
+			wantVel: NewVec3D(0.5, 0.7, -0.5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Particle{loc: tt.loc, vel: tt.vel, lifeSpan: 100, config: tt.config}
+			for i := 0; i < tt.steps; i++ {
+				p.Move()
+			}
+			if !vecNear(p.loc, tt.wantLoc) {
+				t.Errorf("loc = %v, want %v", p.loc, tt.wantLoc)
+			}
+			if !vecNear(p.vel, tt.wantVel) {
+				t.Errorf("vel = %v, want %v", p.vel, tt.wantVel)
+			}
+			if p.age != float64(tt.steps) {
+				t.Errorf("age = %v, want %v", p.age, tt.steps)
+			}
+		})
+	}
+}
+
+func TestParticleTrailLength(t *testing.T) {
+	tests := []struct {
+		trailLength int
+		steps       int
+		want        int
+	}{
+		{0, 5, 0},
+		{3, 2, 2},
+		{3, 5, 3},
+		{10, 20, 10},
+	}
+	for _, tt := range tests {
+		p := &Particle{vel: NewVec3D(1, 0, 0), lifeSpan: 100, config: &ParticleConfig{TrailLength: tt.trailLength}}
+		for i := 0; i < tt.steps; i++ {
+			p.Move()
+		}
+		if len(p.trail) != tt.want {
+			t.Errorf("TrailLength %d after %d steps: len(trail) = %d, want %d", tt.trailLength, tt.steps, len(p.trail), tt.want)
+			continue
+		}
+		if tt.want > 0 && !vecNear(p.trail[len(p.trail)-1], p.loc) {
+			t.Errorf("last trail point = %v, want %v", p.trail[len(p.trail)-1], p.loc)
+		}
+	}
+}
+
+func TestEmitterSetConfig(t *testing.T) {
+	c := &ParticleConfig{TrailLength: 3}
+	e := &Emitter{}
+	e.SetConfig(c)
+	for _, p := range e.Emit(5) {
+		if p.config != c {
+			t.Fatalf("emitted particle does not use the emitter config")
+		}
+	}
+}
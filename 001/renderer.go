@@ -7,7 +7,6 @@ import (
 
 	"github.com/demouth/colorgradient-go"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 type Renderer struct {
@@ -16,14 +15,20 @@ type Renderer struct {
 
 	whiteImage    *ebiten.Image
 	whiteSubImage *ebiten.Image
-	vertices      []ebiten.Vertex
-	indices       []uint16
+
+	trailBatch    *Batch
+	particleBatch *Batch
 
 	op ebiten.DrawImageOptions
+
+	camera   *Camera
+	viewProj Mat4
 }
 
 func NewRenderer() *Renderer {
-	r := &Renderer{}
+	r := &Renderer{
+		camera: NewCamera(),
+	}
 	r.generateParticleImage()
 	r.generateEmitterImage()
 
@@ -31,9 +36,26 @@ func NewRenderer() *Renderer {
 	r.whiteImage.Fill(color.White)
 	r.whiteSubImage = r.whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
 
+	r.trailBatch = NewBatch(r.whiteSubImage)
+
+	// vertex colors scale the premultiplied texture just like ColorScale in DrawImage
+	r.particleBatch = NewBatch(r.particleImage)
+	r.particleBatch.Filter = ebiten.FilterLinear
+	r.particleBatch.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+
 	return r
 }
 
+// SetBlendMode changes how particles and trails are composited.
+func (r *Renderer) SetBlendMode(m BlendMode) {
+	r.trailBatch.Blend = m
+	r.particleBatch.Blend = m
+}
+
+func (r *Renderer) BlendMode() BlendMode {
+	return r.particleBatch.Blend
+}
+
 func (r *Renderer) generateParticleImage() {
 	w := 80
 	h := 80
@@ -113,98 +135,86 @@ func (r *Renderer) generateEmitterImage() {
 
 func (r *Renderer) Draw(screen *ebiten.Image, particles []*Particle, emitter *Emitter) {
 	screen.Fill(color.NRGBA{0x00, 0x00, 0x00, 0xff})
+	r.viewProj = r.camera.ViewProjectionMat4(float64(screenWidth) / screenHeight)
 	r.drawParticlesTrail(screen, particles)
 	r.drawParticles(screen, particles)
-	r.drawEmitter(screen, emitter.loc.x, emitter.loc.y)
+	r.drawEmitter(screen, emitter.loc)
 }
 
-func projectionTo2D(x, y, z float64) (float64, float64, float64) {
-	const zFar float64 = 1000
-	perspective := zFar / (zFar - z)
-	px := x*perspective + screenWidth/2
-	py := y*perspective + screenHeight/2
-	return px, py, perspective
+// Camera returns the camera used to project the particles.
+func (r *Renderer) Camera() *Camera {
+	return r.camera
+}
+
+func (r *Renderer) projectionTo2D(x, y, z float64) (float64, float64, float64) {
+	return r.camera.Project(r.viewProj, NewVec3D(x, y, z), screenWidth, screenHeight)
 }
 
 func (r *Renderer) drawParticlesTrail(screen *ebiten.Image, particles []*Particle) {
+	r.trailBatch.Begin(screen)
 	for _, s := range particles {
-		r.drawParticleTrail(screen, s)
+		r.addParticleTrail(r.trailBatch, s)
 	}
+	r.trailBatch.End()
 }
-func (r *Renderer) drawParticleTrail(screen *ebiten.Image, particle *Particle) {
+
+func (r *Renderer) addParticleTrail(b *Batch, particle *Particle) {
 
 	if len(particle.trail) < 2 {
 		return
 	}
 
 	for i, l := 1, len(particle.trail); i < l; i++ {
-		var path vector.Path
-		x1, y1, _ := projectionTo2D(particle.trail[i-1].x, particle.trail[i-1].y, particle.trail[i-1].z)
-		path.MoveTo(float32(x1), float32(y1))
-		x2, y2, _ := projectionTo2D(particle.trail[i].x, particle.trail[i].y, particle.trail[i].z)
-		path.LineTo(float32(x2), float32(y2))
-		op := &vector.StrokeOptions{}
-		op.Width = float32(i)/float32(l)*4 + 0.4
-		vs, is := path.AppendVerticesAndIndicesForStroke(r.vertices[:0], r.indices[:0], op)
-		for i := range vs {
-			vs[i].ColorR = 1
-			vs[i].ColorG = 0.5
-			vs[i].ColorB = 1.0 - float32(i)/float32(l)
-			vs[i].ColorA = float32(i)/float32(l)*0.9 + 0.1
+		x1, y1, p1 := r.projectionTo2D(particle.trail[i-1].x, particle.trail[i-1].y, particle.trail[i-1].z)
+		x2, y2, p2 := r.projectionTo2D(particle.trail[i].x, particle.trail[i].y, particle.trail[i].z)
+		if p1 <= 0 || p2 <= 0 {
+			continue
 		}
-		screen.DrawTriangles(vs, is, r.whiteSubImage, &ebiten.DrawTrianglesOptions{
-			AntiAlias: false,
-		})
+		t := float32(i) / float32(l)
+		b.AddSegment(
+			float32(x1), float32(y1), float32(x2), float32(y2),
+			t*4+0.4,
+			1, 0.5, 1.0-t, t*0.9+0.1,
+		)
 	}
 }
 
 func (r *Renderer) drawParticles(screen *ebiten.Image, particles []*Particle) {
+	r.particleBatch.Begin(screen)
 	for _, s := range particles {
-		r.drawParticle(screen, s, s.radius*s.AgePer()*0.01)
+		r.addParticle(r.particleBatch, s, s.radius*s.AgePer()*0.01)
 	}
+	r.particleBatch.End()
 }
 
-func (r *Renderer) drawParticle(screen *ebiten.Image, particle *Particle, diam float64) {
-	w, h := float64(r.particleImage.Bounds().Dx()), float64(r.particleImage.Bounds().Dy())
-	x, y, perspective := projectionTo2D(particle.loc.x, particle.loc.y, particle.loc.z)
+func (r *Renderer) addParticle(b *Batch, particle *Particle, diam float64) {
+	x, y, perspective := r.projectionTo2D(particle.loc.x, particle.loc.y, particle.loc.z)
+	if perspective <= 0 {
+		return
+	}
 	diam *= perspective
 
-	r.op.GeoM.Reset()
-	r.op.GeoM.Translate(-float64(w)/2, -float64(h)/2)
-	r.op.GeoM.Scale(particle.radius/w, particle.radius/h)
-	r.op.GeoM.Scale(diam, diam)
-	r.op.GeoM.Translate(x, y)
-	// r.op.GeoM.Translate(screenWidth/2, screenHeight/2)
-	// r.op.Blend = ebiten.BlendLighter
-	r.op.Filter = ebiten.FilterLinear
-	r.op.ColorScale.Reset()
-	r.op.ColorScale.Scale(
-		float32(particle.AgePer()),
-		float32(particle.AgePer()*0.75),
-		float32(1-particle.AgePer()),
-		0.1,
+	// the particle image is stretched to radius*diam pixels
+	half := particle.radius * diam / 2
+	agePer := float32(particle.AgePer())
+	b.AddQuad(
+		float32(x-half), float32(y-half), float32(x+half), float32(y+half),
+		agePer, agePer*0.75, 1-agePer, 0.1,
 	)
-
-	// Do not use the colorm package because it is too heavy
-	// var c colorm.ColorM
-	// c.Reset()
-	// c.Scale(1, 1, 1, particle.AgePer())
-	// c.Translate(particle.AgePer(), particle.AgePer()*0.75, 1-particle.AgePer(), 0.1)
-	// colorm.DrawImage(screen, r.ebitenImage, c, &r.op)
-
-	screen.DrawImage(r.particleImage, &r.op)
 }
 
-func (r *Renderer) drawEmitter(screen *ebiten.Image, x, y float64) {
-	// const zFar float64 = 1000
+func (r *Renderer) drawEmitter(screen *ebiten.Image, loc Vec3D) {
 	w, h := float64(r.emitterImage.Bounds().Dx()), float64(r.emitterImage.Bounds().Dy())
-	radius := float64(100)
+	x, y, perspective := r.projectionTo2D(loc.x, loc.y, loc.z)
+	if perspective <= 0 {
+		return
+	}
+	radius := float64(100) * perspective
 
 	r.op.GeoM.Reset()
 	r.op.GeoM.Translate(-float64(w)/2, -float64(h)/2)
 	r.op.GeoM.Scale(radius/w, radius/h)
 	r.op.GeoM.Translate(x, y)
-	r.op.GeoM.Translate(screenWidth/2, screenHeight/2)
 	// r.op.Blend = ebiten.BlendLighter
 	r.op.ColorScale.Reset()
 	r.op.Filter = ebiten.FilterLinear
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Shape decides where a particle is spawned relative to the emitter.
+// Sample returns the offset from the emitter and a unit direction that
+// the particle is launched in.
+type Shape interface {
+	Sample(r *rand.Rand) (offset, dir Vec3D)
+}
+
+// PointShape spawns every particle exactly at the emitter.
+type PointShape struct{}
+
+func (s PointShape) Sample(r *rand.Rand) (Vec3D, Vec3D) {
+	return Vec3D{}, randomUnitVec3D(r)
+}
+
+// SphereShape spawns particles uniformly inside a ball.
+type SphereShape struct {
+	Radius float64
+}
+
+func (s SphereShape) Sample(r *rand.Rand) (Vec3D, Vec3D) {
+	dir := randomUnitVec3D(r)
+	return dir.Scale(s.Radius * math.Cbrt(r.Float64())), dir
+}
+
+// DiscShape spawns particles uniformly inside a disc on the screen (XY) plane.
+type DiscShape struct {
+	Radius float64
+}
+
+func (s DiscShape) Sample(r *rand.Rand) (Vec3D, Vec3D) {
+	a := r.Float64() * 2 * math.Pi
+	dir := NewVec3D(math.Cos(a), math.Sin(a), 0)
+	return dir.Scale(s.Radius * math.Sqrt(r.Float64())), dir
+}
+
+// BoxShape spawns particles uniformly inside an axis aligned box.
+type BoxShape struct {
+	HalfSize Vec3D
+}
+
+func (s BoxShape) Sample(r *rand.Rand) (Vec3D, Vec3D) {
+	offset := NewVec3D(
+		(r.Float64()*2-1)*s.HalfSize.x,
+		(r.Float64()*2-1)*s.HalfSize.y,
+		(r.Float64()*2-1)*s.HalfSize.z,
+	)
+	return offset, randomUnitVec3D(r)
+}
+
+// LineShape spawns particles along the segment From-To.
+type LineShape struct {
+	From, To Vec3D
+}
+
+func (s LineShape) Sample(r *rand.Rand) (Vec3D, Vec3D) {
+	offset := s.From.Add(s.To.Sub(s.From).Scale(r.Float64()))
+	return offset, randomUnitVec3D(r)
+}
+
+// ConeShape spawns particles inside a cone whose apex is at the emitter.
+// Angle is the half angle in radians and Length the height of the cone.
+type ConeShape struct {
+	Direction Vec3D
+	Angle     float64
+	Length    float64
+}
+
+func (s ConeShape) Sample(r *rand.Rand) (Vec3D, Vec3D) {
+	axis := s.Direction
+	if l := axis.Length(); l > 0 {
+		axis = axis.Scale(1 / l)
+	} else {
+		axis = NewVec3D(0, -1, 0)
+	}
+	helper := NewVec3D(1, 0, 0)
+	if math.Abs(axis.x) > 0.9 {
+		helper = NewVec3D(0, 1, 0)
+	}
+	u := axis.Cross(helper).Normalize()
+	w := axis.Cross(u)
+
+	// uniform over the spherical cap
+	cosT := 1 - r.Float64()*(1-math.Cos(s.Angle))
+	sinT := math.Sqrt(1 - cosT*cosT)
+	phi := r.Float64() * 2 * math.Pi
+	dir := axis.Scale(cosT).Add(u.Scale(math.Cos(phi) * sinT)).Add(w.Scale(math.Sin(phi) * sinT))
+	return dir.Scale(s.Length * math.Cbrt(r.Float64())), dir
+}
+
+func randomUnitVec3D(r *rand.Rand) Vec3D {
+	z := r.Float64()*2 - 1
+	a := r.Float64() * 2 * math.Pi
+	s := math.Sqrt(1 - z*z)
+	return NewVec3D(s*math.Cos(a), s*math.Sin(a), z)
+}
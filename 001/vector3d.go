@@ -14,8 +14,28 @@ func NewVec3D(x, y, z float64) Vec3D {
 	return Vec3D{x, y, z}
 }
 
+func (v Vec3D) Dot(value Vec3D) float64 {
+	return v.x*value.x + v.y*value.y + v.z*value.z
+}
+
+func (v Vec3D) LengthSquared() float64 {
+	return v.Dot(v)
+}
+
+func (v Vec3D) Length() float64 {
+	return math.Sqrt(v.LengthSquared())
+}
+
+func (v Vec3D) Distance(value Vec3D) float64 {
+	return v.Sub(value).Length()
+}
+
+// Normalize returns the unit vector of v, or the zero vector if v has no length.
 func (v Vec3D) Normalize() Vec3D {
-	magnitude := math.Sqrt(v.x*v.x + v.y*v.y + v.z*v.z)
+	magnitude := v.Length()
+	if magnitude == 0 {
+		return Vec3D{}
+	}
 	return Vec3D{v.x / magnitude, v.y / magnitude, v.z / magnitude}
 }
 
@@ -39,10 +59,57 @@ func (v Vec3D) Scale(value float64) Vec3D {
 	return Vec3D{v.x * value, v.y * value, v.z * value}
 }
 
-func (v *Vec3D) Randomize() {
-	v.x = rand.Float64()
-	v.y = rand.Float64()
-	v.z = rand.Float64()
+func (v Vec3D) Neg() Vec3D {
+	return Vec3D{-v.x, -v.y, -v.z}
+}
+
+// Lerp linearly interpolates from v (t=0) to value (t=1).
+func (v Vec3D) Lerp(value Vec3D, t float64) Vec3D {
+	return Vec3D{
+		v.x + (value.x-v.x)*t,
+		v.y + (value.y-v.y)*t,
+		v.z + (value.z-v.z)*t,
+	}
+}
+
+// Slerp spherically interpolates from v (t=0) to value (t=1) along the
+// great arc between them. It is meant for vectors of the same length and
+// falls back to Lerp when they are (anti)parallel.
+func (v Vec3D) Slerp(value Vec3D, t float64) Vec3D {
+	lv, lw := v.Length(), value.Length()
+	if lv == 0 || lw == 0 {
+		return v.Lerp(value, t)
+	}
+	cos := v.Dot(value) / (lv * lw)
+	cos = math.Max(-1, math.Min(1, cos))
+	theta := math.Acos(cos)
+	sin := math.Sin(theta)
+	if sin < 1e-9 {
+		return v.Lerp(value, t)
+	}
+	a := math.Sin((1-t)*theta) / sin
+	b := math.Sin(t*theta) / sin
+	return v.Scale(a).Add(value.Scale(b))
+}
+
+// Reflect reflects v off a surface with the unit normal n.
+func (v Vec3D) Reflect(n Vec3D) Vec3D {
+	return v.Sub(n.Scale(2 * v.Dot(n)))
+}
+
+// ClampLength returns v shortened to max if it is longer than max.
+func (v Vec3D) ClampLength(max float64) Vec3D {
+	l := v.Length()
+	if l <= max || l == 0 {
+		return v
+	}
+	return v.Scale(max / l)
+}
+
+func (v *Vec3D) Randomize(r *rand.Rand) {
+	v.x = r.Float64()
+	v.y = r.Float64()
+	v.z = r.Float64()
 }
 
 func (v *Vec3D) Set(vx, vy, vz float64) {
@@ -61,10 +128,9 @@ func (v *Vec3D) AddSelf(value Vec3D) {
 	v.z += value.z
 }
 
+// InterpolateToSelf moves v toward value by the ratio scale.
 func (v *Vec3D) InterpolateToSelf(value Vec3D, scale float64) {
-	v.x = value.x * scale
-	v.y = value.y * scale
-	v.z = value.z * scale
+	*v = v.Lerp(value, scale)
 }
 
 func (v Vec3D) Clone() Vec3D {
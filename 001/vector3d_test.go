@@ -0,0 +1,106 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVec3DBasics(t *testing.T) {
+	a := NewVec3D(1, 2, 3)
+	b := NewVec3D(-4, 5, 0.5)
+
+	tests := []struct {
+		name string
+		got  Vec3D
+		want Vec3D
+	}{
+		{"Add", a.Add(b), NewVec3D(-3, 7, 3.5)},
+		{"Sub", a.Sub(b), NewVec3D(5, -3, 2.5)},
+		{"Scale", a.Scale(-2), NewVec3D(-2, -4, -6)},
+		{"Neg", a.Neg(), NewVec3D(-1, -2, -3)},
+		{"Cross", a.Cross(b), NewVec3D(2*0.5-3*5, 3*-4-1*0.5, 1*5-2*-4)},
+		{"Cross x y", NewVec3D(1, 0, 0).Cross(NewVec3D(0, 1, 0)), NewVec3D(0, 0, 1)},
+		{"Cross self", a.Cross(a), NewVec3D(0, 0, 0)},
+		{"Normalize", NewVec3D(3, 0, 4).Normalize(), NewVec3D(0.6, 0, 0.8)},
+		{"Normalize zero", Vec3D{}.Normalize(), Vec3D{}},
+		{"Lerp 0", a.Lerp(b, 0), a},
+		{"Lerp 1", a.Lerp(b, 1), b},
+		{"Lerp half", a.Lerp(b, 0.5), NewVec3D(-1.5, 3.5, 1.75)},
+		{"Lerp extrapolate", a.Lerp(b, 2), NewVec3D(-9, 8, -2)},
+		{"Reflect floor", NewVec3D(1, 2, 0).Reflect(NewVec3D(0, -1, 0)), NewVec3D(1, -2, 0)},
+		{"Reflect wall", NewVec3D(3, 1, -1).Reflect(NewVec3D(1, 0, 0)), NewVec3D(-3, 1, -1)},
+		{"ClampLength long", NewVec3D(0, 10, 0).ClampLength(2), NewVec3D(0, 2, 0)},
+		{"ClampLength short", NewVec3D(0, 1, 0).ClampLength(2), NewVec3D(0, 1, 0)},
+		{"ClampLength zero", Vec3D{}.ClampLength(2), Vec3D{}},
+	}
+	for _, tt := range tests {
+		if !vecNear(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestVec3DScalars(t *testing.T) {
+	a := NewVec3D(1, 2, 3)
+	b := NewVec3D(-4, 5, 0.5)
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"Dot", a.Dot(b), -4 + 10 + 1.5},
+		{"Dot perpendicular", NewVec3D(1, 0, 0).Dot(NewVec3D(0, 0, 7)), 0},
+		{"LengthSquared", a.LengthSquared(), 14},
+		{"Length", NewVec3D(2, 3, 6).Length(), 7},
+		{"Distance", a.Distance(NewVec3D(1, 5, 7)), 5},
+		{"Normalize length", b.Normalize().Length(), 1},
+		{"Cross perpendicular a", a.Cross(b).Dot(a), 0},
+		{"Cross perpendicular b", a.Cross(b).Dot(b), 0},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > epsilon {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestVec3DSlerp(t *testing.T) {
+	x := NewVec3D(1, 0, 0)
+	y := NewVec3D(0, 1, 0)
+	tests := []struct {
+		name string
+		a, b Vec3D
+		t    float64
+		want Vec3D
+	}{
+		{"start", x, y, 0, x},
+		{"end", x, y, 1, y},
+		{"half", x, y, 0.5, NewVec3D(math.Sqrt2/2, math.Sqrt2/2, 0)},
+		{"third", x, y, 1.0 / 3, NewVec3D(math.Cos(math.Pi/6), math.Sin(math.Pi/6), 0)},
+		{"same", x, x, 0.5, x},
+		{"zero", Vec3D{}, y, 0.5, NewVec3D(0, 0.5, 0)},
+		{"opposite falls back to lerp", x, x.Neg(), 0.25, NewVec3D(0.5, 0, 0)},
+	}
+	for _, tt := range tests {
+		got := tt.a.Slerp(tt.b, tt.t)
+		if !vecNear(got, tt.want) {
+			t.Errorf("%s: Slerp = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// slerp keeps unit vectors on the unit sphere
+	for i := 0; i <= 10; i++ {
+		v := NewVec3D(1, 2, 3).Normalize().Slerp(NewVec3D(-3, 1, 0).Normalize(), float64(i)/10)
+		if math.Abs(v.Length()-1) > epsilon {
+			t.Errorf("t=%v: length = %v, want 1", float64(i)/10, v.Length())
+		}
+	}
+}
+
+func TestVec3DInterpolateToSelf(t *testing.T) {
+	v := NewVec3D(10, 0, 0)
+	v.InterpolateToSelf(NewVec3D(20, 10, -10), 0.25)
+	if want := NewVec3D(12.5, 2.5, -2.5); !vecNear(v, want) {
+		t.Errorf("InterpolateToSelf = %v, want %v", v, want)
+	}
+}
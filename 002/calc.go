@@ -10,6 +10,8 @@ const (
 
 type Calc struct {
 	World World
+
+	grid SpatialHash
 }
 
 func (u *Calc) Fruits(fruits []*Fruit) []*Fruit {
@@ -49,33 +51,44 @@ func (u *Calc) move(fruits []*Fruit) {
 }
 
 func (u *Calc) hitTest(fruits []*Fruit) {
-	l := len(fruits)
-	for i := 0; i < l; i++ {
-		for j := i + 1; j < l; j++ {
-			f := fruits[i]
-			g := fruits[j]
-			dx := g.X - f.X
-			dy := g.Y - f.Y
-			d := math.Sqrt(dx*dx + dy*dy)
-			minD := f.Radius + g.Radius
-			if d < minD {
-				// collision
-				angle := math.Atan2(dy, dx)
-				tx := f.X + math.Cos(angle)*minD
-				ty := f.Y + math.Sin(angle)*minD
-				ax := (tx - g.X) * spring
-				ay := (ty - g.Y) * spring
-				f.VX -= ax
-				f.VY -= ay
-				g.VX += ax
-				g.VY += ay
+	maxRadius := 0.0
+	for _, f := range fruits {
+		maxRadius = math.Max(maxRadius, f.Radius)
+	}
+	u.grid.CellSize = maxRadius * 2
+	u.grid.Build(fruits)
+	for _, p := range u.grid.Pairs(fruits) {
+		u.collide(fruits[p[0]], fruits[p[1]])
+	}
+}
 
-				f.X = f.X - math.Cos(angle)*(minD-d)/2
-				f.Y = f.Y - math.Sin(angle)*(minD-d)/2
-				g.X = g.X + math.Cos(angle)*(minD-d)/2
-				g.Y = g.Y + math.Sin(angle)*(minD-d)/2
-			}
-		}
+func (u *Calc) collide(f, g *Fruit) {
+	dx := g.X - f.X
+	dy := g.Y - f.Y
+	d := math.Sqrt(dx*dx + dy*dy)
+	minD := f.Radius + g.Radius
+	if d < minD {
+		// collision
+		angle := math.Atan2(dy, dx)
+		tx := f.X + math.Cos(angle)*minD
+		ty := f.Y + math.Sin(angle)*minD
+		ax := (tx - g.X) * spring
+		ay := (ty - g.Y) * spring
+
+		// the lighter fruit takes the larger share of the push
+		// (both get 1 when the masses are equal)
+		fm, gm := f.Mass(), g.Mass()
+		fr := 2 * gm / (fm + gm)
+		gr := 2 * fm / (fm + gm)
+		f.VX -= ax * fr
+		f.VY -= ay * fr
+		g.VX += ax * gr
+		g.VY += ay * gr
+
+		f.X = f.X - math.Cos(angle)*(minD-d)/2*fr
+		f.Y = f.Y - math.Sin(angle)*(minD-d)/2*fr
+		g.X = g.X + math.Cos(angle)*(minD-d)/2*gr
+		g.Y = g.Y + math.Sin(angle)*(minD-d)/2*gr
 	}
 }
 
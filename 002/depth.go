@@ -0,0 +1,24 @@
+package main
+
+import "sort"
+
+// DepthQueue orders fruits back to front by Y. Fruits on the same Y are
+// ordered by ID so that they don't flicker from one frame to the next.
+// Embed it in a drawer to make the drawer a DepthSorter.
+type DepthQueue struct {
+	sorted []*Fruit
+}
+
+// SortByDepth returns a sorted copy of fruits. The returned slice is
+// reused by the next call.
+func (q *DepthQueue) SortByDepth(fruits []*Fruit) []*Fruit {
+	q.sorted = append(q.sorted[:0], fruits...)
+	sort.SliceStable(q.sorted, func(i, j int) bool {
+		a, b := q.sorted[i], q.sorted[j]
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.ID < b.ID
+	})
+	return q.sorted
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDepthQueueOrder(t *testing.T) {
+	fruits := []*Fruit{
+		{ID: 1, Y: 30},
+		{ID: 2, Y: 10},
+		{ID: 3, Y: 20},
+		{ID: 4, Y: 10},
+		{ID: 5, Y: 30},
+	}
+	q := &DepthQueue{}
+	got := q.SortByDepth(fruits)
+	want := []int{2, 4, 3, 1, 5}
+	for i, f := range got {
+		if f.ID != want[i] {
+			t.Fatalf("order = %v, want %v", ids(got), want)
+		}
+	}
+	if fruits[0].ID != 1 {
+		t.Errorf("the input slice was modified")
+	}
+}
+
+func TestDepthQueueStableAcrossFrames(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	fruits := make([]*Fruit, 100)
+	for i := range fruits {
+		// only a few distinct rows so that there are many ties
+		fruits[i] = &Fruit{ID: i + 1, Y: float64(r.Intn(5) * 10)}
+	}
+
+	q := &DepthQueue{}
+	first := ids(q.SortByDepth(fruits))
+	for frame := 0; frame < 20; frame++ {
+		// the simulation may hand over the fruits in any order
+		r.Shuffle(len(fruits), func(i, j int) { fruits[i], fruits[j] = fruits[j], fruits[i] })
+		got := ids(q.SortByDepth(fruits))
+		for i := range got {
+			if got[i] != first[i] {
+				t.Fatalf("frame %d: order changed at %d: %v, want %v", frame, i, got, first)
+			}
+		}
+	}
+}
+
+var _ DepthSorter = (*SpriteDrawer)(nil)
+
+func TestSpriteDrawerIsDepthSorter(t *testing.T) {
+	var d Drawer = &SpriteDrawer{}
+	if _, ok := d.(DepthSorter); !ok {
+		t.Errorf("SpriteDrawer does not implement DepthSorter")
+	}
+}
+
+func ids(fruits []*Fruit) []int {
+	r := make([]int, len(fruits))
+	for i, f := range fruits {
+		r[i] = f.ID
+	}
+	return r
+}
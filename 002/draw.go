@@ -13,13 +13,6 @@ import (
 )
 
 var (
-	appleImage      *ebiten.Image
-	grapeImage      *ebiten.Image
-	orangeImage     *ebiten.Image
-	pineappleImage  *ebiten.Image
-	melonImage      *ebiten.Image
-	watermelonImage *ebiten.Image
-
 	//go:embed assets/apple.png
 	apple_png []byte
 	//go:embed assets/grape.png
@@ -38,10 +31,6 @@ type Draw struct {
 	op ebiten.DrawImageOptions
 }
 
-func init() {
-	appleImage = loadImage(apple_png)
-}
-
 func loadImage(b []byte) *ebiten.Image {
 	img, _, err := image.Decode(bytes.NewReader(b))
 	if err != nil {
@@ -66,8 +55,7 @@ func (d *Draw) World(screen *ebiten.Image, world World) {
 }
 
 func (d *Draw) Fruit(screen *ebiten.Image, world World, f *Fruit) {
-	var img *ebiten.Image
-	img = appleImage
+	img := f.Kind.Spec().Image
 
 	w, h := img.Bounds().Dx(), img.Bounds().Dy()
 	d.op.Filter = ebiten.FilterLinear
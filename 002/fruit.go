@@ -8,22 +8,37 @@ const (
 )
 
 type Fruit struct {
+	ID     int
 	X      float64
 	Y      float64
 	VX     float64
 	VY     float64
 	Radius float64
+	Kind   FruitKind
 
 	Direction     uint8
 	TotalMovement float64
 }
 
-func NewApple(x float64, y float64) *Fruit {
+var nextFruitID int
+
+func NewFruit(kind FruitKind, x float64, y float64) *Fruit {
+	nextFruitID++
 	return &Fruit{
+		ID:     nextFruitID,
 		X:      x,
 		Y:      y,
-		Radius: 20,
+		Radius: kind.Spec().Radius,
+		Kind:   kind,
 
 		Direction: BOTTOM,
 	}
 }
+
+func NewApple(x float64, y float64) *Fruit {
+	return NewFruit(Apple, x, y)
+}
+
+func (f *Fruit) Mass() float64 {
+	return f.Kind.Spec().Mass
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type FruitKind int
+
+const (
+	Apple FruitKind = iota
+	Grape
+	Orange
+	Pineapple
+	Melon
+	Watermelon
+
+	numFruitKinds
+)
+
+// SpriteSheet is an image with 4 walking frames for each of the 4 directions.
+type SpriteSheet struct {
+	Image *ebiten.Image
+	// Frames is indexed by [Direction][frame].
+	Frames [4][4]image.Rectangle
+	// AnchorX and AnchorY are the point of a frame drawn on the fruit's center.
+	AnchorX, AnchorY float64
+}
+
+// FruitSpec describes how a kind of fruit looks and behaves.
+type FruitSpec struct {
+	Name   string
+	Radius float64
+	Mass   float64
+
+	// Image is drawn by Draw, Sheet by SpriteDrawer.
+	Image *ebiten.Image
+	Sheet SpriteSheet
+}
+
+// Masses grow with the area, apple being 1.
+var fruitSpecs = [numFruitKinds]FruitSpec{
+	Apple:      {Name: "apple", Radius: 20, Mass: 1},
+	Grape:      {Name: "grape", Radius: 12, Mass: 0.36},
+	Orange:     {Name: "orange", Radius: 22, Mass: 1.21},
+	Pineapple:  {Name: "pineapple", Radius: 28, Mass: 1.96},
+	Melon:      {Name: "melon", Radius: 34, Mass: 2.89},
+	Watermelon: {Name: "watermelon", Radius: 40, Mass: 4},
+}
+
+func init() {
+	fruitSpecs[Apple].Image = loadImage(apple_png)
+	fruitSpecs[Grape].Image = loadImage(grape_png)
+	fruitSpecs[Orange].Image = loadImage(orange_png)
+	fruitSpecs[Pineapple].Image = loadImage(pineapple_png)
+	fruitSpecs[Melon].Image = loadImage(melon_png)
+	fruitSpecs[Watermelon].Image = loadImage(watermelon_png)
+
+	// the walking character in sprite.png is the apple. There is no art
+	// for the others walking yet, so they slide around as still images.
+	fruitSpecs[Apple].Sheet = newCharacterSheet(loadImage(sprite_png))
+	for k := Grape; k < numFruitKinds; k++ {
+		fruitSpecs[k].Sheet = newStillSheet(fruitSpecs[k].Image)
+	}
+}
+
+// newCharacterSheet cuts the 48x104 frames out of sprite.png.
+func newCharacterSheet(img *ebiten.Image) SpriteSheet {
+	const (
+		w = 48
+		h = 104
+	)
+	offsetXs := [4]int{8, 72, 136, 200}
+	offsetYs := [4]int{
+		BOTTOM: 12,
+		RIGHT:  144,
+		TOP:    276,
+		LEFT:   412,
+	}
+	s := SpriteSheet{
+		Image:   img,
+		AnchorX: w / 2,
+		AnchorY: h - w/2,
+	}
+	for dir, y := range offsetYs {
+		for frame, x := range offsetXs {
+			// the last row runs off the bottom of the image
+			s.Frames[dir][frame] = image.Rect(x, y, x+w, y+h).Intersect(img.Bounds())
+		}
+	}
+	return s
+}
+
+// newStillSheet uses the whole image for every frame, for the kinds that
+// have no walking sheet: they don't animate or turn.
+func newStillSheet(img *ebiten.Image) SpriteSheet {
+	b := img.Bounds()
+	s := SpriteSheet{
+		Image:   img,
+		AnchorX: float64(b.Dx()) / 2,
+		AnchorY: float64(b.Dy()) / 2,
+	}
+	for dir := range s.Frames {
+		for frame := range s.Frames[dir] {
+			s.Frames[dir][frame] = b
+		}
+	}
+	return s
+}
+
+// Spec returns the registered spec of k. Unknown kinds fall back to Apple.
+func (k FruitKind) Spec() *FruitSpec {
+	if k < 0 || k >= numFruitKinds {
+		return &fruitSpecs[Apple]
+	}
+	return &fruitSpecs[k]
+}
+
+func (k FruitKind) String() string {
+	return k.Spec().Name
+}
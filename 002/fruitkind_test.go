@@ -0,0 +1,108 @@
+package main
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestFruitKindSpec(t *testing.T) {
+	tests := []struct {
+		kind   FruitKind
+		name   string
+		radius float64
+		mass   float64
+	}{
+		{Apple, "apple", 20, 1},
+		{Grape, "grape", 12, 0.36},
+		{Orange, "orange", 22, 1.21},
+		{Pineapple, "pineapple", 28, 1.96},
+		{Melon, "melon", 34, 2.89},
+		{Watermelon, "watermelon", 40, 4},
+		{FruitKind(-1), "apple", 20, 1},
+		{numFruitKinds, "apple", 20, 1},
+	}
+	for _, tt := range tests {
+		s := tt.kind.Spec()
+		if s.Name != tt.name || s.Radius != tt.radius || s.Mass != tt.mass {
+			t.Errorf("FruitKind(%d).Spec() = %s r=%v m=%v, want %s r=%v m=%v",
+				tt.kind, s.Name, s.Radius, s.Mass, tt.name, tt.radius, tt.mass)
+		}
+	}
+}
+
+func TestFruitKindSheets(t *testing.T) {
+	for k := Apple; k < numFruitKinds; k++ {
+		s := k.Spec()
+		if s.Image == nil || s.Sheet.Image == nil {
+			t.Errorf("%s: image is not loaded", k)
+			continue
+		}
+		bounds := s.Sheet.Image.Bounds()
+		for dir := range s.Sheet.Frames {
+			for frame, r := range s.Sheet.Frames[dir] {
+				if r.Empty() || !r.In(bounds) {
+					t.Errorf("%s: frame [%d][%d] %v is outside of %v", k, dir, frame, r, bounds)
+				}
+			}
+		}
+	}
+
+	apple := Apple.Spec().Sheet
+	if got, want := apple.Frames[RIGHT][2], image.Rect(136, 144, 184, 248); got != want {
+		t.Errorf("apple frame [RIGHT][2] = %v, want %v", got, want)
+	}
+	if apple.AnchorX != 24 || apple.AnchorY != 80 {
+		t.Errorf("apple anchor = (%v, %v), want (24, 80)", apple.AnchorX, apple.AnchorY)
+	}
+}
+
+func TestNewFruit(t *testing.T) {
+	f := NewFruit(Melon, 10, 20)
+	if f.Kind != Melon || f.Radius != 34 || f.Mass() != 2.89 || f.X != 10 || f.Y != 20 {
+		t.Errorf("NewFruit(Melon) = %+v", f)
+	}
+}
+
+func TestCollideMassWeighted(t *testing.T) {
+	tests := []struct {
+		name   string
+		fk, gk FruitKind
+	}{
+		{"same mass", Apple, Apple},
+		{"light and heavy", Grape, Watermelon},
+		{"heavy and light", Watermelon, Grape},
+		{"orange and melon", Orange, Melon},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFruit(tt.fk, 100, 100)
+			g := NewFruit(tt.gk, 110, 100)
+			c := &Calc{}
+			c.collide(f, g)
+
+			fm, gm := f.Mass(), g.Mass()
+			// momentum is conserved
+			if p := fm*f.VX + gm*g.VX; math.Abs(p) > 1e-9 {
+				t.Errorf("momentum after collision = %v, want 0", p)
+			}
+			// the impulse is split by the inverse mass
+			if math.Abs(f.VX/g.VX+gm/fm) > 1e-9 {
+				t.Errorf("velocity ratio = %v, want %v", f.VX/g.VX, -gm/fm)
+			}
+			// the fruits no longer overlap
+			if d := g.X - f.X; math.Abs(d-(f.Radius+g.Radius)) > 1e-9 {
+				t.Errorf("distance = %v, want %v", d, f.Radius+g.Radius)
+			}
+		})
+	}
+
+	// equal masses behave like the original unweighted spring
+	f := NewApple(100, 100)
+	g := NewApple(110, 100)
+	(&Calc{}).collide(f, g)
+	// target distance 40, so g is pushed by (40-10)*spring
+	if want := -30 * spring; math.Abs(f.VX-want) > 1e-9 || math.Abs(g.VX+want) > 1e-9 {
+		t.Errorf("VX = %v, %v, want %v, %v", f.VX, g.VX, want, -want)
+	}
+}
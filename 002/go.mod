@@ -2,7 +2,11 @@ module github.com/demouth/ebitengine-sketch/002
 
 go 1.22.0
 
-require github.com/hajimehoshi/ebiten/v2 v2.6.6
+require (
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
+	github.com/demouth/ebitengine-sketch/lib/recorder v0.0.0
+	github.com/hajimehoshi/ebiten/v2 v2.6.6
+)
 
 require (
 	github.com/ebitengine/purego v0.6.1 // indirect
@@ -13,3 +17,7 @@ require (
 	golang.org/x/sync v0.6.0 // indirect
 	golang.org/x/sys v0.18.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
+
+replace github.com/demouth/ebitengine-sketch/lib/recorder => ../lib/recorder
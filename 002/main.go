@@ -1,10 +1,13 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"math/rand"
 
+	"github.com/demouth/ebitengine-sketch/lib/recorder"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -25,6 +28,12 @@ type Drawer interface {
 	Fruits(screen *ebiten.Image, world World, fruits []*Fruit)
 }
 
+// DepthSorter is implemented by drawers that need fruits ordered back to
+// front before Fruits is called.
+type DepthSorter interface {
+	SortByDepth(fruits []*Fruit) []*Fruit
+}
+
 var (
 	mainCharacter = NewApple(screenWidth/2, screenHeight/2)
 
@@ -33,23 +42,28 @@ var (
 
 	calc = &Calc{World: world}
 
-	drawer Drawer
+	spriteDrawer = &SpriteDrawer{}
+	fruitDrawer  = &Draw{}
+	drawer       Drawer
 
 	isKeyPressed = false
 )
 
 func init() {
-	for _ = range 40 {
+	drawer = spriteDrawer
+}
+
+func spawnFruits(r *rand.Rand, n int) {
+	for range n {
 		fruits = append(
 			fruits,
-			NewApple(
-				rand.Float64()*screenWidth,
-				rand.Float64()*screenHeight,
+			NewFruit(
+				FruitKind(r.Intn(int(numFruitKinds))),
+				r.Float64()*screenWidth,
+				r.Float64()*screenHeight,
 			),
 		)
 	}
-
-	drawer = &SpriteDrawer{}
 }
 
 func (g *Game) Update() error {
@@ -67,9 +81,9 @@ func (g *Game) Update() error {
 			mainCharacter.VY += (float64(y) - hh) / hh * 1
 		}
 		if len(g.touchIDs) > 1 {
-			drawer = &Draw{}
+			drawer = fruitDrawer
 		} else {
-			drawer = &SpriteDrawer{}
+			drawer = spriteDrawer
 		}
 	}
 	// mouse
@@ -89,9 +103,9 @@ func (g *Game) Update() error {
 		ac = 0.5
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyA) {
-		drawer = &SpriteDrawer{}
+		drawer = spriteDrawer
 	} else if ebiten.IsKeyPressed(ebiten.KeyS) {
-		drawer = &Draw{}
+		drawer = fruitDrawer
 	}
 
 	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
@@ -110,14 +124,20 @@ func (g *Game) Update() error {
 
 func (g *Game) Draw(screen *ebiten.Image) {
 	drawer.World(screen, world)
-	drawer.Fruits(screen, world, fruits)
+	sorted := fruits
+	if s, ok := drawer.(DepthSorter); ok {
+		sorted = s.SortByDepth(fruits)
+	}
+	drawer.Fruits(screen, world, sorted)
 	msg := fmt.Sprintf(
 		"Arrow keys: move character\n"+
 			"Space keys: move fast\n"+
 			"A key: Draw a character\n"+
-			"S key: Draw an apple\n"+
-			"FPS: %0.2f",
+			"S key: Draw fruits\n"+
+			"FPS: %0.2f\n"+
+			"%s",
 		ebiten.ActualFPS(),
+		seed.Label(),
 	)
 	ebitenutil.DebugPrint(screen, msg)
 }
@@ -126,10 +146,19 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return screenWidth, screenHeight
 }
 
+var recordFormat = flag.String("record", "gif", "the format of recordings started with F9: gif or png")
+
 func main() {
+	flag.Parse()
+	format, err := recorder.ParseFormat(*recordFormat)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spawnFruits(seed.New(), 40)
+
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("002")
-	if err := ebiten.RunGame(&Game{}); err != nil {
+	if err := ebiten.RunGame(recorder.New(&Game{}, recorder.Options{Format: format})); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// SpatialHash is a uniform grid over the world used to find fruits that
+// may touch each other without testing every pair.
+// It is rebuilt from scratch every step and reuses its buffers.
+type SpatialHash struct {
+	CellSize float64
+
+	entries []cellEntry
+	pairs   [][2]int
+}
+
+type cellEntry struct {
+	cx, cy int
+	index  int
+}
+
+func (h *SpatialHash) cell(v float64) int {
+	return int(math.Floor(v / h.CellSize))
+}
+
+// Build puts every fruit into all cells its bounding box overlaps.
+func (h *SpatialHash) Build(fruits []*Fruit) {
+	h.entries = h.entries[:0]
+	if h.CellSize <= 0 {
+		return
+	}
+	for i, f := range fruits {
+		minX, maxX := h.cell(f.X-f.Radius), h.cell(f.X+f.Radius)
+		minY, maxY := h.cell(f.Y-f.Radius), h.cell(f.Y+f.Radius)
+		for cx := minX; cx <= maxX; cx++ {
+			for cy := minY; cy <= maxY; cy++ {
+				h.entries = append(h.entries, cellEntry{cx: cx, cy: cy, index: i})
+			}
+		}
+	}
+	sort.Sort(cellEntries(h.entries))
+}
+
+type cellEntries []cellEntry
+
+func (e cellEntries) Len() int      { return len(e) }
+func (e cellEntries) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e cellEntries) Less(i, j int) bool {
+	a, b := e[i], e[j]
+	if a.cx != b.cx {
+		return a.cx < b.cx
+	}
+	if a.cy != b.cy {
+		return a.cy < b.cy
+	}
+	return a.index < b.index
+}
+
+type indexPairs [][2]int
+
+func (p indexPairs) Len() int      { return len(p) }
+func (p indexPairs) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p indexPairs) Less(i, j int) bool {
+	if p[i][0] != p[j][0] {
+		return p[i][0] < p[j][0]
+	}
+	return p[i][1] < p[j][1]
+}
+
+// Pairs returns the index pairs (i < j) of fruits sharing at least one
+// cell, without duplicates and in the same order as a nested loop over
+// i and j would visit them. fruits must be the slice given to Build.
+// The returned slice is reused by the next call.
+func (h *SpatialHash) Pairs(fruits []*Fruit) [][2]int {
+	h.pairs = h.pairs[:0]
+	for start := 0; start < len(h.entries); {
+		end := start + 1
+		for end < len(h.entries) &&
+			h.entries[end].cx == h.entries[start].cx &&
+			h.entries[end].cy == h.entries[start].cy {
+			end++
+		}
+		cx, cy := h.entries[start].cx, h.entries[start].cy
+		for i := start; i < end; i++ {
+			for j := i + 1; j < end; j++ {
+				a, b := fruits[h.entries[i].index], fruits[h.entries[j].index]
+				// Two fruits can share several cells. Only the cell holding the
+				// top-left corner of their overlapping bounding boxes reports them.
+				if h.cell(math.Max(a.X-a.Radius, b.X-b.Radius)) != cx ||
+					h.cell(math.Max(a.Y-a.Radius, b.Y-b.Radius)) != cy {
+					continue
+				}
+				h.pairs = append(h.pairs, [2]int{h.entries[i].index, h.entries[j].index})
+			}
+		}
+		start = end
+	}
+	sort.Sort(indexPairs(h.pairs))
+	return h.pairs
+}
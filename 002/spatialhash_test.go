@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func randomFruits(r *rand.Rand, n int, width, height float64) []*Fruit {
+	fruits := make([]*Fruit, n)
+	for i := range fruits {
+		fruits[i] = &Fruit{
+			X:      r.Float64() * width,
+			Y:      r.Float64() * height,
+			Radius: 5 + r.Float64()*20,
+		}
+	}
+	return fruits
+}
+
+func overlapping(f, g *Fruit) bool {
+	dx := g.X - f.X
+	dy := g.Y - f.Y
+	return math.Sqrt(dx*dx+dy*dy) < f.Radius+g.Radius
+}
+
+func bruteForceOverlaps(fruits []*Fruit) [][2]int {
+	var pairs [][2]int
+	for i := 0; i < len(fruits); i++ {
+		for j := i + 1; j < len(fruits); j++ {
+			if overlapping(fruits[i], fruits[j]) {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+	return pairs
+}
+
+func gridOverlaps(h *SpatialHash, fruits []*Fruit) [][2]int {
+	h.Build(fruits)
+	var pairs [][2]int
+	for _, p := range h.Pairs(fruits) {
+		if overlapping(fruits[p[0]], fruits[p[1]]) {
+			pairs = append(pairs, p)
+		}
+	}
+	return pairs
+}
+
+func TestSpatialHashMatchesBruteForce(t *testing.T) {
+	tests := []struct {
+		n        int
+		size     float64
+		cellSize float64
+	}{
+		{0, 100, 50},
+		{1, 100, 50},
+		{10, 100, 50},
+		{200, 500, 50},
+		{500, 300, 50},
+		{500, 300, 7},   // smaller than the fruits
+		{500, 300, 400}, // everything in a few cells
+	}
+	for seed := int64(1); seed <= 5; seed++ {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("seed%d/n%d/cell%v", seed, tt.n, tt.cellSize), func(t *testing.T) {
+				r := rand.New(rand.NewSource(seed))
+				// allow fruits outside of the world and at negative coordinates
+				fruits := randomFruits(r, tt.n, tt.size, tt.size)
+				for _, f := range fruits {
+					f.X -= tt.size / 4
+					f.Y -= tt.size / 4
+				}
+
+				want := bruteForceOverlaps(fruits)
+				got := gridOverlaps(&SpatialHash{CellSize: tt.cellSize}, fruits)
+				if len(got) != len(want) {
+					t.Fatalf("found %d pairs, want %d", len(got), len(want))
+				}
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("pair %d = %v, want %v", i, got[i], want[i])
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSpatialHashPairsAreUnique(t *testing.T) {
+	// two big fruits sharing many cells
+	fruits := []*Fruit{
+		{X: 50, Y: 50, Radius: 40},
+		{X: 60, Y: 60, Radius: 40},
+	}
+	h := &SpatialHash{CellSize: 10}
+	h.Build(fruits)
+	pairs := h.Pairs(fruits)
+	if len(pairs) != 1 || pairs[0] != [2]int{0, 1} {
+		t.Errorf("Pairs = %v, want [[0 1]]", pairs)
+	}
+}
+
+func TestCalcHitTestSeparatesFruits(t *testing.T) {
+	f := &Fruit{X: 100, Y: 100, Radius: 20}
+	g := &Fruit{X: 110, Y: 100, Radius: 20}
+	far := &Fruit{X: 300, Y: 300, Radius: 20}
+	c := &Calc{}
+	c.hitTest([]*Fruit{f, g, far})
+	if f.VX >= 0 || g.VX <= 0 {
+		t.Errorf("fruits are not pushed apart: f.VX=%v g.VX=%v", f.VX, g.VX)
+	}
+	if math.Abs(g.X-f.X-40) > 1e-9 {
+		t.Errorf("distance after hit test = %v, want 40", g.X-f.X)
+	}
+	if far.VX != 0 || far.VY != 0 {
+		t.Errorf("a distant fruit was moved")
+	}
+}
+
+func benchmarkFruits(n int) []*Fruit {
+	r := rand.New(rand.NewSource(1))
+	// roughly the density of the sketch
+	size := math.Sqrt(float64(n)) * 60
+	fruits := randomFruits(r, n, size, size)
+	for _, f := range fruits {
+		f.Radius = 20
+	}
+	return fruits
+}
+
+func BenchmarkHitTest(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		b.Run(fmt.Sprintf("BruteForce/%d", n), func(b *testing.B) {
+			fruits := benchmarkFruits(n)
+			c := &Calc{}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for i := 0; i < len(fruits); i++ {
+					for j := i + 1; j < len(fruits); j++ {
+						c.collide(fruits[i], fruits[j])
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("SpatialHash/%d", n), func(b *testing.B) {
+			fruits := benchmarkFruits(n)
+			c := &Calc{}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.hitTest(fruits)
+			}
+		})
+	}
+}
@@ -16,15 +16,21 @@ package main
 
 import (
 	_ "embed"
+	"flag"
 	_ "image/png"
 	"log"
+	"time"
 
+	"github.com/demouth/ebitengine-sketch/lib/shaderfile"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 var (
 	//go:embed shader.kage
 	shader_kage []byte
+
+	shaderPath = flag.String("shader", "", "a .kage file to run instead of the embedded shader; reloaded when it changes")
+	presetName = flag.String("preset", "", "a preset saved by the 018 editor to run instead of the embedded shader")
 )
 
 const (
@@ -33,15 +39,25 @@ const (
 )
 
 type Game struct {
-	shader *ebiten.Shader
-	idx    int
-	time   int
+	shader  *ebiten.Shader
+	source  []byte
+	watcher *shaderfile.Watcher
+	idx     int
+	time    int
 }
 
 func (g *Game) Update() error {
 	g.time++
+	if g.watcher != nil {
+		src, changed, err := g.watcher.Poll()
+		if err != nil {
+			log.Println(err)
+		} else if changed {
+			g.reload(src)
+		}
+	}
 	if g.shader == nil {
-		s, err := ebiten.NewShader([]byte(shader_kage))
+		s, err := ebiten.NewShader(g.source)
 		if err != nil {
 			return err
 		}
@@ -50,6 +66,19 @@ func (g *Game) Update() error {
 	return nil
 }
 
+// reload keeps the running shader when src doesn't compile.
+func (g *Game) reload(src []byte) {
+	s, err := ebiten.NewShader(src)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if g.shader != nil {
+		g.shader.Deallocate()
+	}
+	g.shader = s
+}
+
 func (g *Game) Draw(screen *ebiten.Image) {
 	s := g.shader
 
@@ -73,9 +102,26 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 }
 
 func main() {
+	flag.Parse()
+	g := &Game{source: shader_kage}
+	if *presetName != "" {
+		dir, err := shaderfile.DefaultPresetDir()
+		if err != nil {
+			log.Fatal(err)
+		}
+		src, err := shaderfile.NewPresetStore(dir).Load(*presetName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		g.source = src
+	}
+	if *shaderPath != "" {
+		g.watcher = shaderfile.NewWatcher(*shaderPath, 500*time.Millisecond)
+	}
+
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("Shader (Ebitengine Demo)")
-	if err := ebiten.RunGame(&Game{}); err != nil {
+	if err := ebiten.RunGame(g); err != nil {
 		log.Fatal(err)
 	}
 }
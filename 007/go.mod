@@ -2,7 +2,10 @@ module github.com/demouth/ebitengine-sketch/007
 
 go 1.22.1
 
-require github.com/hajimehoshi/ebiten/v2 v2.7.4
+require (
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
+	github.com/hajimehoshi/ebiten/v2 v2.7.4
+)
 
 require (
 	github.com/ebitengine/gomobile v0.0.0-20240518074828-e86332849895 // indirect
@@ -12,3 +15,5 @@ require (
 	golang.org/x/sync v0.7.0 // indirect
 	golang.org/x/sys v0.20.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
@@ -7,7 +7,9 @@ import (
 	"log"
 	"math/rand/v2"
 
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/examples/resources/images"
 
 	"github.com/jakecoffman/cp/v2"
@@ -34,12 +36,13 @@ type Game struct {
 	count int
 
 	space *cp.Space
+	rand  *rand.Rand
 }
 
 func (g *Game) Update() error {
 	g.count++
 	if g.count%20 == 0 {
-		addCircle(g.space, 10, rand.Float64()*10-5, -screenHeight/2+10)
+		addCircle(g.space, 10, g.rand.Float64()*10-5, -screenHeight/2+10)
 	}
 	g.space.Step(1.0 / 60.0)
 	return nil
@@ -62,6 +65,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			screen.DrawImage(runnerImage.SubImage(image.Rect(sx, sy, sx+frameWidth, sy+frameHeight)).(*ebiten.Image), op)
 		}
 	})
+	ebitenutil.DebugPrint(screen, seed.Label())
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
@@ -70,6 +74,7 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 
 func main() {
 	game := &Game{}
+	game.rand = rand.New(rand.NewPCG(uint64(seed.Get()), 0))
 
 	space := cp.NewSpace()
 	space.SetGravity(cp.Vector{X: 0, Y: 200})
@@ -90,7 +95,7 @@ func main() {
 	}
 
 	for i := 0; i < 1; i++ {
-		addCircle(space, 10, rand.Float64()*2-1, rand.Float64()*2-1)
+		addCircle(space, 10, game.rand.Float64()*2-1, game.rand.Float64()*2-1)
 	}
 
 	// Decode an image from the image file's byte slice.
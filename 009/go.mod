@@ -3,6 +3,7 @@ module github.com/demouth/ebitengine-sketch/009
 go 1.22.1
 
 require (
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.7.8
 	github.com/jakecoffman/cp/v2 v2.0.2
 )
@@ -15,3 +16,5 @@ require (
 	golang.org/x/sync v0.7.0 // indirect
 	golang.org/x/sys v0.20.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
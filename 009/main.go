@@ -8,6 +8,7 @@ import (
 	"log"
 	"math/rand/v2"
 
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/examples/resources/images"
@@ -70,6 +71,7 @@ type Game struct {
 	space *cp.Space
 
 	ebitencp *Ebitencp
+	rand     *rand.Rand
 }
 
 func (g *Game) Update() error {
@@ -77,9 +79,9 @@ func (g *Game) Update() error {
 	if g.numShapes < 150 && g.count%15 == 0 {
 
 		g.numShapes++
-		addCircle(g.space, rand.Float64()*15+10, rand.Float64()*10, rand.Float64()*2-150)
+		addCircle(g.space, g.rand.Float64()*15+10, g.rand.Float64()*10, g.rand.Float64()*2-150)
 		g.numShapes++
-		addBox(g.space, rand.Float64()*15+10, rand.Float64()*15+10, rand.Float64()*10, rand.Float64()*2-150)
+		addBox(g.space, g.rand.Float64()*15+10, g.rand.Float64()*15+10, g.rand.Float64()*10, g.rand.Float64()*2-150)
 	}
 	g.space.Step(1.0 / 60.0)
 	return nil
@@ -106,9 +108,10 @@ func (g *Game) Draw(screen *ebiten.Image) {
 
 	g.ebitencp.Draw(screen, g.space)
 	msg := fmt.Sprintf(
-		"FPS: %0.2f\nNum Circles: %d",
+		"FPS: %0.2f\nNum Circles: %d\n%s",
 		ebiten.ActualFPS(),
 		g.numShapes,
+		seed.Label(),
 	)
 	ebitenutil.DebugPrint(screen, msg)
 }
@@ -119,6 +122,7 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 
 func main() {
 	game := &Game{}
+	game.rand = rand.New(rand.NewPCG(uint64(seed.Get()), 0))
 
 	game.ebitencp = &Ebitencp{}
 
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"github.com/demouth/ebitengine-sketch/lib/ebitencp"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Breakable is the UserData of a joint that breaks when the force on it
+// reaches MaxForce.
+type Breakable struct {
+	MaxForce float64
+	// Load is the force on the joint at the last step, against MaxForce.
+	Load float64
+	// Broken, when set, is called once the joint is gone.
+	Broken JointBroken
+
+	// a and b are the bodies of the joint, which it doesn't tell.
+	a, b *cp.Body
+	// impulse is what broke the joint, when it is breaking.
+	impulse  float64
+	breaking bool
+}
+
+// JointBroken is told where joint broke, in the space, and the impulse of
+// the step that broke it.
+type JointBroken func(space *cp.Space, joint *cp.Constraint, pos cp.Vector, impulse float64)
+
+// NewBreakable makes joint between a and b break at maxForce, calling
+// broken when it does. a and b are in the order the joint was made with,
+// and NewBreakable panics if the joint isn't on both of them.
+func NewBreakable(joint *cp.Constraint, a, b *cp.Body, maxForce float64, broken JointBroken) *Breakable {
+	if !holds(a, joint) || !holds(b, joint) {
+		panic("NewBreakable: the joint isn't between a and b")
+	}
+	br := &Breakable{MaxForce: maxForce, Broken: broken, a: a, b: b}
+	joint.UserData = br
+	joint.PostSolve = BreakableJointPostSolve
+	return br
+}
+
+// holds reports whether joint is one of the constraints of body.
+func holds(body *cp.Body, joint *cp.Constraint) bool {
+	found := false
+	body.EachConstraint(func(c *cp.Constraint) {
+		if c == joint {
+			found = true
+		}
+	})
+	return found
+}
+
+func BreakableJointPostStepRemove(space *cp.Space, joint interface{}, _ interface{}) {
+	c := joint.(*cp.Constraint)
+	space.RemoveConstraint(c)
+	if b, ok := c.UserData.(*Breakable); ok && b.Broken != nil {
+		pa, pb := b.anchors(c)
+		b.Broken(space, c, pa.Lerp(pb, 0.5), b.impulse)
+	}
+}
+
+func BreakableJointPostSolve(joint *cp.Constraint, space *cp.Space) {
+	b, ok := joint.UserData.(*Breakable)
+	if !ok || b.breaking {
+		return
+	}
+
+	// Convert the impulse to a force by dividing it by the timestep.
+	impulse := joint.Class.GetImpulse()
+	force := impulse / space.TimeStep()
+	b.Load = force / b.MaxForce
+
+	if force >= b.MaxForce {
+		b.breaking = true
+		b.impulse = impulse
+		space.AddPostStepCallback(BreakableJointPostStepRemove, joint, nil)
+	}
+}
+
+// anchors returns where joint holds its two bodies, in the space, or the
+// bodies themselves for joints without anchors.
+func (br *Breakable) anchors(joint *cp.Constraint) (cp.Vector, cp.Vector) {
+	a, b := br.a, br.b
+	switch j := joint.Class.(type) {
+	case *cp.SlideJoint:
+		return a.LocalToWorld(j.AnchorA), b.LocalToWorld(j.AnchorB)
+	case *cp.PinJoint:
+		return a.LocalToWorld(j.AnchorA), b.LocalToWorld(j.AnchorB)
+	case *cp.PivotJoint:
+		return a.LocalToWorld(j.AnchorA), b.LocalToWorld(j.AnchorB)
+	}
+	return a.Position(), b.Position()
+}
+
+// drawStress adds the breakable joints of space to d, from green to red
+// as they get closer to breaking. They show up at the next Flush.
+func drawStress(d *ebitencp.Drawer, space *cp.Space) {
+	space.EachConstraint(func(c *cp.Constraint) {
+		b, ok := c.UserData.(*Breakable)
+		if !ok {
+			return
+		}
+		color := ebitencp.Ramp(b.Load)
+		pa, pb := b.anchors(c)
+		d.DrawFatSegment(pa, pb, 2, color, color, nil)
+	})
+}
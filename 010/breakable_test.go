@@ -0,0 +1,176 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+const (
+	testGravity = 100.0
+	testLinks   = 3
+	testSpacing = 10.0
+)
+
+type broken struct {
+	joint   *cp.Constraint
+	pos     cp.Vector
+	impulse float64
+	step    int
+}
+
+// hangingChain returns a space with a chain of links of mass 1 hanging
+// from the origin, every joint breaking at maxForce, and the joints from
+// the top down.
+func hangingChain(maxForce float64, breaks *[]broken, step *int) (*cp.Space, []*cp.Body, []*cp.Constraint) {
+	space := cp.NewSpace()
+	space.Iterations = 30
+	space.SetGravity(cp.Vector{Y: -testGravity})
+
+	var links []*cp.Body
+	var joints []*cp.Constraint
+	prev := space.StaticBody
+	for i := 0; i < testLinks; i++ {
+		body := space.AddBody(cp.NewBody(1, cp.MomentForCircle(1, 0, 2, cp.Vector{})))
+		body.SetPosition(cp.Vector{Y: -testSpacing * float64(i+1)})
+		joint := space.AddConstraint(cp.NewSlideJoint(prev, body, cp.Vector{}, cp.Vector{}, 0, testSpacing))
+		NewBreakable(joint, prev, body, maxForce, func(space *cp.Space, joint *cp.Constraint, pos cp.Vector, impulse float64) {
+			*breaks = append(*breaks, broken{joint, pos, impulse, *step})
+		})
+		links = append(links, body)
+		joints = append(joints, joint)
+		prev = body
+	}
+	return space, links, joints
+}
+
+func TestChainBreaks(t *testing.T) {
+	const (
+		dt       = 1 / 60.0
+		maxForce = 20 * testGravity
+	)
+	var breaks []broken
+	step := 0
+	space, links, joints := hangingChain(maxForce, &breaks, &step)
+
+	// the bottom link weighs step, so the top joint holds up 2+step and
+	// breaks when that reaches 20
+	want := 18
+	weight := links[len(links)-1]
+	for step = 1; step <= 30 && len(breaks) == 0; step++ {
+		weight.SetMass(float64(step))
+		space.Step(dt)
+	}
+
+	if len(breaks) != 1 {
+		t.Fatalf("%d joints broke, want 1", len(breaks))
+	}
+	b := breaks[0]
+	if b.joint != joints[0] {
+		t.Errorf("joint %v broke, want the top one", b.joint)
+	}
+	if b.step < want-1 || b.step > want+1 {
+		t.Errorf("broke at step %d, want %d", b.step, want)
+	}
+	if b.impulse < maxForce*dt {
+		t.Errorf("broke at an impulse of %v, under %v", b.impulse, maxForce*dt)
+	}
+	// halfway between the origin and the top link
+	if want := (cp.Vector{Y: -testSpacing / 2}); b.pos.Distance(want) > 1 {
+		t.Errorf("broke at %v, want %v", b.pos, want)
+	}
+	if space.ContainsConstraint(joints[0]) {
+		t.Errorf("the broken joint is still in the space")
+	}
+	for _, j := range joints[1:] {
+		if !space.ContainsConstraint(j) {
+			t.Errorf("a lower joint is gone too")
+		}
+	}
+}
+
+// TestBreakPosition hangs a link turned a quarter from the origin, with
+// the joint made both ways round. The link can't spin, so where the joint
+// breaks tells which anchor went with which body.
+func TestBreakPosition(t *testing.T) {
+	for _, swapped := range []bool{false, true} {
+		space := cp.NewSpace()
+		space.SetGravity(cp.Vector{Y: -testGravity})
+		body := space.AddBody(cp.NewBody(1, cp.INFINITY))
+		body.SetPosition(cp.Vector{Y: -2 * testSpacing})
+		body.SetAngle(math.Pi / 2)
+
+		// the top of the link, before it is turned
+		a, b := space.StaticBody, body
+		anchorA, anchorB := cp.Vector{}, cp.Vector{X: testSpacing}
+		if swapped {
+			a, b = b, a
+			anchorA, anchorB = anchorB, anchorA
+		}
+		joint := space.AddConstraint(cp.NewSlideJoint(a, b, anchorA, anchorB, 0, testSpacing))
+		var breaks []cp.Vector
+		NewBreakable(joint, a, b, testGravity/2, func(space *cp.Space, joint *cp.Constraint, pos cp.Vector, impulse float64) {
+			breaks = append(breaks, pos)
+		})
+		for i := 0; i < 10 && len(breaks) == 0; i++ {
+			space.Step(1 / 60.0)
+		}
+
+		if len(breaks) != 1 {
+			t.Fatalf("swapped %v: %d breaks, want 1", swapped, len(breaks))
+		}
+		// halfway between the origin and the top of the link
+		if want := (cp.Vector{Y: -testSpacing / 2}); breaks[0].Distance(want) > 1 {
+			t.Errorf("swapped %v: broke at %v, want %v", swapped, breaks[0], want)
+		}
+	}
+}
+
+func TestBreakableOtherBody(t *testing.T) {
+	space := cp.NewSpace()
+	a := space.AddBody(cp.NewBody(1, 1))
+	b := space.AddBody(cp.NewBody(1, 1))
+	other := space.AddBody(cp.NewBody(1, 1))
+	joint := space.AddConstraint(cp.NewPinJoint(a, b, cp.Vector{}, cp.Vector{}))
+	defer func() {
+		if recover() == nil {
+			t.Errorf("no panic for a body the joint isn't on")
+		}
+	}()
+	NewBreakable(joint, a, other, 1, nil)
+}
+
+func TestChainLoad(t *testing.T) {
+	const maxForce = 20 * testGravity
+	var breaks []broken
+	step := 0
+	space, _, joints := hangingChain(maxForce, &breaks, &step)
+	for i := 0; i < 10; i++ {
+		space.Step(1 / 60.0)
+	}
+	if len(breaks) != 0 {
+		t.Fatalf("%d joints broke under their own weight", len(breaks))
+	}
+	// each joint holds up the links below it
+	for i, j := range joints {
+		want := float64(testLinks-i) * testGravity / maxForce
+		if got := j.UserData.(*Breakable).Load; math.Abs(got-want) > 0.05 {
+			t.Errorf("joint %d at a load of %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestUnbreakable(t *testing.T) {
+	space := cp.NewSpace()
+	space.SetGravity(cp.Vector{Y: -testGravity})
+	body := space.AddBody(cp.NewBody(1000, 1))
+	joint := space.AddConstraint(cp.NewSlideJoint(space.StaticBody, body, cp.Vector{}, cp.Vector{}, 0, 0))
+	joint.PostSolve = BreakableJointPostSolve
+	for i := 0; i < 10; i++ {
+		space.Step(1 / 60.0)
+	}
+	if !space.ContainsConstraint(joint) {
+		t.Errorf("a joint without a Breakable broke")
+	}
+}
@@ -3,6 +3,7 @@ module github.com/demouth/ebitengine-sketch/010
 go 1.22.1
 
 require (
+	github.com/demouth/ebitengine-sketch/lib/ebitencp v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.3.0.20240811190802-435c8b75ebc0
 	github.com/jakecoffman/cp/v2 v2.0.2
 )
@@ -15,3 +16,5 @@ require (
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.24.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib/ebitencp => ../lib/ebitencp
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	_ "image/png"
 	"log"
+	"math"
+	"math/rand/v2"
 
-	"github.com/demouth/ebitengine-sketch/010/ebitencp"
+	"github.com/demouth/ebitengine-sketch/lib/ebitencp"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 
@@ -28,22 +30,73 @@ type Game struct {
 	count  int
 	space  *cp.Space
 	drawer *ebitencp.Drawer
+	debris []debris
+	rand   *rand.Rand
+}
+
+// debris flies off a joint when it breaks.
+type debris struct {
+	pos, vel cp.Vector
+	life     float64
+}
+
+const (
+	debrisLife = 0.6
+	// debrisSeed seeds the directions and speeds of the debris, so that
+	// it flies the same way every run.
+	debrisSeed = 10
+)
+
+// shatter throws debris out of a broken joint, more for a harder break.
+func (g *Game) shatter(space *cp.Space, joint *cp.Constraint, pos cp.Vector, impulse float64) {
+	n := 4 + int(impulse/200)
+	for i := 0; i < n; i++ {
+		dir := cp.ForAngle(g.rand.Float64() * 2 * math.Pi)
+		g.debris = append(g.debris, debris{
+			pos:  pos,
+			vel:  dir.Mult(50 + g.rand.Float64()*150),
+			life: debrisLife,
+		})
+	}
+}
+
+func (g *Game) updateDebris(dt float64) {
+	alive := g.debris[:0]
+	for _, p := range g.debris {
+		p.life -= dt
+		if p.life <= 0 {
+			continue
+		}
+		p.vel = p.vel.Add(g.space.Gravity().Mult(dt))
+		p.pos = p.pos.Add(p.vel.Mult(dt))
+		alive = append(alive, p)
+	}
+	g.debris = alive
 }
 
 func (g *Game) Update() error {
 	g.drawer.HandleMouseEvent(g.space)
+	g.drawer.HandleCamera()
+	g.drawer.HandleOverlayKeys()
 
 	g.space.Step(1 / 60.0)
+	g.updateDebris(1 / 60.0)
 	return nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
 
 	g.drawer.Screen = screen
-	cp.DrawSpace(g.space, g.drawer)
+	g.drawer.DrawSpace(g.space)
+	drawStress(g.drawer, g.space)
+	for _, p := range g.debris {
+		g.drawer.DrawDot(2, p.pos, cp.FColor{R: 1, G: 0.8, B: 0.3, A: float32(p.life / debrisLife)}, nil)
+	}
+	g.drawer.Flush()
+	g.drawer.DrawOverlay(g.space)
 
 	msg := fmt.Sprintf(
-		"FPS: %0.2f",
+		"FPS: %0.2f\nF1-F6: debug overlay\nwheel: zoom, middle drag: pan",
 		ebiten.ActualFPS(),
 	)
 	ebitenutil.DebugPrint(screen, msg)
@@ -55,6 +108,9 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 
 func main() {
 
+	game := &Game{}
+	game.rand = rand.New(rand.NewPCG(debrisSeed, 0))
+
 	// chipmunk init
 
 	space := cp.NewSpace()
@@ -97,13 +153,15 @@ func main() {
 
 			var constraint *cp.Constraint
 			if prev == nil {
-				constraint = space.AddConstraint(cp.NewSlideJoint(body, space.StaticBody, cp.Vector{X: 0, Y: height / 2}, cp.Vector{X: pos.X, Y: 240}, 0, spacing))
+				prev = space.StaticBody
+				constraint = space.AddConstraint(cp.NewSlideJoint(body, prev, cp.Vector{X: 0, Y: height / 2}, cp.Vector{X: pos.X, Y: 240}, 0, spacing))
 			} else {
 				constraint = space.AddConstraint(cp.NewSlideJoint(body, prev, cp.Vector{X: 0, Y: height / 2}, cp.Vector{X: 0, Y: -height / 2}, 0, spacing))
 			}
 
+			// break a little before the joint gives in to its max force
 			constraint.SetMaxForce(breakingForce)
-			constraint.PostSolve = BreakableJointPostSolve
+			NewBreakable(constraint, body, prev, 0.9*breakingForce, game.shatter)
 			constraint.SetCollideBodies(false)
 
 			prev = body
@@ -121,7 +179,6 @@ func main() {
 
 	// ebitengine init
 
-	game := &Game{}
 	game.space = space
 	game.drawer = ebitencp.NewDrawer(screenWidth, screenHeight)
 
@@ -131,20 +188,3 @@ func main() {
 		log.Fatal(err)
 	}
 }
-
-func BreakableJointPostStepRemove(space *cp.Space, joint interface{}, _ interface{}) {
-	space.RemoveConstraint(joint.(*cp.Constraint))
-}
-
-func BreakableJointPostSolve(joint *cp.Constraint, space *cp.Space) {
-	dt := space.TimeStep()
-
-	// Convert the impulse to a force by dividing it by the timestep.
-	force := joint.Class.GetImpulse() / dt
-	maxForce := joint.MaxForce()
-
-	// If the force is almost as big as the joint's max force, break it.
-	if force > 0.9*maxForce {
-		space.AddPostStepCallback(BreakableJointPostStepRemove, joint, nil)
-	}
-}
@@ -7,36 +7,28 @@ import (
 	_ "image/png"
 	"log"
 
+	"github.com/demouth/ebitengine-sketch/lib/outline"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
 )
 
-var (
-	appleImage      *ebiten.Image
-	grapeImage      *ebiten.Image
-	orangeImage     *ebiten.Image
-	pineappleImage  *ebiten.Image
-	melonImage      *ebiten.Image
-	watermelonImage *ebiten.Image
-
-	applePngImage      image.Image
-	grapePngImage      image.Image
-	orangePngImage     image.Image
-	pineapplePngImage  image.Image
-	melonPngImage      image.Image
-	watermelonPngImage image.Image
+//go:generate go run github.com/demouth/ebitengine-sketch/lib/outline/cmd/outline -o assets/outlines.json assets/apple.png assets/avocado.png assets/kiwi.png assets/melon.png assets/strawberry.png assets/watermelon.png
 
+var (
 	//go:embed assets/apple.png
 	apple_png []byte
-	//go:embed assets/avacado.png
-	grape_png []byte
+	//go:embed assets/avocado.png
+	avocado_png []byte
 	//go:embed assets/kiwi.png
-	orange_png []byte
-	//go:embed assets/strawberry.png
-	pineapple_png []byte
+	kiwi_png []byte
 	//go:embed assets/melon.png
 	melon_png []byte
+	//go:embed assets/strawberry.png
+	strawberry_png []byte
 	//go:embed assets/watermelon.png
 	watermelon_png []byte
+	//go:embed assets/outlines.json
+	outlines_json []byte
 
 	assets map[int]ImageSet
 )
@@ -44,23 +36,44 @@ var (
 type ImageSet struct {
 	EbitenImage *ebiten.Image
 	Image       image.Image
+	// Outline is the outline of Image, in its pixels.
+	Outline *outline.Outline
+	// Pieces are the convex pieces of the outline of Image, centred on the
+	// origin.
+	Pieces [][]cp.Vector
 }
 
 func init() {
-	applePngImage, appleImage = loadImage(apple_png)
-	grapePngImage, grapeImage = loadImage(grape_png)
-	pineapplePngImage, pineappleImage = loadImage(pineapple_png)
-	watermelonPngImage, watermelonImage = loadImage(watermelon_png)
-	// orangePngImage, orangeImage = loadImage(orange_png)
-	// melonPngImage, melonImage = loadImage(melon_png)
+	outlines, err := outline.Load(outlines_json)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	assets = map[int]ImageSet{
-		0: {EbitenImage: appleImage, Image: applePngImage},
-		1: {EbitenImage: grapeImage, Image: grapePngImage},
-		2: {EbitenImage: pineappleImage, Image: pineapplePngImage},
-		3: {EbitenImage: watermelonImage, Image: watermelonPngImage},
-		// 4: {EbitenImage: orangeImage, Image: orangePngImage},
-		// 5: {EbitenImage: melonImage, Image: melonPngImage},
+	// the names are the names of the files, which outlines.json is keyed by
+	files := []struct {
+		name string
+		png  []byte
+	}{
+		{"apple", apple_png},
+		{"avocado", avocado_png},
+		{"kiwi", kiwi_png},
+		{"strawberry", strawberry_png},
+		{"melon", melon_png},
+		{"watermelon", watermelon_png},
+	}
+	assets = map[int]ImageSet{}
+	for i, f := range files {
+		img, ebitenImage := loadImage(f.png)
+		o, ok := outlines[f.name]
+		if !ok {
+			log.Fatalf("no outline for %s, run go generate", f.name)
+		}
+		assets[i] = ImageSet{
+			EbitenImage: ebitenImage,
+			Image:       img,
+			Outline:     o,
+			Pieces:      o.Centered(1),
+		}
 	}
 }
 
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/demouth/ebitengine-sketch/lib/outline"
+)
+
+// minCoverage is the share of the opaque pixels of an image that its
+// outline has to cover.
+const minCoverage = 0.95
+
+func TestOutlines(t *testing.T) {
+	outlines, err := outline.Load(outlines_json)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outlines) != len(assets) {
+		t.Errorf("%d outlines, want %d", len(outlines), len(assets))
+	}
+	for i := 0; i < len(assets); i++ {
+		is := assets[i]
+		for j, p := range is.Pieces {
+			if !outline.Convex(p) {
+				t.Errorf("fruit %d: piece %d is not convex and counter-clockwise: %v", i, j, p)
+			}
+		}
+		if c := is.Outline.Coverage(is.Image, 0.5); c < minCoverage {
+			t.Errorf("fruit %d: %.1f%% of the opaque pixels covered, want %.0f%%", i, c*100, minCoverage*100)
+		}
+	}
+}
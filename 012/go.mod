@@ -4,6 +4,8 @@ go 1.22.6
 
 require (
 	github.com/demouth/ebitencp v1.0.1
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
+	github.com/demouth/ebitengine-sketch/lib/outline v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.3.0.20240811190802-435c8b75ebc0
 	github.com/jakecoffman/cp/v2 v2.0.2
 )
@@ -16,3 +18,7 @@ require (
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.24.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
+
+replace github.com/demouth/ebitengine-sketch/lib/outline => ../lib/outline
@@ -4,13 +4,13 @@ package main
 
 import (
 	"fmt"
-	"image"
 	_ "image/png"
 	"log"
 	"math"
 	"math/rand"
 
 	"github.com/demouth/ebitencp"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 
@@ -29,12 +29,13 @@ type Game struct {
 	numFruits int
 	space     *cp.Space
 	drawer    *ebitencp.Drawer
+	rand      *rand.Rand
 }
 
 func (g *Game) Update() error {
 	g.count++
 	if g.numFruits < 400 && g.count%2 == 0 {
-		addRandomFruit(g.space)
+		addRandomFruit(g.space, g.rand)
 		g.numFruits++
 	}
 
@@ -48,32 +49,34 @@ func (g *Game) Draw(screen *ebiten.Image) {
 
 	g.drawer.Screen = screen
 
-	g.space.EachShape(func(shape *cp.Shape) {
-		switch shape.Class.(type) {
-		case *cp.PolyShape:
-			circle := shape.Class.(*cp.PolyShape)
-			vec := circle.Body().Position()
-
-			img := GetImageSet(circle.Body().UserData.(int)).EbitenImage
-			size := img.Bounds().Size()
-
-			op := &ebiten.DrawImageOptions{}
-			op.Filter = ebiten.FilterLinear
-			op.GeoM.Scale(-1, 1)
-			op.GeoM.Translate(float64(size.X), 0)
-			op.GeoM.Translate(-float64(size.X)/2, -float64(size.Y)/2)
-			op.GeoM.Rotate(-circle.Body().Angle() + math.Pi)
-			op.GeoM.Translate(screenWidth/2, screenHeight/2)
-			op.GeoM.Translate(vec.X, -vec.Y)
-			screen.DrawImage(img, op)
+	// a fruit is made of several shapes, but has one body
+	g.space.EachBody(func(body *cp.Body) {
+		tp, ok := body.UserData.(int)
+		if !ok {
+			return
 		}
+		vec := body.Position()
+
+		img := GetImageSet(tp).EbitenImage
+		size := img.Bounds().Size()
+
+		op := &ebiten.DrawImageOptions{}
+		op.Filter = ebiten.FilterLinear
+		op.GeoM.Scale(-1, 1)
+		op.GeoM.Translate(float64(size.X), 0)
+		op.GeoM.Translate(-float64(size.X)/2, -float64(size.Y)/2)
+		op.GeoM.Rotate(-body.Angle() + math.Pi)
+		op.GeoM.Translate(screenWidth/2, screenHeight/2)
+		op.GeoM.Translate(vec.X, -vec.Y)
+		screen.DrawImage(img, op)
 	})
 	// cp.DrawSpace(g.space, g.drawer)
 
 	msg := fmt.Sprintf(
-		"FPS: %0.2f\nFruit Count: %d",
+		"FPS: %0.2f\nFruit Count: %d\n%s",
 		ebiten.ActualFPS(),
 		g.numFruits,
+		seed.Label(),
 	)
 	ebitenutil.DebugPrint(screen, msg)
 }
@@ -107,6 +110,7 @@ func main() {
 
 	game := &Game{}
 	game.space = space
+	game.rand = seed.New()
 	game.drawer = ebitencp.NewDrawer(screenWidth, screenHeight)
 
 	ebiten.SetWindowSize(screenWidth, screenHeight)
@@ -116,42 +120,29 @@ func main() {
 	}
 }
 
-func addRandomFruit(space *cp.Space) {
-	j := rand.Intn(LenImageSet())
+func addRandomFruit(space *cp.Space, r *rand.Rand) {
+	j := r.Intn(LenImageSet())
 	is := GetImageSet(j)
-	addFruit(space, is.Image, j)
+	addFruit(space, r, is.Pieces, j)
 }
 
-func addFruit(space *cp.Space, img image.Image, tp int) {
-	b := img.Bounds()
-	bb := cp.BB{L: float64(b.Min.X), B: float64(b.Min.Y), R: float64(b.Max.X), T: float64(b.Max.Y)}
-
-	sampleFunc := func(point cp.Vector) float64 {
-		x := point.X
-		y := point.Y
-		rect := img.Bounds()
-
-		if x < float64(rect.Min.X) || x > float64(rect.Max.X) || y < float64(rect.Min.Y) || y > float64(rect.Max.Y) {
-			return 0.0
-		}
-		_, _, _, a := img.At(int(x), int(y)).RGBA()
-		return float64(a) / 0xffff
+// addFruit adds a body made of the convex pieces of an outline.
+func addFruit(space *cp.Space, r *rand.Rand, pieces [][]cp.Vector, tp int) {
+	area := 0.0
+	for _, p := range pieces {
+		area += cp.AreaForPoly(len(p), p, 0)
 	}
-
-	//lineSet := MarchHard(bb, 100, 100, 0.2, PolyLineCollectSegment, sampleFunc)
-	lineSet := cp.MarchSoft(bb, 300, 300, 0.5, cp.PolyLineCollectSegment, sampleFunc)
-
-	line := lineSet.Lines[0].SimplifyCurves(.9)
-	offset := cp.Vector{X: float64(b.Max.X-b.Min.X) / 2., Y: float64(b.Max.Y-b.Min.Y) / 2.}
-	// center the verts on origin
-	for i, l := range line.Verts {
-		line.Verts[i] = l.Sub(offset)
+	moment := 0.0
+	for _, p := range pieces {
+		moment += cp.MomentForPoly(10*cp.AreaForPoly(len(p), p, 0)/area, len(p), p, cp.Vector{}, 1)
 	}
 
-	body := space.AddBody(cp.NewBody(0.5, cp.MomentForPoly(10, len(line.Verts), line.Verts, cp.Vector{}, 1)))
-	body.SetPosition(cp.Vector{X: float64(rand.Intn(200) - 100), Y: float64(screenHeight)})
+	body := space.AddBody(cp.NewBody(0.5, moment))
+	body.SetPosition(cp.Vector{X: float64(r.Intn(200) - 100), Y: float64(screenHeight)})
 	body.UserData = tp
-	fruit := space.AddShape(cp.NewPolyShape(body, len(line.Verts), line.Verts, cp.NewTransformIdentity(), 0))
-	fruit.SetElasticity(.5)
-	fruit.SetFriction(0.7)
+	for _, p := range pieces {
+		fruit := space.AddShape(cp.NewPolyShape(body, len(p), p, cp.NewTransformIdentity(), 0))
+		fruit.SetElasticity(.5)
+		fruit.SetFriction(0.7)
+	}
 }
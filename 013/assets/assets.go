@@ -7,10 +7,13 @@ import (
 	_ "image/png"
 	"log"
 
+	"github.com/demouth/ebitengine-sketch/lib/outline"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/jakecoffman/cp/v2"
 )
 
+//go:generate go run github.com/demouth/ebitengine-sketch/lib/outline/cmd/outline -tolerance 2 -o outlines.json carrot.png cucumber.png eggplant.png onion.png pumpkin.png tomato.png whiteradish.png
+
 const (
 	Tomato Kind = 1 + iota
 	Onion
@@ -39,6 +42,8 @@ var (
 	carrot_png []byte
 	//go:embed whiteradish.png
 	whiteradish_png []byte
+	//go:embed outlines.json
+	outlines_json []byte
 
 	assets map[Kind]ImageSet
 )
@@ -60,8 +65,13 @@ type ImageSet struct {
 	EbitenImage *ebiten.Image
 	Image       image.Image
 	Scale       float64
-	Vectors     []cp.Vector
-	Score       int
+	// Outline is the outline of Image, in its pixels.
+	Outline *outline.Outline
+	// Pieces are the convex pieces of the outline, centred on the origin
+	// and scaled.
+	Pieces   [][]cp.Vector
+	Score    int
+	Material Material
 }
 
 func init() {
@@ -73,14 +83,23 @@ func init() {
 	eggplantPngImage, eggplantImage := loadImage(eggplant_png)
 	whiteradishPngImage, whiteradishImage := loadImage(whiteradish_png)
 
+	materials, err := loadMaterials(materials_json)
+	if err != nil {
+		log.Fatal(err)
+	}
+	outlines, err := outline.Load(outlines_json)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	assets = map[Kind]ImageSet{
-		Tomato:      makeImageSet(tomatoImage, tomatoPngImage, 0.4, 10),
-		Onion:       makeImageSet(onionImage, onionPngImage, 1.0, 20),
-		Eggplant:    makeImageSet(eggplantImage, eggplantPngImage, 1.2, 30),
-		Cucumber:    makeImageSet(cucumberImage, cucumberPngImage, 1.4, 40),
-		Carrot:      makeImageSet(carrotImage, carrotPngImage, 1.1, 50),
-		Pumpkin:     makeImageSet(pumpkinImage, pumpkinPngImage, 1.9, 60),
-		Whiteradish: makeImageSet(whiteradishImage, whiteradishPngImage, 1.2, 70),
+		Tomato:      makeImageSet(tomatoImage, tomatoPngImage, 0.4, 10, materials[Tomato], outlines[Tomato.String()]),
+		Onion:       makeImageSet(onionImage, onionPngImage, 1.0, 20, materials[Onion], outlines[Onion.String()]),
+		Eggplant:    makeImageSet(eggplantImage, eggplantPngImage, 1.2, 30, materials[Eggplant], outlines[Eggplant.String()]),
+		Cucumber:    makeImageSet(cucumberImage, cucumberPngImage, 1.4, 40, materials[Cucumber], outlines[Cucumber.String()]),
+		Carrot:      makeImageSet(carrotImage, carrotPngImage, 1.1, 50, materials[Carrot], outlines[Carrot.String()]),
+		Pumpkin:     makeImageSet(pumpkinImage, pumpkinPngImage, 1.9, 60, materials[Pumpkin], outlines[Pumpkin.String()]),
+		Whiteradish: makeImageSet(whiteradishImage, whiteradishPngImage, 1.2, 70, materials[Whiteradish], outlines[Whiteradish.String()]),
 	}
 }
 
@@ -125,40 +144,24 @@ func makeImageSet(
 	image image.Image,
 	scale float64,
 	score int,
+	material Material,
+	outline *outline.Outline,
 ) ImageSet {
+	// outlines.json is generated from the images; see go:generate above
+	if outline == nil {
+		log.Fatal("assets: an image has no outline, run go generate")
+	}
+	if s := image.Bounds().Size(); s.X != outline.Width || s.Y != outline.Height {
+		log.Fatalf("assets: a %dx%d image has a %dx%d outline, run go generate", s.X, s.Y, outline.Width, outline.Height)
+	}
 	is := ImageSet{
 		EbitenImage: ebitenImage,
 		Image:       image,
 		Scale:       scale,
-		Vectors:     makeVector(image, scale),
+		Outline:     outline,
+		Pieces:      outline.Centered(scale),
 		Score:       score,
+		Material:    material,
 	}
 	return is
 }
-
-func makeVector(img image.Image, scale float64) []cp.Vector {
-	b := img.Bounds()
-	bb := cp.BB{L: float64(b.Min.X), B: float64(b.Min.Y), R: float64(b.Max.X), T: float64(b.Max.Y)}
-
-	sampleFunc := func(point cp.Vector) float64 {
-		x := point.X
-		y := point.Y
-		rect := img.Bounds()
-
-		if x < float64(rect.Min.X) || x > float64(rect.Max.X) || y < float64(rect.Min.Y) || y > float64(rect.Max.Y) {
-			return 0.0
-		}
-		_, _, _, a := img.At(int(x), int(y)).RGBA()
-		return float64(a) / 0xffff
-	}
-
-	lineSet := cp.MarchSoft(bb, 300, 300, 0.5, cp.PolyLineCollectSegment, sampleFunc)
-
-	line := lineSet.Lines[0].SimplifyCurves(.9)
-	offset := cp.Vector{X: float64(b.Max.X-b.Min.X) / 2., Y: float64(b.Max.Y-b.Min.Y) / 2.}
-	// center the verts on origin
-	for i, l := range line.Verts {
-		line.Verts[i] = l.Sub(offset).Mult(scale)
-	}
-	return line.Verts
-}
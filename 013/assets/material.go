@@ -0,0 +1,78 @@
+package assets
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+//go:embed materials.json
+var materials_json []byte
+
+// Material is how a kind of fruit behaves in the space.
+type Material struct {
+	// Density is the mass per unit of area.
+	Density    float64 `json:"density"`
+	Friction   float64 `json:"friction"`
+	Elasticity float64 `json:"elasticity"`
+	// SurfaceVelocity moves what the fruit touches, like a conveyor belt.
+	SurfaceVelocity cp.Vector `json:"surfaceVelocity"`
+}
+
+var names = map[Kind]string{
+	Tomato:      "tomato",
+	Onion:       "onion",
+	Eggplant:    "eggplant",
+	Cucumber:    "cucumber",
+	Carrot:      "carrot",
+	Pumpkin:     "pumpkin",
+	Whiteradish: "whiteradish",
+}
+
+func (k Kind) String() string {
+	if n, ok := names[k]; ok {
+		return n
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// loadMaterials reads a table of materials by the name of the kind. Every
+// kind has to be in it.
+func loadMaterials(data []byte) (map[Kind]Material, error) {
+	var table map[string]Material
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("materials: %w", err)
+	}
+	m := map[Kind]Material{}
+	for k := Min; k <= Max; k++ {
+		mat, ok := table[k.String()]
+		if !ok {
+			return nil, fmt.Errorf("materials: no entry for %s", k)
+		}
+		if mat.Density <= 0 {
+			return nil, fmt.Errorf("materials: %s has a density of %v", k, mat.Density)
+		}
+		m[k] = mat
+		delete(table, k.String())
+	}
+	for name := range table {
+		return nil, fmt.Errorf("materials: unknown kind %q", name)
+	}
+	return m, nil
+}
+
+// Area returns the area of the outline, as it is scaled.
+func (is ImageSet) Area() float64 {
+	a := 0.0
+	for _, p := range is.Pieces {
+		a += cp.AreaForPoly(len(p), p, 0)
+	}
+	return a
+}
+
+// Mass returns the mass of a fruit of the outline and the material.
+func (is ImageSet) Mass() float64 {
+	return is.Area() * is.Material.Density
+}
@@ -0,0 +1,76 @@
+package assets
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+func TestMaterials(t *testing.T) {
+	m, err := loadMaterials(materials_json)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := Min; k <= Max; k++ {
+		mat, ok := m[k]
+		if !ok {
+			t.Errorf("no material for %s", k)
+			continue
+		}
+		if mat.Density <= 0 || mat.Friction < 0 || mat.Elasticity < 0 || mat.Elasticity > 1 {
+			t.Errorf("%s: %+v", k, mat)
+		}
+		if Get(k).Material != mat {
+			t.Errorf("%s: image set has %+v, want %+v", k, Get(k).Material, mat)
+		}
+	}
+	if len(m) != int(Max-Min)+1 {
+		t.Errorf("%d materials, want %d", len(m), int(Max-Min)+1)
+	}
+
+	errs := []string{
+		`[`,
+		`{"tomato":{"density":1}}`,
+		`{"tomato":{"density":0},"onion":{"density":1},"eggplant":{"density":1},"cucumber":{"density":1},"carrot":{"density":1},"pumpkin":{"density":1},"whiteradish":{"density":1}}`,
+		`{"tomato":{"density":1},"onion":{"density":1},"eggplant":{"density":1},"cucumber":{"density":1},"carrot":{"density":1},"pumpkin":{"density":1},"whiteradish":{"density":1},"melon":{"density":1}}`,
+	}
+	for _, data := range errs {
+		if _, err := loadMaterials([]byte(data)); err == nil {
+			t.Errorf("loadMaterials(%s): no error", data)
+		}
+	}
+}
+
+func TestMass(t *testing.T) {
+	for k := Min; k <= Max; k++ {
+		is := Get(k)
+		if is.Area() <= 0 {
+			t.Fatalf("%s: area %v", k, is.Area())
+		}
+		if got, want := is.Mass()/is.Area(), is.Material.Density; math.Abs(got-want) > 1e-12 {
+			t.Errorf("%s: mass per area %v, want the density %v", k, got, want)
+		}
+
+		// the same material on a bigger outline is heavier by the area
+		big := is
+		big.Pieces = nil
+		for _, p := range is.Pieces {
+			var piece []cp.Vector
+			for _, v := range p {
+				piece = append(piece, v.Mult(2))
+			}
+			big.Pieces = append(big.Pieces, piece)
+		}
+		if got, want := big.Mass(), is.Mass()*4; math.Abs(got-want) > want*1e-9 {
+			t.Errorf("%s: twice the size weighs %v, want %v", k, got, want)
+		}
+	}
+
+	// a pumpkin is heavier than a tomato of the same size
+	tomato, pumpkin := Get(Tomato), Get(Pumpkin)
+	pumpkin.Pieces = tomato.Pieces
+	if pumpkin.Mass() <= tomato.Mass() {
+		t.Errorf("pumpkin %v, tomato %v", pumpkin.Mass(), tomato.Mass())
+	}
+}
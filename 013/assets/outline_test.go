@@ -0,0 +1,32 @@
+package assets
+
+import (
+	"testing"
+
+	"github.com/demouth/ebitengine-sketch/lib/outline"
+)
+
+// minCoverage is the share of the opaque pixels of an image that its
+// outline has to cover.
+const minCoverage = 0.95
+
+func TestOutlines(t *testing.T) {
+	outlines, err := outline.Load(outlines_json)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outlines) != int(Max-Min)+1 {
+		t.Errorf("%d outlines, want %d", len(outlines), int(Max-Min)+1)
+	}
+	for k := Min; k <= Max; k++ {
+		is := Get(k)
+		for i, p := range is.Pieces {
+			if !outline.Convex(p) {
+				t.Errorf("%s: piece %d is not convex and counter-clockwise: %v", k, i, p)
+			}
+		}
+		if c := is.Outline.Coverage(is.Image, 0.5); c < minCoverage {
+			t.Errorf("%s: %.1f%% of the opaque pixels covered, want %.0f%%", k, c*100, minCoverage*100)
+		}
+	}
+}
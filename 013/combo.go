@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Combo multiplies the score of merges that chain: each merge within
+// Window ticks of the one before it adds one to the multiplier.
+type Combo struct {
+	Window int
+
+	count int
+	last  int
+}
+
+// Hit records a merge on frame and returns its multiplier.
+func (c *Combo) Hit(frame int) int {
+	if c.count > 0 && frame-c.last <= c.Window {
+		c.count++
+	} else {
+		c.count = 1
+	}
+	c.last = frame
+	return c.count
+}
+
+// Multiplier returns the multiplier the chain is at on frame, or 0 once it
+// has run out.
+func (c *Combo) Multiplier(frame int) int {
+	if c.count == 0 || frame-c.last > c.Window {
+		return 0
+	}
+	return c.count
+}
+
+func (c *Combo) Reset() {
+	c.count = 0
+}
+
+// Popup is the score of a merge, floating up from where it happened.
+type Popup struct {
+	Pos   cp.Vector
+	Text  string
+	Frame int
+}
+
+const popupLife = 60
+
+func popupText(points, multiplier int) string {
+	if multiplier > 1 {
+		return fmt.Sprintf("+%d x%d", points, multiplier)
+	}
+	return fmt.Sprintf("+%d", points)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/demouth/ebitengine-sketch/013/assets"
+	"github.com/jakecoffman/cp/v2"
+)
+
+func TestCombo(t *testing.T) {
+	c := Combo{Window: 10}
+	tests := []struct {
+		frame int
+		want  int
+	}{
+		{100, 1},
+		{105, 2},
+		{115, 3}, // just within the window of the last merge
+		{126, 1}, // just out of it
+		{126, 2}, // two merges in the same tick chain
+		{200, 1},
+	}
+	for _, tt := range tests {
+		if got := c.Hit(tt.frame); got != tt.want {
+			t.Errorf("Hit(%d) = %d, want %d", tt.frame, got, tt.want)
+		}
+	}
+	if m := c.Multiplier(210); m != 1 {
+		t.Errorf("Multiplier(210) = %d, want 1", m)
+	}
+	if m := c.Multiplier(211); m != 0 {
+		t.Errorf("Multiplier(211) = %d, want 0", m)
+	}
+}
+
+func TestComboScore(t *testing.T) {
+	w := NewWorld(1)
+	pos := cp.Vector{X: 100, Y: 500}
+	tomato, onion := assets.Tomato.Score(), assets.Onion.Score()
+
+	tests := []struct {
+		frame int
+		kind  assets.Kind
+		score int
+		popup string
+	}{
+		{10, assets.Tomato, tomato, popupText(tomato, 1)},
+		{50, assets.Onion, tomato + onion*2, popupText(onion*2, 2)},
+		{100, assets.Tomato, tomato + onion*2 + tomato*3, popupText(tomato*3, 3)},
+		{300, assets.Tomato, tomato + onion*2 + tomato*4, popupText(tomato, 1)},
+	}
+	for _, tt := range tests {
+		w.Frame = tt.frame
+		w.merged(tt.kind, pos)
+		if w.Score != tt.score {
+			t.Errorf("frame %d: score %d, want %d", tt.frame, w.Score, tt.score)
+		}
+		p := w.Popups[len(w.Popups)-1]
+		if p.Text != tt.popup || p.Frame != tt.frame || p.Pos != pos {
+			t.Errorf("frame %d: popup %+v, want %q", tt.frame, p, tt.popup)
+		}
+	}
+
+	// popups go away after popupLife ticks
+	w.Frame = 300 + popupLife
+	w.Step()
+	if len(w.Popups) != 0 {
+		t.Errorf("%d popups left", len(w.Popups))
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import "github.com/jakecoffman/cp/v2"
+
+// DangerLine ends the game when a fruit settles above the top of the
+// container and stays there for a while.
+type DangerLine struct {
+	Y float64
+	// MaxSpeed is how slow a fruit has to be to count as settled, so that
+	// fruit bouncing off the pile or just merged don't end the game.
+	MaxSpeed float64
+	// Grace is the number of ticks a settled fruit may stay above Y.
+	Grace int
+
+	ticks int
+}
+
+// Crossed reports whether a fruit at pos moving at vel is settled above
+// the line.
+func (d *DangerLine) Crossed(pos, vel cp.Vector) bool {
+	return pos.Y < d.Y && vel.Length() < d.MaxSpeed
+}
+
+// Update advances the timer by a tick and reports whether the game is
+// over. crossed is whether any fruit is settled above the line; when none
+// is, the timer starts over.
+func (d *DangerLine) Update(crossed bool) bool {
+	if !crossed {
+		d.ticks = 0
+		return false
+	}
+	d.ticks++
+	return d.ticks >= d.Grace
+}
+
+// Warning returns how far the timer has run, from 0 to 1.
+func (d *DangerLine) Warning() float64 {
+	if d.Grace <= 0 {
+		return 0
+	}
+	return min(float64(d.ticks)/float64(d.Grace), 1)
+}
+
+func (d *DangerLine) Reset() {
+	d.ticks = 0
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+func TestDangerLineCrossed(t *testing.T) {
+	d := DangerLine{Y: 200, MaxSpeed: 30, Grace: 3}
+	tests := []struct {
+		pos, vel cp.Vector
+		want     bool
+	}{
+		{cp.Vector{X: 100, Y: 150}, cp.Vector{}, true},
+		{cp.Vector{X: 100, Y: 150}, cp.Vector{X: 10, Y: -20}, true},
+		{cp.Vector{X: 100, Y: 150}, cp.Vector{X: 0, Y: -300}, false},
+		{cp.Vector{X: 100, Y: 200}, cp.Vector{}, false},
+		{cp.Vector{X: 100, Y: 400}, cp.Vector{}, false},
+	}
+	for _, tt := range tests {
+		if got := d.Crossed(tt.pos, tt.vel); got != tt.want {
+			t.Errorf("Crossed(%v, %v) = %v, want %v", tt.pos, tt.vel, got, tt.want)
+		}
+	}
+}
+
+func TestDangerLineTimer(t *testing.T) {
+	tests := []struct {
+		name    string
+		crossed []bool
+		want    []bool
+	}{
+		{"never", []bool{false, false, false, false}, []bool{false, false, false, false}},
+		{"stays", []bool{true, true, true, true}, []bool{false, false, true, true}},
+		{"leaves in time", []bool{true, true, false, true, true}, []bool{false, false, false, false, false}},
+		{"comes back", []bool{true, false, true, true, true}, []bool{false, false, false, false, true}},
+	}
+	for _, tt := range tests {
+		d := DangerLine{Grace: 3}
+		for i, crossed := range tt.crossed {
+			if got := d.Update(crossed); got != tt.want[i] {
+				t.Errorf("%s: tick %d: Update(%v) = %v, want %v", tt.name, i, crossed, got, tt.want[i])
+			}
+		}
+	}
+
+	d := DangerLine{Grace: 4}
+	d.Update(true)
+	if w := d.Warning(); w != 0.25 {
+		t.Errorf("Warning() = %v, want 0.25", w)
+	}
+	d.Reset()
+	if w := d.Warning(); w != 0 {
+		t.Errorf("Warning() after Reset = %v, want 0", w)
+	}
+}
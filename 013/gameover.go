@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"strings"
+
+	"github.com/demouth/ebitengine-sketch/013/ui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+func (g *Game) gameOver() {
+	w := g.world
+	if !g.replayed {
+		rank, err := g.scores.Add(Entry{Score: w.Score, Seed: w.Seed})
+		if err != nil {
+			log.Print(err)
+		}
+		g.rank = rank
+	}
+
+	g.status = ""
+	if path, err := saveReplay(w.Replay()); err != nil {
+		log.Print(err)
+	} else {
+		g.status = "replay saved to " + path
+	}
+	g.overUI = g.newGameOverUI()
+}
+
+// restart starts a new game with a new seed.
+func (g *Game) restart() {
+	g.world = NewWorld(newSeed())
+	g.replayed = false
+	g.overUI = nil
+}
+
+func (g *Game) newGameOverUI() ui.Components {
+	var table strings.Builder
+	for i, e := range g.scores.Entries {
+		mark := " "
+		if i == g.rank {
+			mark = ">"
+		}
+		fmt.Fprintf(&table, "%s%2d %6d  seed %d\n", mark, i+1, e.Score, e.Seed)
+	}
+	return ui.Components{
+		&ui.Label{X: screenWidth / 2, Y: 120, Text: "GAME OVER", FontSize: 32, Color: color.NRGBA{0xff, 0x66, 0x66, 0xff}, Align: text.AlignCenter},
+		&ui.Label{X: screenWidth / 2, Y: 180, Text: fmt.Sprintf("SCORE %d", g.world.Score), FontSize: 16, Align: text.AlignCenter},
+		&ui.Label{X: screenWidth / 2, Y: 240, Text: "HIGH SCORES", FontSize: 12, Align: text.AlignCenter},
+		&ui.Label{X: 50, Y: 270, Text: table.String(), FontSize: 12},
+		&ui.Label{X: screenWidth / 2, Y: 520, Text: g.status, FontSize: 8, Align: text.AlignCenter},
+		&ui.Button{X: screenWidth/2 - 170, Y: 560, Width: 160, Height: 60, FontSize: 14, Text: "Restart", OnMouseDown: func() {
+			g.restart()
+		}},
+		&ui.Button{X: screenWidth/2 + 10, Y: 560, Width: 160, Height: 60, FontSize: 14, Text: "Replay", OnMouseDown: func() {
+			g.play(g.world.Replay())
+		}},
+	}
+}
+
+func (g *Game) drawDangerLine(screen *ebiten.Image) {
+	w := g.world.danger.Warning()
+	if w == 0 {
+		return
+	}
+	// blinks faster as the timer runs out
+	if g.world.Frame/int(12-8*w)%2 == 1 {
+		return
+	}
+	_, y := g.drawer.WorldToScreen(cp.Vector{Y: g.world.danger.Y})
+	vector.StrokeLine(screen, 0, float32(y), screenWidth, float32(y), 5, color.NRGBA{0xff, 0x00, 0x00, 0xff}, true)
+}
+
+func (g *Game) drawGameOver(screen *ebiten.Image) {
+	vector.DrawFilledRect(screen, 0, 0, screenWidth, screenHeight, color.NRGBA{0x00, 0x00, 0x00, 0xc0}, false)
+	g.overUI.Draw(screen)
+}
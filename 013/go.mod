@@ -3,7 +3,9 @@ module github.com/demouth/ebitengine-sketch/013
 go 1.22.6
 
 require (
-	github.com/demouth/ebitencp v1.3.3
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
+	github.com/demouth/ebitengine-sketch/lib/ebitencp v0.0.0
+	github.com/demouth/ebitengine-sketch/lib/outline v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.3.0.20240811190802-435c8b75ebc0
 	github.com/jakecoffman/cp/v2 v2.0.2
 )
@@ -19,3 +21,9 @@ require (
 	golang.org/x/sys v0.24.0 // indirect
 	golang.org/x/text v0.16.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
+
+replace github.com/demouth/ebitengine-sketch/lib/ebitencp => ../lib/ebitencp
+
+replace github.com/demouth/ebitengine-sketch/lib/outline => ../lib/outline
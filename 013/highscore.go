@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const maxHighScores = 10
+
+// Store keeps the high score table between runs.
+type Store interface {
+	// Load returns what was saved last, or nil when nothing was.
+	Load() ([]byte, error)
+	Save(data []byte) error
+}
+
+type Entry struct {
+	Score int   `json:"score"`
+	Seed  int64 `json:"seed"`
+}
+
+// HighScores is the top ten, best first.
+type HighScores struct {
+	Entries []Entry
+	store   Store
+}
+
+// LoadHighScores reads the table from store. If it can't be read, the
+// table starts empty and the error is returned with it.
+func LoadHighScores(store Store) (*HighScores, error) {
+	h := &HighScores{store: store}
+	data, err := store.Load()
+	if err != nil {
+		return h, fmt.Errorf("high scores: %w", err)
+	}
+	if data == nil {
+		return h, nil
+	}
+	if err := json.Unmarshal(data, &h.Entries); err != nil {
+		h.Entries = nil
+		return h, fmt.Errorf("high scores: %w", err)
+	}
+	if len(h.Entries) > maxHighScores {
+		h.Entries = h.Entries[:maxHighScores]
+	}
+	return h, nil
+}
+
+// Add puts e in the table and saves it. It returns the place of e from 0,
+// or -1 if it didn't make the table. A score that ties with one already in
+// the table goes below it.
+func (h *HighScores) Add(e Entry) (int, error) {
+	if e.Score <= 0 {
+		return -1, nil
+	}
+	i := 0
+	for i < len(h.Entries) && h.Entries[i].Score >= e.Score {
+		i++
+	}
+	if i >= maxHighScores {
+		return -1, nil
+	}
+	h.Entries = append(h.Entries, Entry{})
+	copy(h.Entries[i+1:], h.Entries[i:])
+	h.Entries[i] = e
+	if len(h.Entries) > maxHighScores {
+		h.Entries = h.Entries[:maxHighScores]
+	}
+
+	data, err := json.Marshal(h.Entries)
+	if err != nil {
+		return i, err
+	}
+	if err := h.store.Save(data); err != nil {
+		return i, fmt.Errorf("high scores: %w", err)
+	}
+	return i, nil
+}
+
+// Best returns the top score, or 0.
+func (h *HighScores) Best() int {
+	if len(h.Entries) == 0 {
+		return 0
+	}
+	return h.Entries[0].Score
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+type memStore struct {
+	data []byte
+	err  error
+}
+
+func (m *memStore) Load() ([]byte, error) { return m.data, m.err }
+
+func (m *memStore) Save(data []byte) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.data = append([]byte(nil), data...)
+	return nil
+}
+
+func scoresOf(entries []Entry) []int {
+	var s []int
+	for _, e := range entries {
+		s = append(s, e.Score)
+	}
+	return s
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHighScoresAdd(t *testing.T) {
+	h, err := LoadHighScores(&memStore{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		score int
+		rank  int
+		want  []int
+	}{
+		{50, 0, []int{50}},
+		{80, 0, []int{80, 50}},
+		{60, 1, []int{80, 60, 50}},
+		{0, -1, []int{80, 60, 50}},
+		// a tie goes below
+		{60, 2, []int{80, 60, 60, 50}},
+		{10, 4, []int{80, 60, 60, 50, 10}},
+		{20, 4, []int{80, 60, 60, 50, 20, 10}},
+		{30, 4, []int{80, 60, 60, 50, 30, 20, 10}},
+		{40, 4, []int{80, 60, 60, 50, 40, 30, 20, 10}},
+		{70, 1, []int{80, 70, 60, 60, 50, 40, 30, 20, 10}},
+		{5, 9, []int{80, 70, 60, 60, 50, 40, 30, 20, 10, 5}},
+		// the table is full: the last one drops out
+		{90, 0, []int{90, 80, 70, 60, 60, 50, 40, 30, 20, 10}},
+		{10, -1, []int{90, 80, 70, 60, 60, 50, 40, 30, 20, 10}},
+		{3, -1, []int{90, 80, 70, 60, 60, 50, 40, 30, 20, 10}},
+		{15, 9, []int{90, 80, 70, 60, 60, 50, 40, 30, 20, 15}},
+	}
+	for _, tt := range tests {
+		rank, err := h.Add(Entry{Score: tt.score, Seed: int64(tt.score)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rank != tt.rank {
+			t.Errorf("Add(%d) = %d, want %d", tt.score, rank, tt.rank)
+		}
+		if got := scoresOf(h.Entries); !equal(got, tt.want) {
+			t.Fatalf("after Add(%d): %v, want %v", tt.score, got, tt.want)
+		}
+	}
+	if h.Best() != 90 {
+		t.Errorf("Best() = %d, want 90", h.Best())
+	}
+}
+
+func TestHighScoresStore(t *testing.T) {
+	store := &memStore{}
+	h, _ := LoadHighScores(store)
+	for _, s := range []int{30, 10, 20} {
+		h.Add(Entry{Score: s, Seed: 7})
+	}
+
+	loaded, err := LoadHighScores(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := scoresOf(loaded.Entries); !equal(got, []int{30, 20, 10}) {
+		t.Errorf("loaded %v, want [30 20 10]", got)
+	}
+	if loaded.Entries[0].Seed != 7 {
+		t.Errorf("seed %d, want 7", loaded.Entries[0].Seed)
+	}
+
+	// a broken store starts an empty table
+	for _, store := range []*memStore{{data: []byte("{")}, {err: errors.New("no storage")}} {
+		h, err := LoadHighScores(store)
+		if err == nil {
+			t.Errorf("%+v: no error", store)
+		}
+		if len(h.Entries) != 0 || h.Best() != 0 {
+			t.Errorf("%+v: %v, want no entries", store, h.Entries)
+		}
+	}
+
+	// more than ten saved are trimmed
+	store = &memStore{data: []byte(`[{"score":12},{"score":11},{"score":10},{"score":9},{"score":8},{"score":7},{"score":6},{"score":5},{"score":4},{"score":3},{"score":2}]`)}
+	h, err = LoadHighScores(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Entries) != maxHighScores {
+		t.Errorf("%d entries, want %d", len(h.Entries), maxHighScores)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/demouth/ebitengine-sketch/013/assets"
+	"github.com/demouth/ebitengine-sketch/013/ui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+var popupFace = ui.Face(14)
+
+// drawQueue draws the next two fruits and the hold slot at the top right.
+func (g *Game) drawQueue(screen *ebiten.Image) {
+	q := g.world.Queue
+	ebitenutil.DebugPrintAt(screen, "HOLD", screenWidth-190, 8)
+	ebitenutil.DebugPrintAt(screen, "NEXT", screenWidth-110, 8)
+	if q.Hold != 0 {
+		g.drawPreview(screen, q.Hold, screenWidth-175, 60, q.CanSwap())
+	}
+	for i, k := range q.Next {
+		g.drawPreview(screen, k, screenWidth-95+float64(i)*50, 60, true)
+	}
+}
+
+// drawPreview draws k at half its size, dimmed when it can't be used yet.
+func (g *Game) drawPreview(screen *ebiten.Image, k assets.Kind, x, y float64, active bool) {
+	imgSet := assets.Get(k)
+	img := imgSet.EbitenImage
+	size := img.Bounds().Size()
+
+	op := &ebiten.DrawImageOptions{}
+	op.Filter = ebiten.FilterLinear
+	op.GeoM.Translate(-float64(size.X)/2, -float64(size.Y)/2)
+	// the same size whatever the kind, so that big fruit fit
+	s := 40 / float64(max(size.X, size.Y))
+	op.GeoM.Scale(s, s)
+	op.GeoM.Translate(x, y)
+	if !active {
+		op.ColorScale.ScaleAlpha(0.4)
+	}
+	screen.DrawImage(img, op)
+}
+
+// drawPopups draws the score of each merge floating up and fading out.
+func (g *Game) drawPopups(screen *ebiten.Image) {
+	w := g.world
+	for _, p := range w.Popups {
+		t := float64(w.Frame-p.Frame) / popupLife
+		op := &text.DrawOptions{}
+		x, y := g.drawer.WorldToScreen(p.Pos)
+		op.GeoM.Translate(x, y-40*t)
+		op.ColorScale.ScaleWithColor(color.NRGBA{0xff, 0x66, 0x00, 0xff})
+		op.ColorScale.ScaleAlpha(float32(1 - t))
+		op.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, p.Text, popupFace, op)
+	}
+}
@@ -3,16 +3,17 @@ package main
 // This is based on "jakecoffman/cp-examples/march".
 
 import (
+	"flag"
 	"fmt"
 	"image/color"
 	_ "image/png"
 	"log"
-	"math"
-	"math/rand"
+	"time"
 
-	"github.com/demouth/ebitencp"
 	"github.com/demouth/ebitengine-sketch/013/assets"
 	"github.com/demouth/ebitengine-sketch/013/ui"
+	"github.com/demouth/ebitengine-sketch/lib/ebitencp"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -24,130 +25,134 @@ import (
 var (
 	bgImage       = ebiten.NewImage(100, 50)
 	whiteSubImage = ebiten.NewImage(3, 3)
-	score         = 0
-	hiscore       = 0
 )
 
 const (
 	screenWidth     = 480
 	screenHeight    = 800
 	containerHeight = 600
-	paddingBottom   = 100
+
+	// paddingBottom is the gap between the container floor and the bottom
+	// of the screen; the drawer's camera adds it when placing the world.
+	paddingBottom = 100
 )
 
 type Game struct {
-	count   int
-	space   *cp.Space
+	world   *World
 	drawer  *ebitencp.Drawer
-	next    next
 	buttons ui.Components
+	actions []Action
+
+	scores *HighScores
+	rank   int
+	overUI ui.Components
+	status string
+
+	player *Player
+	// replayed is set when the world came from a replay, whose score
+	// isn't a high score.
+	replayed    bool
+	paused      bool
+	speed       int
+	replayUI    ui.Components
+	replayLabel *ui.Label
 
 	debug bool
 }
 
-type next struct {
-	kind  assets.Kind
-	x     float64
-	y     float64
-	angle float64
-}
-
 func (g *Game) Update() error {
-	g.count++
-	g.space.EachBody(func(body *cp.Body) {
-		if body.Position().Y < screenHeight-containerHeight {
-			g.space.EachShape(func(shape *cp.Shape) {
-				if shape.Body().UserData != nil {
-					g.space.AddPostStepCallback(removeShapeCallback, shape, nil)
-				}
-				hiscore = int(math.Max(float64(score), float64(hiscore)))
-				score = 0
-			})
-			return
+	if g.player != nil {
+		g.updatePlayback()
+		return nil
+	}
+	if g.world.Over {
+		g.overUI.Update()
+		if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+			g.restart()
 		}
-	})
-	g.next.angle += 0.01
+		return nil
+	}
+
+	g.actions = g.actions[:0]
 	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-		g.drop()
+		g.actions = append(g.actions, ActionDrop)
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
-		g.moveRight()
+		g.actions = append(g.actions, ActionRight)
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
-		g.moveLeft()
+		g.actions = append(g.actions, ActionLeft)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyH) || inpututil.IsKeyJustPressed(ebiten.KeyShift) {
+		g.actions = append(g.actions, ActionHold)
 	}
 	g.buttons.Update()
-	g.drawer.HandleMouseEvent(g.space)
-	g.space.Step(1 / 60.0)
+	if g.debug {
+		// grabbing fruit with the mouse is not in the replay, so it is
+		// only for debugging
+		g.drawer.HandleMouseEvent(g.world.space)
+	}
+	g.world.Step(g.actions...)
+	if g.world.Over {
+		g.gameOver()
+	}
 	return nil
 }
 func (g *Game) Draw(screen *ebiten.Image) {
 	g.drawBackground(screen)
-	g.drawFruit(screen, g.next.kind, g.next.x, g.next.y-paddingBottom, g.next.angle)
-	g.space.EachShape(func(shape *cp.Shape) {
-		switch shape.Class.(type) {
-		case *cp.PolyShape:
-			circle := shape.Class.(*cp.PolyShape)
-			vec := circle.Body().Position()
-			g.drawFruit(screen, circle.Body().UserData.(assets.Kind), vec.X, vec.Y-paddingBottom, circle.Body().Angle())
-		}
+	w := g.world
+	g.drawFruit(screen, w.Queue.Current, cp.Vector{X: w.next.x, Y: w.next.y}, w.next.angle)
+	w.EachFruit(func(k assets.Kind, pos cp.Vector, angle float64) {
+		g.drawFruit(screen, k, pos, angle)
 	})
+	g.drawDangerLine(screen)
+	g.drawQueue(screen)
+	g.drawPopups(screen)
 	if g.debug {
-		cp.DrawSpace(g.space, g.drawer.WithScreen(screen))
+		g.drawer.Screen = screen
+		g.drawer.DrawSpace(w.space)
+	}
+	if g.player != nil {
+		g.replayUI.Draw(screen)
+	} else {
+		g.buttons.Draw(screen)
 	}
-	g.buttons.Draw(screen)
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
-		"FPS: %0.2f\nScore: %d\nHiScore: %d",
+		"FPS: %0.2f\nScore: %d\nHiScore: %d\nseed: %d",
 		ebiten.ActualFPS(),
-		score,
-		hiscore,
+		w.Score,
+		max(w.Score, g.scores.Best()),
+		w.Seed,
 	))
+	if w.Over && g.player == nil {
+		g.drawGameOver(screen)
+	}
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return screenWidth, screenHeight
 }
 
-func (g *Game) moveRight() {
-	if g.next.x < screenWidth-50 {
-		g.next.x += 4
-	}
-}
-func (g *Game) moveLeft() {
-	if g.next.x > 40 {
-		g.next.x -= 4
-	}
-}
-func (g *Game) drop() {
-	if g.count > 40 {
-		k := g.next.kind
-		addShapeOptions := addShapeOptions{
-			kind:  g.next.kind,
-			pos:   cp.Vector{X: g.next.x, Y: g.next.y},
-			angle: g.next.angle,
-		}
-		g.space.AddPostStepCallback(addShapeCallback, k, addShapeOptions)
-		g.count = 0
-		g.next.kind = assets.Kind(rand.Intn(2) + int(assets.Min))
-	}
-}
 func (g *Game) drawBackground(screen *ebiten.Image) {
 	screen.Fill(color.RGBA{0, 0, 0, 255})
 	screen.DrawImage(bgImage, nil)
 
 	var path vector.Path
 
+	left, floor := g.drawer.WorldToScreen(cp.Vector{X: 0, Y: screenHeight})
+	right, top := g.drawer.WorldToScreen(cp.Vector{X: screenWidth, Y: screenHeight - containerHeight})
+
 	path = vector.Path{}
-	path.MoveTo(0, 0)
-	path.LineTo(0, screenHeight-paddingBottom)
-	path.LineTo(screenWidth, screenHeight-paddingBottom)
-	path.LineTo(screenWidth, 0)
+	path.MoveTo(float32(left), 0)
+	path.LineTo(float32(left), float32(floor))
+	path.LineTo(float32(right), float32(floor))
+	path.LineTo(float32(right), 0)
 	g.drawFill(screen, path, color.NRGBA{0xff, 0xcc, 0x99, 0xff})
 	g.drawLine(screen, path, color.NRGBA{0xaa, 0x66, 0x33, 0xff}, 5)
 
 	path = vector.Path{}
-	path.MoveTo(0, screenHeight-containerHeight-paddingBottom)
-	path.LineTo(screenWidth, screenHeight-containerHeight-paddingBottom)
+	path.MoveTo(float32(left), float32(top))
+	path.LineTo(float32(right), float32(top))
 	g.drawLine(screen, path, color.NRGBA{0xdd, 0xaa, 0x99, 0xff}, 3)
 
 	path = vector.Path{}
@@ -195,57 +200,55 @@ func init() {
 	whiteSubImage.Fill(color.White)
 }
 
-func main() {
-
-	// chipmunk init
-
-	space := cp.NewSpace()
-	space.Iterations = 30
-	space.SetGravity(cp.Vector{X: 0, Y: 500})
-	space.SleepTimeThreshold = 0.5
-	space.SetDamping(1)
-
-	walls := []cp.Vector{
-		{X: 0, Y: 0}, {X: 0, Y: screenHeight},
-		{X: screenWidth, Y: 0}, {X: screenWidth, Y: screenHeight},
-		{X: 0, Y: screenHeight}, {X: screenWidth, Y: screenHeight},
-	}
-	for i := 0; i < len(walls)-1; i += 2 {
-		shape := space.AddShape(cp.NewSegment(space.StaticBody, walls[i], walls[i+1], 1))
-		shape.SetElasticity(0.6)
-		shape.SetFriction(0.4)
-	}
+// newSeed picks the seed of the next game after a restart.
+func newSeed() int64 {
+	return time.Now().UnixNano() % 1000000
+}
 
-	assets.ForEach(func(i assets.Kind, is assets.ImageSet) {
-		ct := cp.CollisionType(i)
-		space.NewCollisionHandler(ct, ct).BeginFunc = BeginFunc
-	})
+func main() {
+	flag.Parse()
 
 	// ebitengine init
 
 	bgImage.Fill(color.Black)
 
 	game := &Game{}
-	game.space = space
+	game.world = NewWorld(seed.Get())
+	scores, err := LoadHighScores(newStore())
+	if err != nil {
+		log.Print(err)
+	}
+	game.scores = scores
 	game.drawer = ebitencp.NewDrawer(screenWidth, screenHeight)
 	game.drawer.FlipYAxis = true
-	game.drawer.Camera.Offset = cp.Vector{X: screenWidth / 2, Y: screenHeight/2 + paddingBottom}
-	game.next = next{kind: assets.Tomato, x: screenWidth / 2, y: screenHeight - containerHeight + 10, angle: 0}
+	game.drawer.Camera.Position = cp.Vector{X: screenWidth / 2, Y: screenHeight/2 + paddingBottom}
 	game.buttons = ui.Components{
-		&ui.Button{X: 20, Y: screenHeight - 70, Width: 60, Height: 40, FontSize: 8, Text: "debug", OnMouseDown: func() {
+		&ui.Button{X: 20, Y: screenHeight - 85, Width: 60, Height: 30, FontSize: 8, Text: "hold", OnMouseDown: func() {
+			game.actions = append(game.actions, ActionHold)
+		}},
+		&ui.Button{X: 20, Y: screenHeight - 45, Width: 60, Height: 30, FontSize: 8, Text: "debug", OnMouseDown: func() {
 			game.debug = !game.debug
 		}},
 		&ui.Button{X: 120, Y: screenHeight - 80, Width: 80, Height: 60, FontSize: 14, Text: "<-", OnMouseDownHold: func() {
-			game.moveLeft()
+			game.actions = append(game.actions, ActionLeft)
 		}},
 		&ui.Button{X: 220, Y: screenHeight - 80, Width: 100, Height: 60, FontSize: 14, Text: "Drop", OnMouseDownHold: func() {
-			game.drop()
+			game.actions = append(game.actions, ActionDrop)
 		}},
 		&ui.Button{X: 340, Y: screenHeight - 80, Width: 80, Height: 60, FontSize: 14, Text: "->", OnMouseDownHold: func() {
-			game.moveRight()
+			game.actions = append(game.actions, ActionRight)
 		}},
 	}
 
+	replay, err := loadReplay()
+	if err != nil {
+		log.Print(err)
+	}
+	if replay != nil {
+		game.rank = -1
+		game.play(replay)
+	}
+
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("Ebitengine + Chipmunk Physics")
 	if err := ebiten.RunGame(game); err != nil {
@@ -253,29 +256,7 @@ func main() {
 	}
 }
 
-func addRandomFruit(space *cp.Space) {
-	j := assets.Tomato
-	pos := cp.Vector{X: screenWidth / 2, Y: screenHeight - containerHeight + 10}
-	addFruit(space, j, pos, rand.Float64()*math.Pi*2)
-}
-
-func addFruit(space *cp.Space, k assets.Kind, position cp.Vector, angle float64) {
-	if !assets.Exists(k) {
-		return
-	}
-	imgSet := assets.Get(k)
-
-	body := space.AddBody(cp.NewBody(0, cp.MomentForPoly(10, len(imgSet.Vectors), imgSet.Vectors, cp.Vector{}, 1)))
-	body.SetPosition(position)
-	body.SetAngle(angle)
-	body.UserData = k
-	fruit := space.AddShape(cp.NewPolyShape(body, len(imgSet.Vectors), imgSet.Vectors, cp.NewTransformIdentity(), 0))
-	body.SetMass(fruit.Area() * 0.001)
-	fruit.SetElasticity(0.2)
-	fruit.SetFriction(0.9)
-	fruit.SetCollisionType(cp.CollisionType(k))
-}
-func (g *Game) drawFruit(screen *ebiten.Image, kind assets.Kind, x, y, angle float64) {
+func (g *Game) drawFruit(screen *ebiten.Image, kind assets.Kind, pos cp.Vector, angle float64) {
 	imgSet := assets.Get(kind)
 	img := imgSet.EbitenImage
 	size := img.Bounds().Size()
@@ -285,62 +266,6 @@ func (g *Game) drawFruit(screen *ebiten.Image, kind assets.Kind, x, y, angle flo
 	op.GeoM.Translate(-float64(size.X)/2, -float64(size.Y)/2)
 	op.GeoM.Rotate(angle)
 	op.GeoM.Scale(imgSet.Scale, imgSet.Scale)
-	op.GeoM.Translate(x, y)
+	op.GeoM.Translate(g.drawer.WorldToScreen(pos))
 	screen.DrawImage(img, op)
 }
-
-type addShapeOptions struct {
-	kind  assets.Kind
-	pos   cp.Vector
-	angle float64
-}
-
-func addShapeCallback(space *cp.Space, key interface{}, data interface{}) {
-	var opt addShapeOptions
-	if i, ok := data.(addShapeOptions); ok {
-		opt = i
-	} else {
-		return
-	}
-	addFruit(space, opt.kind, opt.pos, opt.angle)
-}
-func removeShapeCallback(space *cp.Space, key interface{}, data interface{}) {
-	var s *cp.Shape
-	var ok bool
-	if s, ok = key.(*cp.Shape); !ok {
-		return
-	}
-	space.RemoveBody(s.Body())
-	space.RemoveShape(s)
-}
-func BeginFunc(arb *cp.Arbiter, space *cp.Space, data interface{}) bool {
-	shape, shape2 := arb.Shapes()
-
-	var k assets.Kind
-	if ud, ok := shape.Body().UserData.(assets.Kind); ok {
-		k = ud
-	} else {
-		return false
-	}
-
-	space.AddPostStepCallback(removeShapeCallback, shape, nil)
-	space.AddPostStepCallback(removeShapeCallback, shape2, nil)
-
-	score += k.Score()
-
-	if hasNext, kk := k.Next(); hasNext {
-		k = kk
-	} else {
-		return false
-	}
-	sp := shape.Body().Position().Clone()
-	sp.Sub(shape2.Body().Position()).Mult(0.5).Add(shape2.Body().Position())
-	a := (shape.Body().Angle() + shape2.Body().Angle()) / 2
-	addShapeOptions := addShapeOptions{
-		kind:  k,
-		pos:   sp,
-		angle: a,
-	}
-	space.AddPostStepCallback(addShapeCallback, k, addShapeOptions)
-	return false
-}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/demouth/ebitengine-sketch/013/ui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// play shows a replay. It runs at the fixed step of the world, one, two or
+// four ticks per frame.
+func (g *Game) play(r *Replay) {
+	g.player = NewPlayer(r)
+	g.world = g.player.World
+	g.replayed = true
+	g.rank = -1
+	g.paused = false
+	g.speed = 1
+	g.replayLabel = &ui.Label{X: screenWidth - 20, Y: 20, FontSize: 12, Align: text.AlignEnd}
+	g.replayUI = ui.Components{
+		g.replayLabel,
+		&ui.Button{X: 20, Y: screenHeight - 80, Width: 80, Height: 60, FontSize: 14, Text: "||", OnMouseDown: func() {
+			g.paused = !g.paused
+		}},
+		&ui.Button{X: 120, Y: screenHeight - 80, Width: 80, Height: 60, FontSize: 14, Text: "1x", OnMouseDown: func() {
+			g.speed = 1
+		}},
+		&ui.Button{X: 220, Y: screenHeight - 80, Width: 80, Height: 60, FontSize: 14, Text: "2x", OnMouseDown: func() {
+			g.speed = 2
+		}},
+		&ui.Button{X: 320, Y: screenHeight - 80, Width: 80, Height: 60, FontSize: 14, Text: "4x", OnMouseDown: func() {
+			g.speed = 4
+		}},
+	}
+}
+
+func (g *Game) updatePlayback() {
+	g.replayUI.Update()
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyP):
+		g.paused = !g.paused
+	case inpututil.IsKeyJustPressed(ebiten.Key1):
+		g.speed = 1
+	case inpututil.IsKeyJustPressed(ebiten.Key2):
+		g.speed = 2
+	case inpututil.IsKeyJustPressed(ebiten.Key4):
+		g.speed = 4
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		g.stopPlayback()
+		return
+	}
+
+	if !g.paused {
+		for i := 0; i < g.speed && !g.player.Done(); i++ {
+			g.player.Step()
+		}
+	}
+	if g.paused {
+		g.replayLabel.Text = "REPLAY PAUSED"
+	} else {
+		g.replayLabel.Text = fmt.Sprintf("REPLAY %dx", g.speed)
+	}
+	if g.player.Done() {
+		g.stopPlayback()
+	}
+}
+
+// stopPlayback leaves the replay. A run that isn't over goes on from where
+// the replay stopped, with the player in control.
+func (g *Game) stopPlayback() {
+	g.player = nil
+	if g.world.Over && g.overUI == nil {
+		g.overUI = g.newGameOverUI()
+	}
+}
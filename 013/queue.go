@@ -0,0 +1,61 @@
+package main
+
+import "github.com/demouth/ebitengine-sketch/013/assets"
+
+// Queue is the fruit in the dropper, the next two and the hold slot.
+type Queue struct {
+	Current assets.Kind
+	Next    [2]assets.Kind
+	// Hold is the kind in the hold slot, or 0 when it is empty.
+	Hold assets.Kind
+
+	pick func() assets.Kind
+	// held is set after a swap, until the next drop, so that the same two
+	// fruits can't be swapped back and forth.
+	held bool
+}
+
+func NewQueue(first assets.Kind, pick func() assets.Kind) *Queue {
+	q := &Queue{Current: first, pick: pick}
+	for i := range q.Next {
+		q.Next[i] = pick()
+	}
+	return q
+}
+
+// Pop returns the fruit to drop and moves the queue along.
+func (q *Queue) Pop() assets.Kind {
+	k := q.Current
+	q.Current = q.shift()
+	q.held = false
+	return k
+}
+
+func (q *Queue) shift() assets.Kind {
+	k := q.Next[0]
+	copy(q.Next[:], q.Next[1:])
+	q.Next[len(q.Next)-1] = q.pick()
+	return k
+}
+
+// Swap puts the current fruit in the hold slot and takes out what was
+// there, or the next fruit when it was empty. It can be done once per
+// drop; it reports whether it was done.
+func (q *Queue) Swap() bool {
+	if q.held {
+		return false
+	}
+	if q.Hold == 0 {
+		q.Hold = q.Current
+		q.Current = q.shift()
+	} else {
+		q.Hold, q.Current = q.Current, q.Hold
+	}
+	q.held = true
+	return true
+}
+
+// CanSwap reports whether Swap would be done.
+func (q *Queue) CanSwap() bool {
+	return !q.held
+}
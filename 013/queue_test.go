@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/demouth/ebitengine-sketch/013/assets"
+)
+
+// sequence returns a pick func that hands out kinds in order.
+func sequence(kinds ...assets.Kind) func() assets.Kind {
+	i := 0
+	return func() assets.Kind {
+		k := kinds[i%len(kinds)]
+		i++
+		return k
+	}
+}
+
+func TestQueue(t *testing.T) {
+	const (
+		T = assets.Tomato
+		O = assets.Onion
+		E = assets.Eggplant
+		C = assets.Cucumber
+		K = assets.Carrot
+	)
+	q := NewQueue(T, sequence(O, E, C, K))
+
+	type state struct {
+		current assets.Kind
+		next    [2]assets.Kind
+		hold    assets.Kind
+	}
+	tests := []struct {
+		op       string
+		wantPop  assets.Kind
+		wantSwap bool
+		want     state
+	}{
+		{"", 0, false, state{T, [2]assets.Kind{O, E}, 0}},
+		{"pop", T, false, state{O, [2]assets.Kind{E, C}, 0}},
+		// the first hold takes the next fruit
+		{"swap", 0, true, state{E, [2]assets.Kind{C, K}, O}},
+		// only once per drop
+		{"swap", 0, false, state{E, [2]assets.Kind{C, K}, O}},
+		{"pop", E, false, state{C, [2]assets.Kind{K, O}, O}},
+		// then it swaps with the held fruit, and the queue stays
+		{"swap", 0, true, state{O, [2]assets.Kind{K, O}, C}},
+		{"pop", O, false, state{K, [2]assets.Kind{O, E}, C}},
+		{"swap", 0, true, state{C, [2]assets.Kind{O, E}, K}},
+		{"pop", C, false, state{O, [2]assets.Kind{E, C}, K}},
+	}
+	for i, tt := range tests {
+		switch tt.op {
+		case "pop":
+			if got := q.Pop(); got != tt.wantPop {
+				t.Errorf("%d: Pop() = %v, want %v", i, got, tt.wantPop)
+			}
+		case "swap":
+			if got := q.Swap(); got != tt.wantSwap {
+				t.Errorf("%d: Swap() = %v, want %v", i, got, tt.wantSwap)
+			}
+		}
+		if got := (state{q.Current, q.Next, q.Hold}); got != tt.want {
+			t.Errorf("%d: after %s: %+v, want %+v", i, tt.op, got, tt.want)
+		}
+		if q.CanSwap() == (tt.op == "swap") {
+			t.Errorf("%d: after %s: CanSwap() = %v", i, tt.op, q.CanSwap())
+		}
+	}
+}
+
+func TestWorldHold(t *testing.T) {
+	w := NewWorld(1)
+	current, next := w.Queue.Current, w.Queue.Next[0]
+	w.Step(ActionHold)
+	if w.Queue.Hold != current || w.Queue.Current != next {
+		t.Errorf("after hold: current %v, hold %v; want %v, %v", w.Queue.Current, w.Queue.Hold, next, current)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Replay is what it takes to play a run again: the seed and the actions
+// with the frames they were applied on.
+type Replay struct {
+	Seed int64 `json:"seed"`
+	// Frames is the length of the run in ticks, with the tick that ended
+	// it if it is over.
+	Frames int     `json:"frames"`
+	Inputs []Input `json:"inputs"`
+}
+
+type Input struct {
+	Frame  int    `json:"frame"`
+	Action Action `json:"action"`
+}
+
+func (a Action) MarshalText() ([]byte, error) {
+	s, ok := actionNames[a]
+	if !ok {
+		return nil, fmt.Errorf("unknown action %d", int(a))
+	}
+	return []byte(s), nil
+}
+
+func (a *Action) UnmarshalText(text []byte) error {
+	for action, name := range actionNames {
+		if name == string(text) {
+			*a = action
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action %q", text)
+}
+
+func ParseReplay(data []byte) (*Replay, error) {
+	r := &Replay{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	for i, in := range r.Inputs {
+		if in.Frame < 0 || (i > 0 && in.Frame < r.Inputs[i-1].Frame) {
+			return nil, fmt.Errorf("replay: input %d is out of order", i)
+		}
+	}
+	return r, nil
+}
+
+func (r *Replay) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// Player feeds a replay to a new world, one tick per Step.
+type Player struct {
+	World *World
+
+	replay *Replay
+	next   int
+}
+
+func NewPlayer(r *Replay) *Player {
+	return &Player{
+		World:  NewWorld(r.Seed),
+		replay: r,
+	}
+}
+
+func (p *Player) Step() {
+	var actions []Action
+	inputs := p.replay.Inputs
+	for p.next < len(inputs) && inputs[p.next].Frame <= p.World.Frame {
+		actions = append(actions, inputs[p.next].Action)
+		p.next++
+	}
+	p.World.Step(actions...)
+}
+
+// Done reports whether the run is over, or has been played to where it
+// was recorded.
+func (p *Player) Done() bool {
+	return p.World.Over || p.World.Frame >= p.replay.Frames
+}
+
+// Run plays the whole replay.
+func (p *Player) Run() {
+	for !p.Done() {
+		p.Step()
+	}
+}
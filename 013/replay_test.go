@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/demouth/ebitengine-sketch/013/assets"
+	"github.com/jakecoffman/cp/v2"
+)
+
+type fruit struct {
+	kind  assets.Kind
+	pos   cp.Vector
+	angle float64
+}
+
+func fruits(w *World) []fruit {
+	var fs []fruit
+	w.EachFruit(func(k assets.Kind, pos cp.Vector, angle float64) {
+		fs = append(fs, fruit{k, pos, angle})
+	})
+	return fs
+}
+
+// record plays a scripted run: fruit dropped at a steady pace while the
+// dropper sweeps left and right.
+func record(seed int64, frames int) *World {
+	w := NewWorld(seed)
+	for f := 0; f < frames && !w.Over; f++ {
+		var actions []Action
+		if (f/70)%2 == 0 {
+			actions = append(actions, ActionLeft)
+		} else {
+			actions = append(actions, ActionRight)
+		}
+		if f%45 == 0 {
+			actions = append(actions, ActionDrop)
+		}
+		w.Step(actions...)
+	}
+	return w
+}
+
+func TestReplay(t *testing.T) {
+	original := record(42, 1500)
+
+	data, err := original.Replay().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay, err := ParseReplay(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var played []*World
+	for i := 0; i < 2; i++ {
+		p := NewPlayer(replay)
+		p.Run()
+		played = append(played, p.World)
+	}
+
+	for i, w := range played {
+		if w.Frame != original.Frame || w.Score != original.Score || w.Over != original.Over {
+			t.Errorf("play %d: frame %d, score %d, over %v; want frame %d, score %d, over %v",
+				i, w.Frame, w.Score, w.Over, original.Frame, original.Score, original.Over)
+		}
+		got, want := fruits(w), fruits(original)
+		if len(got) != len(want) {
+			t.Fatalf("play %d: %d fruits, want %d", i, len(got), len(want))
+		}
+		for j := range want {
+			if got[j] != want[j] {
+				t.Errorf("play %d: fruit %d is %+v, want %+v", i, j, got[j], want[j])
+			}
+		}
+	}
+	if len(fruits(original)) == 0 {
+		t.Errorf("no fruit dropped")
+	}
+}
+
+func TestReplayGameOver(t *testing.T) {
+	// fruit dropped on one spot as fast as it comes piles up over the line
+	original := NewWorld(7)
+	for f := 0; f < 36000 && !original.Over; f++ {
+		original.Step(ActionDrop)
+	}
+	if !original.Over {
+		t.Fatalf("the run is not over after %d frames", original.Frame)
+	}
+
+	replay := original.Replay()
+	if replay.Frames != original.Frame+1 {
+		t.Errorf("the replay is %d frames, want %d with the one that ended the run", replay.Frames, original.Frame+1)
+	}
+	p := NewPlayer(replay)
+	p.Run()
+	if w := p.World; !w.Over || w.Frame != original.Frame || w.Score != original.Score {
+		t.Errorf("played to frame %d, score %d, over %v; want frame %d, score %d, over",
+			w.Frame, w.Score, w.Over, original.Frame, original.Score)
+	}
+}
+
+func TestParseReplay(t *testing.T) {
+	r, err := ParseReplay([]byte(`{"seed":7,"frames":100,"inputs":[{"frame":0,"action":"moveLeft"},{"frame":3,"action":"drop"},{"frame":3,"action":"moveRight"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Input{{0, ActionLeft}, {3, ActionDrop}, {3, ActionRight}}
+	if r.Seed != 7 || r.Frames != 100 || len(r.Inputs) != len(want) {
+		t.Fatalf("got %+v", r)
+	}
+	for i := range want {
+		if r.Inputs[i] != want[i] {
+			t.Errorf("input %d is %+v, want %+v", i, r.Inputs[i], want[i])
+		}
+	}
+
+	errs := []string{
+		`{`,
+		`{"inputs":[{"frame":0,"action":"jump"}]}`,
+		`{"inputs":[{"frame":-1,"action":"drop"}]}`,
+		`{"inputs":[{"frame":5,"action":"drop"},{"frame":4,"action":"drop"}]}`,
+	}
+	for _, data := range errs {
+		if _, err := ParseReplay([]byte(data)); err == nil {
+			t.Errorf("ParseReplay(%s): no error", data)
+		}
+	}
+}
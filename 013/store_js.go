@@ -0,0 +1,57 @@
+//go:build js
+
+package main
+
+import (
+	"net/url"
+	"strings"
+	"syscall/js"
+)
+
+const replayKey = "ebitengine-sketch/013/replay"
+
+// localStore keeps the high scores in localStorage.
+type localStore struct {
+	key string
+}
+
+func newStore() Store {
+	return &localStore{key: "ebitengine-sketch/013/hiscores"}
+}
+
+func (l *localStore) Load() ([]byte, error) {
+	v := js.Global().Get("localStorage").Call("getItem", l.key)
+	if v.IsNull() {
+		return nil, nil
+	}
+	return []byte(v.String()), nil
+}
+
+func (l *localStore) Save(data []byte) error {
+	js.Global().Get("localStorage").Call("setItem", l.key, string(data))
+	return nil
+}
+
+// saveReplay keeps the last run in localStorage.
+func saveReplay(r *Replay) (string, error) {
+	data, err := r.Marshal()
+	if err != nil {
+		return "", err
+	}
+	js.Global().Get("localStorage").Call("setItem", replayKey, string(data))
+	return "localStorage, open with ?replay", nil
+}
+
+// loadReplay returns the last run when the page is opened with ?replay,
+// or nil.
+func loadReplay() (*Replay, error) {
+	q, err := url.ParseQuery(strings.TrimPrefix(js.Global().Get("location").Get("search").String(), "?"))
+	if err != nil || !q.Has("replay") {
+		return nil, nil
+	}
+	v := js.Global().Get("localStorage").Call("getItem", replayKey)
+	if v.IsNull() {
+		return nil, nil
+	}
+	return ParseReplay([]byte(v.String()))
+}
@@ -0,0 +1,67 @@
+//go:build !js
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+var flagReplay = flag.String("replay", "", "a replay file to play")
+
+// fileStore keeps the high scores in the user's config directory.
+type fileStore struct {
+	path string
+}
+
+func newStore() Store {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return &fileStore{path: filepath.Join(dir, "ebitengine-sketch", "013", "hiscores.json")}
+}
+
+func (f *fileStore) Load() ([]byte, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (f *fileStore) Save(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, data, 0o644)
+}
+
+// saveReplay writes r to the current directory and returns the file name.
+func saveReplay(r *Replay) (string, error) {
+	data, err := r.Marshal()
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("replay-%d-%d.json", r.Seed, r.Frames)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// loadReplay reads the file given with -replay, or returns nil.
+func loadReplay() (*Replay, error) {
+	if *flagReplay == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(*flagReplay)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReplay(data)
+}
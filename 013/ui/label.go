@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+type Label struct {
+	X        float32
+	Y        float32
+	Text     string
+	FontSize float64
+	Color    color.Color
+	Align    text.Align
+}
+
+func (l *Label) Update() {}
+
+func (l *Label) Draw(screen *ebiten.Image) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(l.X), float64(l.Y))
+	c := l.Color
+	if c == nil {
+		c = color.White
+	}
+	op.ColorScale.ScaleWithColor(c)
+	op.LineSpacing = l.FontSize * 1.5
+	op.PrimaryAlign = l.Align
+	text.Draw(screen, l.Text, &text.GoTextFace{
+		Source: arcadeFaceSource,
+		Size:   l.FontSize,
+	}, op)
+}
+
+// Face returns the arcade font of the buttons and labels at size.
+func Face(size float64) *text.GoTextFace {
+	return &text.GoTextFace{
+		Source: arcadeFaceSource,
+		Size:   size,
+	}
+}
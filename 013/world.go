@@ -0,0 +1,313 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/demouth/ebitengine-sketch/013/assets"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// step is the fixed time step of the simulation. The world always moves by
+// step per tick, whatever the frame rate, so that a run can be replayed.
+const step = 1 / 60.0
+
+type Action int
+
+const (
+	ActionDrop Action = iota + 1
+	ActionLeft
+	ActionRight
+	ActionHold
+)
+
+var actionNames = map[Action]string{
+	ActionDrop:  "drop",
+	ActionLeft:  "moveLeft",
+	ActionRight: "moveRight",
+	ActionHold:  "hold",
+}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+// World is the game without Ebitengine: the space, the next fruit and the
+// score. It moves only when Step is called, so it can run headless.
+type World struct {
+	Seed int64
+	// Frame is the number of ticks stepped.
+	Frame int
+	Score int
+	Over  bool
+	Queue *Queue
+	Combo Combo
+	// Popups are the scores of the merges of the last popupLife ticks.
+	Popups []Popup
+
+	space  *cp.Space
+	rand   *rand.Rand
+	next   next
+	count  int
+	danger DangerLine
+	log    []Input
+	// merging are the fruits merged in this tick. A fruit is made of
+	// several pieces, so two fruits can touch more than once in a tick.
+	merging map[*cp.Body]bool
+}
+
+// next is where the dropper is.
+type next struct {
+	x     float64
+	y     float64
+	angle float64
+}
+
+func NewWorld(seed int64) *World {
+	w := &World{
+		Seed:   seed,
+		rand:   rand.New(rand.NewSource(seed)),
+		next:   next{x: screenWidth / 2, y: screenHeight - containerHeight + 10, angle: 0},
+		danger: DangerLine{Y: screenHeight - containerHeight, MaxSpeed: 30, Grace: 120},
+		Combo:  Combo{Window: 90},
+
+		merging: map[*cp.Body]bool{},
+	}
+	w.Queue = NewQueue(assets.Tomato, func() assets.Kind {
+		return assets.Kind(w.rand.Intn(2) + int(assets.Min))
+	})
+
+	space := cp.NewSpace()
+	space.Iterations = 30
+	space.SetGravity(cp.Vector{X: 0, Y: 500})
+	space.SleepTimeThreshold = 0.5
+	space.SetDamping(1)
+
+	walls := []cp.Vector{
+		{X: 0, Y: 0}, {X: 0, Y: screenHeight},
+		{X: screenWidth, Y: 0}, {X: screenWidth, Y: screenHeight},
+		{X: 0, Y: screenHeight}, {X: screenWidth, Y: screenHeight},
+	}
+	for i := 0; i < len(walls)-1; i += 2 {
+		shape := space.AddShape(cp.NewSegment(space.StaticBody, walls[i], walls[i+1], 1))
+		shape.SetElasticity(0.6)
+		shape.SetFriction(0.4)
+	}
+
+	for k := assets.Min; k <= assets.Max; k++ {
+		ct := cp.CollisionType(k)
+		handler := space.NewCollisionHandler(ct, ct)
+		handler.BeginFunc = BeginFunc
+		handler.UserData = w
+	}
+	w.space = space
+	return w
+}
+
+// Step applies actions and moves the world by one tick. Every action is
+// logged with the frame it was applied on, whether it had an effect or not.
+func (w *World) Step(actions ...Action) {
+	if w.Over {
+		return
+	}
+	w.count++
+	if w.danger.Update(w.fruitAboveLine()) {
+		w.Over = true
+		return
+	}
+	w.next.angle += 0.01
+	for len(w.Popups) > 0 && w.Frame-w.Popups[0].Frame >= popupLife {
+		w.Popups = w.Popups[1:]
+	}
+	for _, a := range actions {
+		w.log = append(w.log, Input{Frame: w.Frame, Action: a})
+		switch a {
+		case ActionDrop:
+			w.drop()
+		case ActionLeft:
+			w.moveLeft()
+		case ActionRight:
+			w.moveRight()
+		case ActionHold:
+			w.Queue.Swap()
+		}
+	}
+	clear(w.merging)
+	w.space.Step(step)
+	w.Frame++
+}
+
+// Replay returns the seed and the inputs so far.
+func (w *World) Replay() *Replay {
+	frames := w.Frame
+	if w.Over {
+		// the tick that ended the run doesn't move Frame on
+		frames++
+	}
+	return &Replay{
+		Seed:   w.Seed,
+		Frames: frames,
+		Inputs: append([]Input(nil), w.log...),
+	}
+}
+
+func (w *World) moveRight() {
+	if w.next.x < screenWidth-50 {
+		w.next.x += 4
+	}
+}
+func (w *World) moveLeft() {
+	if w.next.x > 40 {
+		w.next.x -= 4
+	}
+}
+func (w *World) drop() {
+	if w.count > 40 {
+		k := w.Queue.Pop()
+		addShapeOptions := addShapeOptions{
+			kind:  k,
+			pos:   cp.Vector{X: w.next.x, Y: w.next.y},
+			angle: w.next.angle,
+		}
+		w.space.AddPostStepCallback(addShapeCallback, k, addShapeOptions)
+		w.count = 0
+	}
+}
+
+// fruitAboveLine reports whether any fruit is settled above the danger
+// line.
+func (w *World) fruitAboveLine() bool {
+	above := false
+	w.space.EachBody(func(body *cp.Body) {
+		if _, ok := body.UserData.(assets.Kind); ok && w.danger.Crossed(body.Position(), body.Velocity()) {
+			above = true
+		}
+	})
+	return above
+}
+
+// merged scores a merge of two k at pos. Merges that chain within the
+// combo window score more.
+func (w *World) merged(k assets.Kind, pos cp.Vector) {
+	m := w.Combo.Hit(w.Frame)
+	points := k.Score() * m
+	w.Score += points
+	w.Popups = append(w.Popups, Popup{Pos: pos, Text: popupText(points, m), Frame: w.Frame})
+}
+
+// EachFruit calls f with the kind, position and angle of every fruit in
+// the container.
+func (w *World) EachFruit(f func(k assets.Kind, pos cp.Vector, angle float64)) {
+	w.space.EachBody(func(body *cp.Body) {
+		if k, ok := body.UserData.(assets.Kind); ok {
+			f(k, body.Position(), body.Angle())
+		}
+	})
+}
+
+func addRandomFruit(space *cp.Space, r *rand.Rand) {
+	j := assets.Tomato
+	pos := cp.Vector{X: screenWidth / 2, Y: screenHeight - containerHeight + 10}
+	addFruit(space, j, pos, r.Float64()*math.Pi*2)
+}
+
+func addFruit(space *cp.Space, k assets.Kind, position cp.Vector, angle float64) {
+	if !assets.Exists(k) {
+		return
+	}
+	imgSet := assets.Get(k)
+
+	mat := imgSet.Material
+	area := imgSet.Area()
+	moment := 0.0
+	for _, p := range imgSet.Pieces {
+		// each piece weighs its share of the area
+		m := imgSet.Mass() * cp.AreaForPoly(len(p), p, 0) / area
+		moment += cp.MomentForPoly(m, len(p), p, cp.Vector{}, 0)
+	}
+	body := space.AddBody(cp.NewBody(imgSet.Mass(), moment))
+	body.SetPosition(position)
+	body.SetAngle(angle)
+	body.UserData = k
+	for _, p := range imgSet.Pieces {
+		fruit := space.AddShape(cp.NewPolyShape(body, len(p), p, cp.NewTransformIdentity(), 0))
+		fruit.SetElasticity(mat.Elasticity)
+		fruit.SetFriction(mat.Friction)
+		fruit.SetSurfaceV(mat.SurfaceVelocity)
+		fruit.SetCollisionType(cp.CollisionType(k))
+	}
+}
+
+type addShapeOptions struct {
+	kind  assets.Kind
+	pos   cp.Vector
+	angle float64
+}
+
+func addShapeCallback(space *cp.Space, key interface{}, data interface{}) {
+	var opt addShapeOptions
+	if i, ok := data.(addShapeOptions); ok {
+		opt = i
+	} else {
+		return
+	}
+	addFruit(space, opt.kind, opt.pos, opt.angle)
+}
+func removeBodyCallback(space *cp.Space, key interface{}, data interface{}) {
+	var b *cp.Body
+	var ok bool
+	if b, ok = key.(*cp.Body); !ok {
+		return
+	}
+	// removing a shape reorders the shapes of the body, so they are
+	// gathered first
+	var shapes []*cp.Shape
+	b.EachShape(func(s *cp.Shape) {
+		shapes = append(shapes, s)
+	})
+	for _, s := range shapes {
+		space.RemoveShape(s)
+	}
+	space.RemoveBody(b)
+}
+func BeginFunc(arb *cp.Arbiter, space *cp.Space, data interface{}) bool {
+	shape, shape2 := arb.Shapes()
+
+	var k assets.Kind
+	if ud, ok := shape.Body().UserData.(assets.Kind); ok {
+		k = ud
+	} else {
+		return false
+	}
+
+	w, ok := data.(*World)
+	if !ok {
+		return false
+	}
+	body, body2 := shape.Body(), shape2.Body()
+	if w.merging[body] || w.merging[body2] {
+		return false
+	}
+	w.merging[body], w.merging[body2] = true, true
+
+	space.AddPostStepCallback(removeBodyCallback, body, nil)
+	space.AddPostStepCallback(removeBodyCallback, body2, nil)
+
+	w.merged(k, body.Position().Lerp(body2.Position(), 0.5))
+
+	if hasNext, kk := k.Next(); hasNext {
+		k = kk
+	} else {
+		return false
+	}
+	sp := shape.Body().Position().Clone()
+	sp.Sub(shape2.Body().Position()).Mult(0.5).Add(shape2.Body().Position())
+	a := (shape.Body().Angle() + shape2.Body().Angle()) / 2
+	addShapeOptions := addShapeOptions{
+		kind:  k,
+		pos:   sp,
+		angle: a,
+	}
+	space.AddPostStepCallback(addShapeCallback, k, addShapeOptions)
+	return false
+}
@@ -4,6 +4,7 @@ go 1.22.6
 
 require (
 	github.com/demouth/ebitencp v1.3.3
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.3.0.20240811190802-435c8b75ebc0
 	github.com/jakecoffman/cp/v2 v2.0.2
 	golang.org/x/image v0.18.0
@@ -19,3 +20,5 @@ require (
 	golang.org/x/sys v0.23.0 // indirect
 	golang.org/x/text v0.16.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
@@ -11,6 +11,7 @@ import (
 
 	"github.com/demouth/ebitencp"
 	"github.com/demouth/ebitengine-sketch/014/minigui"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/examples/resources/images"
@@ -45,6 +46,7 @@ type Game struct {
 	debugDrawer *ebitencp.Drawer
 
 	space *cp.Space
+	rand  *rand.Rand
 
 	gui  *minigui.GUI
 	gui2 *minigui.GUI
@@ -61,9 +63,9 @@ func (g *Game) Update() error {
 		for i := 0; i < g.numGen; i++ {
 			addCircle(
 				g.space,
-				rand.Float64()*rand.Float64()*rand.Float64()*30+10,
-				rand.Float64()*20-10,
-				rand.Float64()*20-10-200,
+				g.rand.Float64()*g.rand.Float64()*g.rand.Float64()*30+10,
+				g.rand.Float64()*20-10,
+				g.rand.Float64()*20-10-200,
 				g.elasticity,
 			)
 		}
@@ -142,9 +144,10 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	g.gui2.Draw(screen)
 
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
-		"FPS: %0.2f\nNumCircle: %v",
+		"FPS: %0.2f\nNumCircle: %v\n%s",
 		ebiten.ActualFPS(),
 		numCircle,
+		seed.Label(),
 	))
 }
 
@@ -161,6 +164,7 @@ func main() {
 	game.gy = 100
 	game.elasticity = 0.9
 	game.numGen = 2
+	game.rand = rand.New(rand.NewPCG(uint64(seed.Get()), 0))
 	game.debugDrawer = ebitencp.NewDrawer(screenWidth, screenHeight)
 	game.debugDrawer.FlipYAxis = true
 
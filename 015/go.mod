@@ -4,6 +4,7 @@ go 1.22.6
 
 require (
 	github.com/demouth/ebitencp v1.3.3
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
 	github.com/ebitengine/microui v0.0.0-20240828175406-3a31a7c47107
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.3.0.20240826172230-42209606b1cf
 	github.com/jakecoffman/cp/v2 v2.0.2
@@ -20,3 +21,5 @@ require (
 	golang.org/x/sys v0.24.0 // indirect
 	golang.org/x/text v0.17.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
@@ -10,6 +10,7 @@ import (
 	"math/rand"
 
 	"github.com/demouth/ebitencp"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/ebitengine/microui"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -53,7 +54,8 @@ type Game struct {
 	Stiffness  float32
 	Damping    float32
 
-	ctx *microui.Context
+	ctx  *microui.Context
+	rand *rand.Rand
 }
 
 func (g *Game) Update() error {
@@ -110,8 +112,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	}
 
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
-		"FPS: %0.2f",
+		"FPS: %0.2f\n%s",
 		ebiten.ActualFPS(),
+		seed.Label(),
 	))
 }
 
@@ -133,6 +136,7 @@ func main() {
 	game.Stiffness = 600
 	game.Damping = 32
 	game.ctx = microui.NewContext()
+	game.rand = seed.New()
 
 	space := cp.NewSpace()
 	space.Iterations = 20
@@ -172,8 +176,8 @@ func addWall(space *cp.Space, x1, y1, x2, y2, radius, elasticity float64) {
 	shape.UserData = "wall"
 }
 
-func addSoftbodyCircle(space *cp.Space, restLength float64, x, y, elasticity float64) *SoftbodyCircle {
-	softBody := newSoftbodyCircle(space, restLength, x, y, elasticity)
+func addSoftbodyCircle(space *cp.Space, r *rand.Rand, restLength float64, x, y, elasticity float64) *SoftbodyCircle {
+	softBody := newSoftbodyCircle(space, r, restLength, x, y, elasticity)
 	return softBody
 }
 
@@ -222,7 +226,7 @@ func (g *Game) ProcessFrame() {
 
 	g.count++
 	if g.count%80 == 0 {
-		softbodies = append(softbodies, addSoftbodyCircle(g.space, float64(g.RestLength), rand.Float64()*200.0-100.0, -400, 0.1))
+		softbodies = append(softbodies, addSoftbodyCircle(g.space, g.rand, float64(g.RestLength), g.rand.Float64()*200.0-100.0, -400, 0.1))
 	}
 	newSoftbodies := make([]*SoftbodyCircle, 0)
 	for _, sb := range softbodies {
@@ -384,7 +388,7 @@ func (sc *SoftbodyCircle) setParams(restLength, stiffness, damping float64) {
 		})
 	}
 }
-func newSoftbodyCircle(space *cp.Space, restLength float64, x, y, elasticity float64) *SoftbodyCircle {
+func newSoftbodyCircle(space *cp.Space, r *rand.Rand, restLength float64, x, y, elasticity float64) *SoftbodyCircle {
 	numParts := 32
 	angleStep := math.Pi * 2.0 / float64(numParts)
 	partRadius := partRadius(restLength, numParts)
@@ -422,8 +426,8 @@ func newSoftbodyCircle(space *cp.Space, restLength float64, x, y, elasticity flo
 		restLength: restLength,
 		parts:      parts,
 		center:     center,
-		random:     rand.Float64()*0.8 + 0.6,
-		color:      colors.Random(),
+		random:     r.Float64()*0.8 + 0.6,
+		color:      colors.Random(r),
 	}
 	return sc
 }
@@ -452,8 +456,8 @@ func NewColors() *Colors {
 	}
 	return &Colors{colors: colors}
 }
-func (c *Colors) Random() color.NRGBA {
-	i := rand.Intn(len(c.colors))
+func (c *Colors) Random(r *rand.Rand) color.NRGBA {
+	i := r.Intn(len(c.colors))
 	return c.colors[i]
 }
 func (c *Colors) Color(colorNo uint8) color.NRGBA {
@@ -3,6 +3,7 @@ module github.com/demouth/ebitengine-sketch/016
 go 1.22.6
 
 require (
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
 	github.com/ebitengine/microui v0.0.0-20240901185901-bbb4d8da3b6a
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.3.0.20240826172230-42209606b1cf
 )
@@ -20,3 +21,5 @@ require (
 	golang.org/x/sys v0.24.0 // indirect
 	golang.org/x/text v0.17.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
@@ -8,6 +8,7 @@ import (
 	"math"
 	"math/rand"
 
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/ebitengine/microui"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -34,6 +35,7 @@ type Game struct {
 	gy float32
 
 	particles []*Particle
+	rand      *rand.Rand
 
 	colors *Colors
 }
@@ -50,6 +52,8 @@ func (g *Game) Update() error {
 				g.ctx.LayoutRow(2, []int{84, -1}, 0)
 				g.ctx.Label("ActualFPS:")
 				g.ctx.Label(fmt.Sprintf("%.1f", ebiten.ActualFPS()))
+				g.ctx.Label("Seed:")
+				g.ctx.Label(fmt.Sprint(seed.Get()))
 			}
 			if g.ctx.HeaderEx("Cache", microui.OptExpanded) != 0 {
 				g.ctx.Checkbox("Show Sprite", &g.showSprite)
@@ -60,12 +64,12 @@ func (g *Game) Update() error {
 					return 1000
 				}
 				if g.ctx.Checkbox("Use Cache", &g.useCache) > 0 {
-					g.particles = initParticles(int(g.numParticles))
+					g.particles = initParticles(int(g.numParticles), g.rand)
 				}
 				g.ctx.LayoutRow(2, []int{46, -1}, 0)
 				g.ctx.Label("Num:")
 				if g.ctx.SliderEx(&g.numParticles, 100, calcMax(g.useCache), 100, "%.0f", microui.OptAlignCenter) > 0 {
-					g.particles = initParticles(int(g.numParticles))
+					g.particles = initParticles(int(g.numParticles), g.rand)
 				}
 			}
 		})
@@ -109,7 +113,8 @@ func main() {
 	game.maxNumParticles = 20000
 	game.useCache = true
 	game.cache = newCache(game.shapeWidth, game.shapeHeight, game.shapeLineWidth, game.colors)
-	game.particles = initParticles(int(game.numParticles))
+	game.rand = seed.New()
+	game.particles = initParticles(int(game.numParticles), game.rand)
 
 	game.ctx = microui.NewContext()
 
@@ -118,14 +123,14 @@ func main() {
 	ebiten.RunGame(game)
 }
 
-func initParticles(num int) []*Particle {
+func initParticles(num int, r *rand.Rand) []*Particle {
 	particles := []*Particle{}
 	for i := 0; i < num; i++ {
 		p := &Particle{
-			X:       rand.Float32() * screenWidth,
-			Y:       rand.Float32() * screenHeight,
-			frameNo: uint8(rand.Intn(0xff)),
-			colorNo: uint8(rand.Intn(8)),
+			X:       r.Float32() * screenWidth,
+			Y:       r.Float32() * screenHeight,
+			frameNo: uint8(r.Intn(0xff)),
+			colorNo: uint8(r.Intn(8)),
 		}
 		particles = append(particles, p)
 	}
@@ -152,8 +157,8 @@ func NewColors() *Colors {
 	}
 	return &Colors{colors: colors}
 }
-func (c *Colors) Random() color.NRGBA {
-	i := rand.Intn(len(c.colors))
+func (c *Colors) Random(r *rand.Rand) color.NRGBA {
+	i := r.Intn(len(c.colors))
 	return c.colors[i]
 }
 func (c *Colors) Color(colorNo uint8) color.NRGBA {
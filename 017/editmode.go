@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/ebitengine/microui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// editor mode: the space stands still and the walls are edited with the
+// mouse. Leaving it restarts the game on the edited level.
+
+func (g *Game) screenToWorld(x, y int) cp.Vector {
+	return cp.Vector{
+		X: float64(x) - screenWidth/2 + g.cameraX,
+		Y: float64(y) - screenHeight/2 + g.cameraY,
+	}
+}
+
+func (g *Game) worldToScreen(v cp.Vector) (float32, float32) {
+	return float32(v.X - g.cameraX + screenWidth/2), float32(v.Y - g.cameraY + screenHeight/2)
+}
+
+func (g *Game) updateEditor() {
+	const speed = 10
+	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+		g.cameraX -= speed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+		g.cameraX += speed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+		g.cameraY -= speed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+		g.cameraY += speed
+	}
+
+	e := g.editor
+	x, y := ebiten.CursorPosition()
+	p := g.screenToWorld(x, y)
+	switch {
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+		// clicks on the panel are for microui
+		if !image.Pt(x, y).In(g.panel) {
+			e.Press(p)
+		}
+	case e.Dragging() && inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft):
+		e.Release(p)
+	case e.Dragging():
+		e.Drag(p)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyDelete) || inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
+		e.Delete()
+	}
+}
+
+func (g *Game) editorPanel() {
+	ctx := g.ctx
+	e := g.editor
+	ctx.LayoutRow(2, []int{100, -1}, 0)
+	ctx.Label("Grid:")
+	ctx.SliderEx(&e.Grid, 0, 50, 5, "%.0f", microui.OptAlignCenter)
+	if s := e.Segment(); s != nil {
+		ctx.Label("Radius:")
+		ctx.Slider(&s.Radius, 1, 50)
+		ctx.Label("Friction:")
+		ctx.Slider(&s.Friction, 0, 2)
+		ctx.Label("Elasticity:")
+		ctx.Slider(&s.Elasticity, 0, 1)
+	}
+	ctx.LayoutRow(3, []int{70, 70, -1}, 0)
+	if ctx.Button("Save") != 0 {
+		if err := saveLevel(e.Level); err != nil {
+			g.status = err.Error()
+		} else {
+			g.status = fmt.Sprintf("saved %d walls", len(e.Level.Segments))
+		}
+	}
+	if ctx.Button("Load") != 0 {
+		if level, err := loadLevel(); err != nil {
+			g.status = err.Error()
+		} else {
+			g.editor = NewEditor(level)
+			g.status = fmt.Sprintf("loaded %d walls", len(level.Segments))
+		}
+	}
+	if ctx.Button("Delete") != 0 {
+		e.Delete()
+	}
+	ctx.LayoutRow(1, []int{-1}, 0)
+	ctx.Label(g.status)
+}
+
+func (g *Game) drawEditor(screen *ebiten.Image) {
+	e := g.editor
+
+	// grid
+	if e.Grid >= 5 {
+		step := e.Grid
+		for step < 20 {
+			step *= 2
+		}
+		min := snap(g.screenToWorld(0, 0), step)
+		for x := min.X - step; x <= min.X+screenWidth+step; x += step {
+			for y := min.Y - step; y <= min.Y+screenHeight+step; y += step {
+				sx, sy := g.worldToScreen(cp.Vector{X: x, Y: y})
+				vector.DrawFilledRect(screen, sx, sy, 1, 1, color.NRGBA{0xc0, 0xc0, 0xc0, 0xff}, false)
+			}
+		}
+	}
+
+	for i := range e.Level.Segments {
+		s := &e.Level.Segments[i]
+		c := color.NRGBA{0x00, 0x00, 0x00, 0xff}
+		if i == e.Selected {
+			c = color.NRGBA{0xff, 0x40, 0x40, 0xff}
+		}
+		ax, ay := g.worldToScreen(s.A())
+		bx, by := g.worldToScreen(s.B())
+		var path vector.Path
+		path.MoveTo(ax, ay)
+		path.LineTo(bx, by)
+		path.Close()
+		g.drawLine(screen, path, c, float32(math.Max(s.Radius*2, 1)))
+		if i == e.Selected {
+			g.drawCircle(screen, ax, ay, 4, color.NRGBA{0x40, 0x80, 0xff, 0xff})
+			g.drawCircle(screen, bx, by, 4, color.NRGBA{0x40, 0x80, 0xff, 0xff})
+		}
+	}
+
+	// where the soft body starts
+	sx, sy := g.worldToScreen(cp.Vector{X: 0, Y: -400})
+	vector.StrokeCircle(screen, sx, sy, float32(g.RestLength), 1, color.NRGBA{0x40, 0x80, 0xff, 0xff}, true)
+
+	ebitenutil.DebugPrintAt(screen, "EDIT: drag to draw or move walls, Delete to remove, arrows to scroll, E to play", 8, screenHeight-20)
+}
@@ -0,0 +1,140 @@
+package main
+
+import "github.com/jakecoffman/cp/v2"
+
+type dragMode int
+
+const (
+	dragNone dragMode = iota
+	dragNew
+	dragA
+	dragB
+	dragMove
+)
+
+// Editor places, drags and deletes the walls of a level. It takes the
+// cursor in world coordinates, so that it doesn't depend on the camera or
+// on Ebitengine's input.
+type Editor struct {
+	Level *Level
+	// Grid is the size of the snapping grid. 0 turns snapping off.
+	Grid float64
+	// Handle is how close the cursor has to be to grab a segment or one of
+	// its ends.
+	Handle float64
+	// Selected is the index of the selected segment, or -1.
+	Selected int
+
+	mode  dragMode
+	start cp.Vector
+	orig  Segment
+}
+
+func NewEditor(level *Level) *Editor {
+	return &Editor{
+		Level:    level,
+		Grid:     10,
+		Handle:   8,
+		Selected: -1,
+	}
+}
+
+// Segment returns the selected segment, or nil.
+func (e *Editor) Segment() *Segment {
+	if e.Selected < 0 || e.Selected >= len(e.Level.Segments) {
+		return nil
+	}
+	return &e.Level.Segments[e.Selected]
+}
+
+// Press starts a drag at p. It grabs an end of a segment, then a segment,
+// and starts a new segment on empty space.
+func (e *Editor) Press(p cp.Vector) {
+	e.start = p
+	segs := e.Level.Segments
+
+	// ends first, so that a short segment can still be stretched
+	for i := len(segs) - 1; i >= 0; i-- {
+		switch {
+		case p.Distance(segs[i].A()) <= e.Handle+segs[i].Radius:
+			e.grab(i, dragA)
+			return
+		case p.Distance(segs[i].B()) <= e.Handle+segs[i].Radius:
+			e.grab(i, dragB)
+			return
+		}
+	}
+	for i := len(segs) - 1; i >= 0; i-- {
+		if segs[i].Distance(p) <= e.Handle {
+			e.grab(i, dragMove)
+			return
+		}
+	}
+
+	s := Segment{
+		Radius:     defaultRadius,
+		Friction:   defaultFriction,
+		Elasticity: defaultElasticity,
+	}
+	// new walls look like the selected one
+	if sel := e.Segment(); sel != nil {
+		s.Radius, s.Friction, s.Elasticity = sel.Radius, sel.Friction, sel.Elasticity
+	}
+	a := snap(p, e.Grid)
+	s.SetA(a)
+	s.SetB(a)
+	e.Level.Segments = append(e.Level.Segments, s)
+	e.grab(len(e.Level.Segments)-1, dragNew)
+}
+
+func (e *Editor) grab(i int, mode dragMode) {
+	e.Selected = i
+	e.mode = mode
+	e.orig = e.Level.Segments[i]
+}
+
+// Drag moves what Press grabbed to p.
+func (e *Editor) Drag(p cp.Vector) {
+	s := e.Segment()
+	if s == nil {
+		return
+	}
+	switch e.mode {
+	case dragA:
+		s.SetA(snap(p, e.Grid))
+	case dragB, dragNew:
+		s.SetB(snap(p, e.Grid))
+	case dragMove:
+		// the ends snap, not the cursor
+		d := p.Sub(e.start)
+		a := snap(e.orig.A().Add(d), e.Grid)
+		s.SetA(a)
+		s.SetB(e.orig.B().Add(a.Sub(e.orig.A())))
+	}
+}
+
+// Release ends a drag. A new segment that was never stretched is dropped.
+func (e *Editor) Release(p cp.Vector) {
+	e.Drag(p)
+	if e.mode == dragNew {
+		if s := e.Segment(); s != nil && s.A() == s.B() {
+			e.Delete()
+		}
+	}
+	e.mode = dragNone
+}
+
+func (e *Editor) Dragging() bool {
+	return e.mode != dragNone
+}
+
+// Delete removes the selected segment.
+func (e *Editor) Delete() {
+	if e.Segment() == nil {
+		return
+	}
+	segs := e.Level.Segments
+	e.Level.Segments = append(segs[:e.Selected], segs[e.Selected+1:]...)
+	e.Selected = -1
+	e.mode = dragNone
+}
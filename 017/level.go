@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Level is the set of static walls the soft body rolls on. It is saved as
+// JSON.
+type Level struct {
+	Segments []Segment `json:"segments"`
+}
+
+type Segment struct {
+	X1         float64 `json:"x1"`
+	Y1         float64 `json:"y1"`
+	X2         float64 `json:"x2"`
+	Y2         float64 `json:"y2"`
+	Radius     float64 `json:"radius"`
+	Friction   float64 `json:"friction"`
+	Elasticity float64 `json:"elasticity"`
+}
+
+func (s *Segment) A() cp.Vector { return cp.Vector{X: s.X1, Y: s.Y1} }
+func (s *Segment) B() cp.Vector { return cp.Vector{X: s.X2, Y: s.Y2} }
+
+func (s *Segment) SetA(v cp.Vector) { s.X1, s.Y1 = v.X, v.Y }
+func (s *Segment) SetB(v cp.Vector) { s.X2, s.Y2 = v.X, v.Y }
+
+// Distance returns how far p is from the outline of s.
+func (s *Segment) Distance(p cp.Vector) float64 {
+	a, b := s.A(), s.B()
+	ab := b.Sub(a)
+	t := 0.0
+	if l := ab.LengthSq(); l > 0 {
+		t = cp.Clamp01(p.Sub(a).Dot(ab) / l)
+	}
+	return math.Max(p.Distance(a.Add(ab.Mult(t)))-s.Radius, 0)
+}
+
+const (
+	defaultRadius     = 10
+	defaultFriction   = 0.2
+	defaultElasticity = 0.99
+)
+
+// defaultLevel is the level the sketch started with, before it could be
+// edited.
+func defaultLevel() *Level {
+	walls := [][5]float64{
+		{-400, 0, -65, 110, 30},
+		{400, 0, 65, 110, 30},
+		{-60, 110, -60, 200, 30},
+		{60, 110, 60, 200, 30},
+		{300, 400, -40, 450, 10},
+		{-300, 600, 20, 700, 10},
+		{130, 600, 130, 1900, 10},
+		{20, 700, 20, 2000, 10},
+		{20, 2000, 40, 2040, 10},
+		{40, 2040, 60, 2070, 10},
+		{60, 2070, 80, 2090, 10},
+		{80, 2090, 100, 2100, 10},
+		{100, 2100, 140, 2110, 10},
+		{140, 2110, 200, 2100, 10},
+		{200, 2100, 300, 2030, 10},
+		{900, 2500, 1100, 2500, 20},
+		{900, 2400, 900, 2500, 20},
+		{1100, 2400, 1100, 2500, 20},
+	}
+	l := &Level{}
+	for _, w := range walls {
+		l.Segments = append(l.Segments, Segment{
+			X1: w[0], Y1: w[1], X2: w[2], Y2: w[3],
+			Radius:     w[4],
+			Friction:   defaultFriction,
+			Elasticity: defaultElasticity,
+		})
+	}
+	return l
+}
+
+func ParseLevel(data []byte) (*Level, error) {
+	l := &Level{}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("level: %w", err)
+	}
+	for i, s := range l.Segments {
+		if s.Radius < 0 {
+			return nil, fmt.Errorf("level: segment %d has a negative radius", i)
+		}
+		if s.Friction < 0 {
+			return nil, fmt.Errorf("level: segment %d has a negative friction", i)
+		}
+	}
+	return l, nil
+}
+
+func (l *Level) Marshal() ([]byte, error) {
+	return json.MarshalIndent(l, "", "  ")
+}
+
+// AddTo adds the walls of l to the static body of space.
+func (l *Level) AddTo(space *cp.Space) []*cp.Shape {
+	shapes := make([]*cp.Shape, 0, len(l.Segments))
+	for _, s := range l.Segments {
+		shape := space.AddShape(cp.NewSegment(space.StaticBody, s.A(), s.B(), s.Radius))
+		shape.SetElasticity(s.Elasticity)
+		shape.SetFriction(s.Friction)
+		shape.UserData = "wall"
+		shapes = append(shapes, shape)
+	}
+	return shapes
+}
+
+// snap moves v to the nearest point of a grid of the given size. A size of
+// 0 or less turns snapping off.
+func snap(v cp.Vector, grid float64) cp.Vector {
+	if grid <= 0 {
+		return v
+	}
+	return cp.Vector{
+		X: math.Round(v.X/grid) * grid,
+		Y: math.Round(v.Y/grid) * grid,
+	}
+}
@@ -0,0 +1,25 @@
+//go:build js
+
+package main
+
+import "syscall/js"
+
+// Browsers keep the level in localStorage.
+const storageKey = "ebitengine-sketch/017/level"
+
+func loadLevel() (*Level, error) {
+	v := js.Global().Get("localStorage").Call("getItem", storageKey)
+	if v.IsNull() {
+		return defaultLevel(), nil
+	}
+	return ParseLevel([]byte(v.String()))
+}
+
+func saveLevel(l *Level) error {
+	data, err := l.Marshal()
+	if err != nil {
+		return err
+	}
+	js.Global().Get("localStorage").Call("setItem", storageKey, string(data))
+	return nil
+}
@@ -0,0 +1,33 @@
+//go:build !js
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+)
+
+var flagLevel = flag.String("level", "level.json", "the level file to play and edit")
+
+// loadLevel reads the level file, or returns the default level if there is
+// none yet.
+func loadLevel() (*Level, error) {
+	data, err := os.ReadFile(*flagLevel)
+	if errors.Is(err, fs.ErrNotExist) {
+		return defaultLevel(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseLevel(data)
+}
+
+func saveLevel(l *Level) error {
+	data, err := l.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*flagLevel, data, 0o644)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+func TestLevelCodec(t *testing.T) {
+	l := defaultLevel()
+	data, err := l.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseLevel(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Segments) != len(l.Segments) {
+		t.Fatalf("%d segments, want %d", len(got.Segments), len(l.Segments))
+	}
+	for i := range l.Segments {
+		if got.Segments[i] != l.Segments[i] {
+			t.Errorf("segment %d is %+v, want %+v", i, got.Segments[i], l.Segments[i])
+		}
+	}
+
+	errs := []string{
+		`{`,
+		`{"segments":{}}`,
+		`{"segments":[{"radius":-1}]}`,
+		`{"segments":[{"friction":-0.5}]}`,
+	}
+	for _, data := range errs {
+		if _, err := ParseLevel([]byte(data)); err == nil {
+			t.Errorf("ParseLevel(%s): no error", data)
+		}
+	}
+}
+
+func TestSnap(t *testing.T) {
+	tests := []struct {
+		v    cp.Vector
+		grid float64
+		want cp.Vector
+	}{
+		{cp.Vector{X: 12, Y: 18}, 10, cp.Vector{X: 10, Y: 20}},
+		{cp.Vector{X: -12, Y: -18}, 10, cp.Vector{X: -10, Y: -20}},
+		{cp.Vector{X: 15, Y: -15}, 10, cp.Vector{X: 20, Y: -20}},
+		{cp.Vector{X: 7, Y: 26}, 25, cp.Vector{X: 0, Y: 25}},
+		{cp.Vector{X: 1.5, Y: 2.25}, 0, cp.Vector{X: 1.5, Y: 2.25}},
+		{cp.Vector{X: 1.5, Y: 2.25}, -5, cp.Vector{X: 1.5, Y: 2.25}},
+	}
+	for _, tt := range tests {
+		if got := snap(tt.v, tt.grid); got != tt.want {
+			t.Errorf("snap(%v, %v) = %v, want %v", tt.v, tt.grid, got, tt.want)
+		}
+	}
+}
+
+func TestLevelAddTo(t *testing.T) {
+	l := &Level{Segments: []Segment{
+		{X1: 0, Y1: 0, X2: 100, Y2: 0, Radius: 10, Friction: 0.5, Elasticity: 0.9},
+		{X1: -50, Y1: 20, X2: -50, Y2: 200, Radius: 2, Friction: 1, Elasticity: 0},
+	}}
+	space := cp.NewSpace()
+	shapes := l.AddTo(space)
+	if len(shapes) != len(l.Segments) {
+		t.Fatalf("%d shapes, want %d", len(shapes), len(l.Segments))
+	}
+	for i, shape := range shapes {
+		want := l.Segments[i]
+		seg, ok := shape.Class.(*cp.Segment)
+		if !ok {
+			t.Fatalf("shape %d is a %T, want a segment", i, shape.Class)
+		}
+		if shape.Body() != space.StaticBody {
+			t.Errorf("shape %d is not on the static body", i)
+		}
+		if !space.ContainsShape(shape) {
+			t.Errorf("shape %d is not in the space", i)
+		}
+		if seg.TransformA() != want.A() || seg.TransformB() != want.B() {
+			t.Errorf("shape %d goes from %v to %v, want %v to %v", i, seg.TransformA(), seg.TransformB(), want.A(), want.B())
+		}
+		if seg.Radius() != want.Radius || shape.Friction() != want.Friction || shape.Elasticity() != want.Elasticity {
+			t.Errorf("shape %d has radius %v, friction %v and elasticity %v, want %+v", i, seg.Radius(), shape.Friction(), shape.Elasticity(), want)
+		}
+	}
+}
+
+func TestEditor(t *testing.T) {
+	e := NewEditor(&Level{})
+
+	// a new segment snaps to the grid
+	e.Press(cp.Vector{X: 3, Y: 4})
+	e.Drag(cp.Vector{X: 48, Y: 1})
+	e.Release(cp.Vector{X: 97, Y: -2})
+	if len(e.Level.Segments) != 1 {
+		t.Fatalf("%d segments, want 1", len(e.Level.Segments))
+	}
+	if s := e.Level.Segments[0]; s.A() != (cp.Vector{}) || s.B() != (cp.Vector{X: 100, Y: 0}) {
+		t.Errorf("segment goes from %v to %v", s.A(), s.B())
+	}
+
+	// a click without a drag doesn't leave a segment
+	e.Press(cp.Vector{X: 300, Y: 300})
+	e.Release(cp.Vector{X: 301, Y: 300})
+	if len(e.Level.Segments) != 1 {
+		t.Fatalf("%d segments after a click, want 1", len(e.Level.Segments))
+	}
+
+	// dragging an end
+	e.Press(cp.Vector{X: 99, Y: 1})
+	e.Release(cp.Vector{X: 99, Y: 52})
+	if s := e.Level.Segments[0]; s.B() != (cp.Vector{X: 100, Y: 50}) {
+		t.Errorf("end dragged to %v", s.B())
+	}
+
+	// moving the whole segment
+	e.Press(cp.Vector{X: 50, Y: 25})
+	e.Release(cp.Vector{X: 71, Y: 25})
+	if s := e.Level.Segments[0]; s.A() != (cp.Vector{X: 20, Y: 0}) || s.B() != (cp.Vector{X: 120, Y: 50}) {
+		t.Errorf("segment moved to %v, %v", s.A(), s.B())
+	}
+
+	if e.Selected != 0 {
+		t.Fatalf("segment %d selected, want 0", e.Selected)
+	}
+	e.Delete()
+	if len(e.Level.Segments) != 0 || e.Selected != -1 {
+		t.Errorf("%d segments and %d selected after Delete", len(e.Level.Segments), e.Selected)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image"
 	"image/color"
@@ -11,6 +12,7 @@ import (
 	"github.com/demouth/ebitencp"
 	"github.com/ebitengine/microui"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 
 	"github.com/jakecoffman/cp/v2"
@@ -51,7 +53,12 @@ type Game struct {
 	Stiffness  float64
 	Damping    float64
 
-	ctx *microui.Context
+	ctx   *microui.Context
+	panel image.Rectangle
+
+	editing bool
+	editor  *Editor
+	status  string
 }
 
 func (g *Game) Update() error {
@@ -61,6 +68,11 @@ func (g *Game) Update() error {
 
 func (g *Game) Draw(screen *ebiten.Image) {
 	screen.Fill(color.NRGBA{0xff, 0xff, 0xff, 0xff})
+	if g.editing {
+		g.drawEditor(screen)
+		g.ctx.Draw(screen)
+		return
+	}
 	g.ctx.Draw(screen)
 	if g.debugMode {
 		cp.DrawSpace(g.space, g.debugDrawer.WithScreen(screen))
@@ -151,33 +163,13 @@ func main() {
 	game.Damping = 13
 	game.ctx = microui.NewContext()
 
-	space := cp.NewSpace()
-	space.Iterations = 20
-	gravity := cp.Vector{X: float64(game.gx), Y: float64(game.gy)}
-	space.SetGravity(gravity)
-	game.space = space
-	game.softbody = game.newSoftbody()
-	game.cameraX = game.softbody.center.Position().X
-	game.cameraY = game.softbody.center.Position().Y
-
-	addWall(space, -400, 0, -65, 110, 30, 0.99)
-	addWall(space, 400, 0, 65, 110, 30, 0.99)
-	addWall(space, -60, 110, -60, 200, 30, 0.99)
-	addWall(space, 60, 110, 60, 200, 30, 0.99)
-	addWall(space, 300, 400, -40, 450, 10, 0.99)
-	addWall(space, -300, 600, 20, 700, 10, 0.99)
-	addWall(space, 130, 600, 130, 1900, 10, 0.99)
-	addWall(space, 20, 700, 20, 2000, 10, 0.99)
-	addWall(space, 20, 2000, 40, 2040, 10, 0.99)
-	addWall(space, 40, 2040, 60, 2070, 10, 0.99)
-	addWall(space, 60, 2070, 80, 2090, 10, 0.99)
-	addWall(space, 80, 2090, 100, 2100, 10, 0.99)
-	addWall(space, 100, 2100, 140, 2110, 10, 0.99)
-	addWall(space, 140, 2110, 200, 2100, 10, 0.99)
-	addWall(space, 200, 2100, 300, 2030, 10, 0.99)
-	addWall(space, 900, 2500, 1100, 2500, 20, 0.99)
-	addWall(space, 900, 2400, 900, 2500, 20, 0.99)
-	addWall(space, 1100, 2400, 1100, 2500, 20, 0.99)
+	flag.Parse()
+	level, err := loadLevel()
+	if err != nil {
+		log.Fatal(err)
+	}
+	game.editor = NewEditor(level)
+	game.reset()
 
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("Ebitengine + Chipmunk")
@@ -186,6 +178,20 @@ func main() {
 	}
 }
 
+// reset starts the game over on the level being edited.
+func (g *Game) reset() {
+	space := cp.NewSpace()
+	space.Iterations = 20
+	gravity := cp.Vector{X: float64(g.gx), Y: float64(g.gy)}
+	space.SetGravity(gravity)
+	g.space = space
+	g.softbody = g.newSoftbody()
+	g.cameraX = g.softbody.center.Position().X
+	g.cameraY = g.softbody.center.Position().Y
+	g.count = 0
+	g.editor.Level.AddTo(space)
+}
+
 func addCircle(space *cp.Space, radius float64, x, y, elasticity float64) (*cp.Body, *cp.Shape) {
 	mass := 1.0
 	body := space.AddBody(cp.NewBody(mass, cp.MomentForCircle(mass, 0, radius, cp.Vector{})))
@@ -197,15 +203,6 @@ func addCircle(space *cp.Space, radius float64, x, y, elasticity float64) (*cp.B
 	shape.UserData = "circle"
 	return body, shape
 }
-func addWall(space *cp.Space, x1, y1, x2, y2, radius, elasticity float64) {
-	pos1 := cp.Vector{X: x1, Y: y1}
-	pos2 := cp.Vector{X: x2, Y: y2}
-	shape := space.AddShape(cp.NewSegment(space.StaticBody, pos1, pos2, radius))
-	shape.SetElasticity(elasticity)
-	shape.SetFriction(0.2)
-	shape.UserData = "wall"
-}
-
 func addSoftbodyCircle(space *cp.Space, restLength float64, x, y, elasticity float64) *SoftbodyCircle {
 	softBody := newSoftbodyCircle(space, restLength, x, y, elasticity)
 	return softBody
@@ -256,6 +253,7 @@ func (g *Game) ProcessFrame() {
 			win := ctx.CurrentContainer()
 			win.Rect.Max.X = win.Rect.Min.X + max(win.Rect.Dx(), 240)
 			win.Rect.Max.Y = win.Rect.Min.Y + max(win.Rect.Dy(), 100)
+			g.panel = win.Rect
 
 			if ctx.HeaderEx("Space", microui.OptExpanded) != 0 {
 				ctx.LayoutRow(2, []int{100, -1}, 0)
@@ -279,9 +277,23 @@ func (g *Game) ProcessFrame() {
 				ctx.Slider(&g.Damping, 0, 50)
 				ctx.Checkbox("Debug Mode", &g.debugMode)
 			}
+			if g.editing && ctx.HeaderEx("Editor", microui.OptExpanded) != 0 {
+				g.editorPanel()
+			}
 		})
 	})
 
+	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+		g.editing = !g.editing
+		if !g.editing {
+			g.reset()
+		}
+	}
+	if g.editing {
+		g.updateEditor()
+		return
+	}
+
 	g.count++
 	if g.count > 1900 {
 		g.count = 0
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CompileError is one error reported by the Kage compiler.
+// Line and Column are 1-based and 0 when the message has no position.
+type CompileError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e CompileError) String() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("line %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+var (
+	// matches "12:3: msg". The compiler has no file name to put first.
+	compileErrorPos = regexp.MustCompile(`^(\d+):(\d+):\s*`)
+	// the compiler writes "-" for the position of errors about the whole
+	// shader, like a Fragment with the wrong arguments
+	noPos = regexp.MustCompile(`^-:\s*`)
+	// go/parser appends this to the first error when there are more than 10
+	moreErrors = regexp.MustCompile(`\s*\(and \d+ more errors?\)$`)
+)
+
+// ParseCompileErrors splits the error returned by ebiten.NewShader into
+// its positioned messages, in the order the compiler reported them.
+func ParseCompileErrors(msg string) []CompileError {
+	var errs []CompileError
+	for _, line := range strings.Split(msg, "\n") {
+		line = strings.TrimSpace(moreErrors.ReplaceAllString(line, ""))
+		if line == "" {
+			continue
+		}
+		m := compileErrorPos.FindStringSubmatchIndex(line)
+		if m == nil {
+			errs = append(errs, CompileError{Message: noPos.ReplaceAllString(line, "")})
+			continue
+		}
+		l, _ := strconv.Atoi(line[m[2]:m[3]])
+		c, _ := strconv.Atoi(line[m[4]:m[5]])
+		errs = append(errs, CompileError{
+			Line:    l,
+			Column:  c,
+			Message: line[m[1]:],
+		})
+	}
+	return errs
+}
+
+// FirstCompileError returns the first error with a position, or the first
+// error if none has one.
+func FirstCompileError(errs []CompileError) (CompileError, bool) {
+	for _, e := range errs {
+		if e.Line > 0 {
+			return e, true
+		}
+	}
+	if len(errs) > 0 {
+		return errs[0], true
+	}
+	return CompileError{}, false
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The messages below are what ebiten.NewShader of Ebitengine v2.8 returns
+// for broken shaders.
+func TestParseCompileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want []CompileError
+	}{
+		{
+			name: "parser error",
+			msg:  "4:15: missing ',' before newline in argument list (and 96 more errors)",
+			want: []CompileError{{4, 15, "missing ',' before newline in argument list"}},
+		},
+		{
+			name: "missing package",
+			msg:  "2:1: expected 'package', found 'var' (and 1 more errors)",
+			want: []CompileError{{2, 1, "expected 'package', found 'var'"}},
+		},
+		{
+			name: "bad token",
+			msg:  "4:7: expected operand, found ']' (and 1 more errors)",
+			want: []CompileError{{4, 7, "expected operand, found ']'"}},
+		},
+		{
+			name: "undefined identifier",
+			msg:  "4:14: unexpected identifier: Tme",
+			want: []CompileError{{4, 14, "unexpected identifier: Tme"}},
+		},
+		{
+			name: "type mismatch",
+			msg:  "5:2: cannot use type vec3 as type vec4 in variable declaration",
+			want: []CompileError{{5, 2, "cannot use type vec3 as type vec4 in variable declaration"}},
+		},
+		{
+			name: "colons in the message",
+			msg:  "4:9: types don't match: vec4 + vec2",
+			want: []CompileError{{4, 9, "types don't match: vec4 + vec2"}},
+		},
+		{
+			name: "unused variable",
+			msg:  "4:2: local variable a is not used",
+			want: []CompileError{{4, 2, "local variable a is not used"}},
+		},
+		{
+			name: "redeclared",
+			msg:  "6:1: redeclared function: Foo",
+			want: []CompileError{{6, 1, "redeclared function: Foo"}},
+		},
+		{
+			name: "several errors",
+			msg:  "3:5: global variables must be exposed: a\n3:8: global variables must be exposed: b",
+			want: []CompileError{
+				{3, 5, "global variables must be exposed: a"},
+				{3, 8, "global variables must be exposed: b"},
+			},
+		},
+		{
+			name: "past the source",
+			// an unclosed function runs into what Ebitengine appends
+			msg:  "11:6: expected '(', found imageDstTextureSize (and 172 more errors)",
+			want: []CompileError{{11, 6, "expected '(', found imageDstTextureSize"}},
+		},
+		{
+			name: "no position",
+			msg:  "-: fragment argument dst must be vec4 but was vec2",
+			want: []CompileError{{0, 0, "fragment argument dst must be vec4 but was vec2"}},
+		},
+		{
+			name: "no position on the return",
+			msg:  "-: fragment entry point must have one returning vec4 value for a color",
+			want: []CompileError{{0, 0, "fragment entry point must have one returning vec4 value for a color"}},
+		},
+		{
+			name: "no Fragment",
+			msg:  "graphics: fragment shader entry point 'Fragment' is missing",
+			want: []CompileError{{0, 0, "graphics: fragment shader entry point 'Fragment' is missing"}},
+		},
+		{
+			name: "unit directive",
+			msg:  "shader: invalid value for //kage:unit: pixel",
+			want: []CompileError{{0, 0, "shader: invalid value for //kage:unit: pixel"}},
+		},
+		{
+			name: "empty",
+			msg:  "",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseCompileErrors(tt.msg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCompileErrors(%q) = %#v, want %#v", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstCompileError(t *testing.T) {
+	tests := []struct {
+		name   string
+		errs   []CompileError
+		want   CompileError
+		wantOK bool
+	}{
+		{"none", nil, CompileError{}, false},
+		{"only positioned", []CompileError{{3, 1, "a"}, {5, 2, "b"}}, CompileError{3, 1, "a"}, true},
+		{"positioned after unpositioned", []CompileError{{0, 0, "a"}, {5, 2, "b"}}, CompileError{5, 2, "b"}, true},
+		{"only unpositioned", []CompileError{{0, 0, "a"}}, CompileError{0, 0, "a"}, true},
+	}
+	for _, tt := range tests {
+		got, ok := FirstCompileError(tt.errs)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: FirstCompileError = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCompileErrorString(t *testing.T) {
+	if got, want := (CompileError{12, 3, "oops"}).String(), "line 12:3: oops"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := (CompileError{Message: "oops"}).String(), "oops"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
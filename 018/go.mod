@@ -3,6 +3,7 @@ module github.com/demouth/ebitengine-sketch/018
 go 1.22.6
 
 require (
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
 	github.com/hajimehoshi/bitmapfont/v3 v3.2.0-alpha.4
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.3.0.20240901110852-3eda0dd3875b
 )
@@ -19,3 +20,5 @@ require (
 	golang.org/x/sys v0.24.0 // indirect
 	golang.org/x/text v0.17.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// builtinUniforms are fed by the editor itself and not shown in the inspector.
+var builtinUniforms = map[string]bool{
+	"Time":   true,
+	"Cursor": true,
+}
+
+const (
+	inspectorRowHeight  = 18
+	inspectorLabelWidth = 72
+)
+
+type inspectorRowKind int
+
+const (
+	rowLabel inspectorRowKind = iota
+	rowSlider
+	rowToggle
+	rowSwatch
+)
+
+type inspectorRow struct {
+	kind  inspectorRowKind
+	label string
+	name  string
+	// index is the first scalar of the row in the uniform's values.
+	index int
+}
+
+// Inspector shows a widget for every uniform of the current shader:
+// sliders for float and vecN, a color picker for vec4 named ...Color and
+// a toggle for int. All values are in [0, 1].
+type Inspector struct {
+	bounds image.Rectangle
+	decls  []UniformDecl
+	values map[string][]float32
+	rows   []inspectorRow
+
+	dragging int
+}
+
+func NewInspector(bounds image.Rectangle) *Inspector {
+	return &Inspector{
+		bounds:   bounds,
+		values:   map[string][]float32{},
+		dragging: -1,
+	}
+}
+
+func inspectable(d UniformDecl) bool {
+	if builtinUniforms[d.Name] {
+		return false
+	}
+	switch d.Type {
+	case "float", "int", "vec2", "vec3", "vec4":
+		return true
+	}
+	return false
+}
+
+func isColorUniform(d UniformDecl) bool {
+	return d.Type == "vec4" && strings.HasSuffix(d.Name, "Color")
+}
+
+// SetDecls replaces the uniforms. Values of uniforms that keep their name
+// and size are kept.
+func (in *Inspector) SetDecls(decls []UniformDecl) {
+	in.decls = in.decls[:0]
+	values := map[string][]float32{}
+	for _, d := range decls {
+		if !inspectable(d) {
+			continue
+		}
+		in.decls = append(in.decls, d)
+		if v, ok := in.values[d.Name]; ok && len(v) == d.Size() {
+			values[d.Name] = v
+			continue
+		}
+		v := make([]float32, d.Size())
+		if isColorUniform(d) {
+			for i := range v {
+				v[i] = 1
+			}
+		}
+		values[d.Name] = v
+	}
+	in.values = values
+	in.rows = in.buildRows()
+	in.dragging = -1
+}
+
+func (in *Inspector) buildRows() []inspectorRow {
+	var rows []inspectorRow
+	for _, d := range in.decls {
+		n := d.Len
+		if n == 0 {
+			n = 1
+		}
+		c := d.Components()
+		for e := 0; e < n; e++ {
+			label := d.Name
+			if d.Len > 0 {
+				label = fmt.Sprintf("%s[%d]", d.Name, e)
+			}
+			switch {
+			case d.Type == "int":
+				rows = append(rows, inspectorRow{kind: rowToggle, label: label, name: d.Name, index: e})
+			case isColorUniform(d):
+				rows = append(rows, inspectorRow{kind: rowSwatch, label: label, name: d.Name, index: e * c})
+				for i, l := range []string{"r", "g", "b", "a"} {
+					rows = append(rows, inspectorRow{kind: rowSlider, label: "  " + l, name: d.Name, index: e*c + i})
+				}
+			case c == 1:
+				rows = append(rows, inspectorRow{kind: rowSlider, label: label, name: d.Name, index: e})
+			default:
+				rows = append(rows, inspectorRow{kind: rowLabel, label: label, name: d.Name, index: e * c})
+				for i, l := range []string{"x", "y", "z", "w"}[:c] {
+					rows = append(rows, inspectorRow{kind: rowSlider, label: "  " + l, name: d.Name, index: e*c + i})
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// Uniforms returns the values in the form DrawRectShaderOptions.Uniforms expects.
+func (in *Inspector) Uniforms() map[string]any {
+	u := map[string]any{}
+	for _, d := range in.decls {
+		v := in.values[d.Name]
+		switch {
+		case d.Type == "int" && d.Len == 0:
+			u[d.Name] = int32(v[0])
+		case d.Type == "int":
+			is := make([]int32, len(v))
+			for i := range v {
+				is[i] = int32(v[i])
+			}
+			u[d.Name] = is
+		case d.Type == "float" && d.Len == 0:
+			u[d.Name] = v[0]
+		default:
+			u[d.Name] = v
+		}
+	}
+	return u
+}
+
+func (in *Inspector) rowAt(x, y int) int {
+	if !image.Pt(x, y).In(in.bounds) {
+		return -1
+	}
+	i := (y - in.bounds.Min.Y - 4) / inspectorRowHeight
+	if i < 0 || i >= len(in.rows) {
+		return -1
+	}
+	return i
+}
+
+func (in *Inspector) sliderRange() (int, int) {
+	return in.bounds.Min.X + inspectorLabelWidth, in.bounds.Max.X - 8
+}
+
+func (in *Inspector) Update() {
+	var x, y int
+	ids := inpututil.AppendJustPressedTouchIDs(nil)
+	pressed := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || len(ids) > 0
+	if len(ids) > 0 {
+		x, y = ebiten.TouchPosition(ids[0])
+	} else {
+		x, y = ebiten.CursorPosition()
+	}
+
+	if pressed {
+		if i := in.rowAt(x, y); i >= 0 {
+			r := in.rows[i]
+			switch r.kind {
+			case rowSlider:
+				in.dragging = i
+			case rowToggle:
+				v := in.values[r.name]
+				v[r.index] = 1 - v[r.index]
+			}
+		}
+	}
+
+	if in.dragging < 0 {
+		return
+	}
+	touches := ebiten.AppendTouchIDs(nil)
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) && len(touches) == 0 {
+		in.dragging = -1
+		return
+	}
+	if len(touches) > 0 {
+		x, _ = ebiten.TouchPosition(touches[0])
+	}
+	r := in.rows[in.dragging]
+	x0, x1 := in.sliderRange()
+	t := float32(x-x0) / float32(x1-x0)
+	in.values[r.name][r.index] = min(max(t, 0), 1)
+}
+
+func (in *Inspector) Draw(screen *ebiten.Image) {
+	b := in.bounds
+	vector.DrawFilledRect(screen, float32(b.Min.X), float32(b.Min.Y), float32(b.Dx()), float32(b.Dy()), color.RGBA{0xbb, 0xbb, 0xbb, 0xbb}, false)
+	vector.StrokeRect(screen, float32(b.Min.X), float32(b.Min.Y), float32(b.Dx()), float32(b.Dy()), 1, color.Black, false)
+
+	if len(in.rows) == 0 {
+		in.drawLabel(screen, "no uniforms", b.Min.X+4, b.Min.Y+4)
+		return
+	}
+
+	x0, x1 := in.sliderRange()
+	for i, r := range in.rows {
+		y := b.Min.Y + 4 + i*inspectorRowHeight
+		if y+inspectorRowHeight > b.Max.Y {
+			break
+		}
+		in.drawLabel(screen, r.label, b.Min.X+4, y)
+		v := in.values[r.name]
+		cy := float32(y) + inspectorRowHeight/2
+
+		switch r.kind {
+		case rowSlider:
+			vector.StrokeLine(screen, float32(x0), cy, float32(x1), cy, 2, color.RGBA{0x66, 0x66, 0x66, 0xff}, false)
+			kx := float32(x0) + v[r.index]*float32(x1-x0)
+			vector.DrawFilledRect(screen, kx-3, cy-6, 6, 12, color.RGBA{0, 0, 0xff, 0xff}, false)
+			in.drawLabel(screen, fmt.Sprintf("%.2f", v[r.index]), x1-30, y)
+		case rowToggle:
+			vector.StrokeRect(screen, float32(x0), cy-6, 12, 12, 1, color.Black, false)
+			if v[r.index] != 0 {
+				vector.DrawFilledRect(screen, float32(x0)+3, cy-3, 6, 6, color.Black, false)
+			}
+		case rowSwatch:
+			c := v[r.index : r.index+4]
+			clr := color.NRGBA{uint8(c[0] * 0xff), uint8(c[1] * 0xff), uint8(c[2] * 0xff), uint8(c[3] * 0xff)}
+			vector.DrawFilledRect(screen, float32(x0), cy-6, float32(x1-x0), 12, clr, false)
+			vector.StrokeRect(screen, float32(x0), cy-6, float32(x1-x0), 12, 1, color.Black, false)
+		}
+	}
+}
+
+func (in *Inspector) drawLabel(screen *ebiten.Image, s string, x, y int) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x), float64(y+(inspectorRowHeight-textFieldLineHeight())/2))
+	op.ColorScale.ScaleWithColor(color.Black)
+	text.Draw(screen, s, fontFace, op)
+}
+
+func textFieldLineHeight() int {
+	m := fontFace.Metrics()
+	return int(m.HLineGap + m.HAscent + m.HDescent)
+}
@@ -15,13 +15,19 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"image"
 	"image/color"
 	"log"
 	"math"
+	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
+	"github.com/demouth/ebitengine-sketch/lib/shaderfile"
+
 	"github.com/hajimehoshi/bitmapfont/v3"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -39,9 +45,11 @@ const (
 )
 
 type TextField struct {
-	bounds     image.Rectangle
-	multilines bool
-	field      textinput.Field
+	bounds      image.Rectangle
+	multilines  bool
+	lineNumbers bool
+	errorLine   int
+	field       textinput.Field
 }
 
 func NewTextField(bounds image.Rectangle, multilines bool) *TextField {
@@ -71,7 +79,7 @@ func (t *TextField) textIndexByCursorPosition(x, y int) (int, bool) {
 
 	x -= t.bounds.Min.X
 	y -= t.bounds.Min.Y
-	px, py := textFieldPadding()
+	px, py := t.padding()
 	x -= px
 	y -= py
 	if x < 0 {
@@ -134,7 +142,7 @@ func (t *TextField) Update() error {
 
 	x, y := t.bounds.Min.X, t.bounds.Min.Y
 	cx, cy := t.cursorPos()
-	px, py := textFieldPadding()
+	px, py := t.padding()
 	x += cx + px
 	y += cy + py + int(fontFace.Metrics().HAscent)
 	handled, err := t.field.HandleInput(x, y)
@@ -232,7 +240,14 @@ func (t *TextField) Draw(screen *ebiten.Image) {
 	}
 	vector.StrokeRect(screen, float32(t.bounds.Min.X), float32(t.bounds.Min.Y), float32(t.bounds.Dx()), float32(t.bounds.Dy()), 1, clr, false)
 
-	px, py := textFieldPadding()
+	px, py := t.padding()
+	lineHeight := fontFace.Metrics().HLineGap + fontFace.Metrics().HAscent + fontFace.Metrics().HDescent
+	if y0, y1, ok := t.errorBand(py, lineHeight); ok {
+		vector.DrawFilledRect(screen, float32(t.bounds.Min.X+1), y0, float32(t.bounds.Dx()-2), y1-y0, color.RGBA{0xff, 0, 0, 0x60}, false)
+	}
+	if t.lineNumbers {
+		t.drawLineNumbers(screen, py, lineHeight)
+	}
 	selectionStart, _ := t.field.Selection()
 	if t.field.IsFocused() && selectionStart >= 0 {
 		x, y := t.bounds.Min.X, t.bounds.Min.Y
@@ -253,6 +268,56 @@ func (t *TextField) Draw(screen *ebiten.Image) {
 	text.Draw(screen, t.field.TextForRendering(), fontFace, op)
 }
 
+// errorBand returns the top and bottom of the band that marks the error
+// line, cut to the inside of the field. ok is false when there is no error
+// line or it is out of the field.
+func (t *TextField) errorBand(py int, lineHeight float64) (y0, y1 float32, ok bool) {
+	if t.errorLine <= 0 {
+		return 0, 0, false
+	}
+	y0 = float32(t.bounds.Min.Y+py) + float32(t.errorLine-1)*float32(lineHeight)
+	y1 = min(y0+float32(lineHeight), float32(t.bounds.Max.Y-1))
+	y0 = max(y0, float32(t.bounds.Min.Y+1))
+	return y0, y1, y0 < y1
+}
+
+func (t *TextField) drawLineNumbers(screen *ebiten.Image, py int, lineHeight float64) {
+	gw := gutterWidth()
+	x := float32(t.bounds.Min.X)
+	vector.DrawFilledRect(screen, x+1, float32(t.bounds.Min.Y+1), float32(gw), float32(t.bounds.Dy()-2), color.RGBA{0x99, 0x99, 0x99, 0xbb}, false)
+
+	n := strings.Count(t.field.TextForRendering(), "\n") + 1
+	for i := 1; i <= n; i++ {
+		s := strconv.Itoa(i)
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(float64(t.bounds.Min.X+gw)-4-text.Advance(s, fontFace), float64(t.bounds.Min.Y+py)+float64(i-1)*lineHeight)
+		if i == t.errorLine {
+			op.ColorScale.ScaleWithColor(color.RGBA{0xcc, 0, 0, 0xff})
+		} else {
+			op.ColorScale.ScaleWithColor(color.RGBA{0x44, 0x44, 0x44, 0xff})
+		}
+		text.Draw(screen, s, fontFace, op)
+	}
+}
+
+// SetErrorLine highlights the 1-based line. 0 clears the highlight.
+func (t *TextField) SetErrorLine(line int) {
+	t.errorLine = line
+}
+
+func (t *TextField) padding() (int, int) {
+	px, py := textFieldPadding()
+	if t.lineNumbers {
+		px += gutterWidth()
+	}
+	return px, py
+}
+
+// gutterWidth is wide enough for 4 digit line numbers.
+func gutterWidth() int {
+	return int(text.Advance("0000", fontFace)) + 8
+}
+
 const textFieldHeight = 24
 
 func textFieldPadding() (int, int) {
@@ -260,18 +325,7 @@ func textFieldPadding() (int, int) {
 	return 4, (textFieldHeight - int(m.HLineGap+m.HAscent+m.HDescent)) / 2
 }
 
-type Game struct {
-	textFields  []*TextField
-	shader      *ebiten.Shader
-	shaderImage *ebiten.Image
-	prevText    string
-	time        int
-}
-
-func (g *Game) Update() error {
-	if g.textFields == nil {
-		g.textFields = append(g.textFields, NewTextField(image.Rect(16, 16, screenWidth-16, screenHeight-16), true))
-		g.textFields[0].field.SetTextAndSelection(`// Shader Art Coding Introduction
+const defaultSource = `// Shader Art Coding Introduction
 // https://www.shadertoy.com/view/mtyGWy
 
 //go:build ignore
@@ -311,7 +365,119 @@ func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
 	}
 
 	return vec4(finalColor, 1)
-}`, 0, 0)
+}`
+
+const (
+	statusBarHeight = 24
+	inspectorWidth  = 200
+)
+
+type Game struct {
+	textFields  []*TextField
+	inspector   *Inspector
+	shader      *ebiten.Shader
+	shaderImage *ebiten.Image
+	prevText    string
+	time        int
+
+	// compileErr is the first error of the current source, if any.
+	// shader keeps the last source that compiled.
+	compileErr    CompileError
+	hasCompileErr bool
+
+	watcher *shaderfile.Watcher
+	presets *shaderfile.PresetStore
+	// unsaved is true while the source differs from the autosave.
+	unsaved   bool
+	savedTime int
+
+	notice     string
+	noticeTime int
+}
+
+const (
+	autosavePreset   = "autosave"
+	autosaveInterval = 60
+	noticeDuration   = 120
+)
+
+var presetKeys = []ebiten.Key{ebiten.KeyF1, ebiten.KeyF2, ebiten.KeyF3, ebiten.KeyF4}
+
+// initialSource is the watched file, the autosave or the default shader.
+func (g *Game) initialSource() string {
+	if g.watcher != nil {
+		if src, _, err := g.watcher.Poll(); err == nil {
+			return string(src)
+		}
+	}
+	if g.presets != nil {
+		if src, err := g.presets.Load(autosavePreset); err == nil {
+			return string(src)
+		}
+	}
+	return defaultSource
+}
+
+func (g *Game) setNotice(msg string) {
+	g.notice = msg
+	g.noticeTime = g.time
+}
+
+// updatePresets reloads the watched file and handles the preset keys:
+// F1-F4 load a preset and Shift+F1-F4 save one.
+func (g *Game) updatePresets() {
+	tf := g.textFields[0]
+	if g.watcher != nil {
+		src, changed, err := g.watcher.Poll()
+		if err != nil {
+			g.setNotice(err.Error())
+		} else if changed {
+			tf.field.SetTextAndSelection(string(src), 0, 0)
+			g.setNotice("reloaded " + g.watcher.Path)
+		}
+	}
+	if g.presets == nil {
+		return
+	}
+
+	for i, k := range presetKeys {
+		if !inpututil.IsKeyJustPressed(k) {
+			continue
+		}
+		name := fmt.Sprintf("slot%d", i+1)
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			if err := g.presets.Save(name, []byte(tf.field.Text())); err != nil {
+				g.setNotice(err.Error())
+			} else {
+				g.setNotice("saved " + name)
+			}
+			continue
+		}
+		src, err := g.presets.Load(name)
+		if err != nil {
+			g.setNotice(err.Error())
+			continue
+		}
+		tf.field.SetTextAndSelection(string(src), 0, 0)
+		g.setNotice("loaded " + name)
+	}
+
+	if g.unsaved && g.time-g.savedTime >= autosaveInterval {
+		if err := g.presets.Save(autosavePreset, []byte(tf.field.Text())); err != nil {
+			g.setNotice(err.Error())
+		}
+		g.unsaved = false
+		g.savedTime = g.time
+	}
+}
+
+func (g *Game) Update() error {
+	if g.textFields == nil {
+		tf := NewTextField(image.Rect(16, 16, screenWidth-16-inspectorWidth-8, screenHeight-16-statusBarHeight-8), true)
+		tf.lineNumbers = true
+		g.textFields = append(g.textFields, tf)
+		g.inspector = NewInspector(image.Rect(screenWidth-16-inspectorWidth, 16, screenWidth-16, screenHeight-16-statusBarHeight-8))
+		g.textFields[0].field.SetTextAndSelection(g.initialSource(), 0, 0)
 	}
 
 	ids := inpututil.AppendJustPressedTouchIDs(nil)
@@ -337,6 +503,8 @@ func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
 			return err
 		}
 	}
+	g.inspector.Update()
+	g.updatePresets()
 
 	x, y := ebiten.CursorPosition()
 	var inTextField bool
@@ -353,30 +521,69 @@ func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
 	}
 
 	g.time++
-	if g.shader == nil || g.prevText != g.textFields[0].field.Text() {
-		g.shaderImage = ebiten.NewImage(screenWidth, screenHeight)
-		s, err := ebiten.NewShader([]byte(g.textFields[0].field.Text()))
-		if err != nil {
-			g.shader = nil
-			// return err
+	if g.shaderImage == nil || g.prevText != g.textFields[0].field.Text() {
+		if g.shaderImage == nil {
+			g.shaderImage = ebiten.NewImage(screenWidth, screenHeight)
+		}
+		if g.prevText != "" {
+			g.unsaved = true
 		}
-		g.shader = s
+		g.compile(g.textFields[0].field.Text())
 		g.prevText = g.textFields[0].field.Text()
 	}
 
 	return nil
 }
 
+// compile replaces the running shader only when src compiles.
+func (g *Game) compile(src string) {
+	s, err := ebiten.NewShader([]byte(src))
+	if err != nil {
+		g.compileErr, g.hasCompileErr = FirstCompileError(ParseCompileErrors(err.Error()))
+		g.textFields[0].SetErrorLine(g.compileErr.Line)
+		return
+	}
+	if g.shader != nil {
+		g.shader.Deallocate()
+	}
+	g.shader = s
+	g.hasCompileErr = false
+	g.textFields[0].SetErrorLine(0)
+
+	decls, _ := ParseUniformDecls(src)
+	g.inspector.SetDecls(decls)
+}
+
+func (g *Game) drawStatusBar(screen *ebiten.Image) {
+	x, y := 16, screenHeight-16-statusBarHeight
+	bg := color.RGBA{0x33, 0x99, 0x33, 0xdd}
+	msg := "compiled"
+	if g.hasCompileErr {
+		bg = color.RGBA{0xcc, 0x33, 0x33, 0xdd}
+		msg = g.compileErr.String()
+	} else if g.notice != "" && g.time-g.noticeTime < noticeDuration {
+		msg = g.notice
+	} else if g.presets != nil {
+		msg = "compiled  (F1-F4: load preset, Shift+F1-F4: save preset)"
+	}
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(screenWidth-32), statusBarHeight, bg, false)
+
+	_, py := textFieldPadding()
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x+4), float64(y+py))
+	op.ColorScale.ScaleWithColor(color.White)
+	text.Draw(screen, msg, fontFace, op)
+}
+
 func (g *Game) Draw(screen *ebiten.Image) {
 	if g.shader != nil {
 		s := g.shader
 		w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
 		cx, cy := ebiten.CursorPosition()
 		op := &ebiten.DrawRectShaderOptions{}
-		op.Uniforms = map[string]any{
-			"Time":   float32(g.time) / 60,
-			"Cursor": []float32{float32(cx), float32(cy)},
-		}
+		op.Uniforms = g.inspector.Uniforms()
+		op.Uniforms["Time"] = float32(g.time) / 60
+		op.Uniforms["Cursor"] = []float32{float32(cx), float32(cy)}
 		op.ColorScale.SetA(0x33)
 		g.shaderImage.DrawRectShader(w, h, s, op)
 		sop := &ebiten.DrawImageOptions{}
@@ -387,16 +594,37 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	for _, tf := range g.textFields {
 		tf.Draw(screen)
 	}
+	g.inspector.Draw(screen)
+	g.drawStatusBar(screen)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return screenWidth, screenHeight
 }
 
+var (
+	shaderPath = flag.String("shader", "", "a .kage file to edit in an external editor; reloaded when it changes")
+	presetDir  = flag.String("presets", "", "the preset library directory (default: the user config directory)")
+)
+
 func main() {
+	flag.Parse()
+	g := &Game{}
+	if *shaderPath != "" {
+		g.watcher = shaderfile.NewWatcher(*shaderPath, 500*time.Millisecond)
+	}
+	// presets are not available when there is no file system, e.g. in browsers
+	dir := *presetDir
+	if dir == "" {
+		dir, _ = shaderfile.DefaultPresetDir()
+	}
+	if dir != "" {
+		g.presets = shaderfile.NewPresetStore(dir)
+	}
+
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("Text Input (Ebitengine Demo)")
-	if err := ebiten.RunGame(&Game{}); err != nil {
+	if err := ebiten.RunGame(g); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"image"
+	"testing"
+)
+
+func TestErrorBand(t *testing.T) {
+	// lines of 10 from y 108 in a field from 100 to 150
+	const py, lineHeight = 8, 10
+	bounds := image.Rect(0, 100, 200, 150)
+	tests := []struct {
+		line   int
+		y0, y1 float32
+		ok     bool
+	}{
+		{0, 0, 0, false},
+		{1, 108, 118, true},
+		{4, 138, 148, true},
+		// cut at the bottom edge
+		{5, 148, 149, true},
+		// past the source, below the field
+		{9, 0, 0, false},
+	}
+	for _, tt := range tests {
+		f := &TextField{bounds: bounds, errorLine: tt.line}
+		y0, y1, ok := f.errorBand(py, lineHeight)
+		if ok != tt.ok || ok && (y0 != tt.y0 || y1 != tt.y1) {
+			t.Errorf("line %d: band %v to %v, %v; want %v to %v, %v", tt.line, y0, y1, ok, tt.y0, tt.y1, tt.ok)
+		}
+	}
+}
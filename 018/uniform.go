@@ -0,0 +1,130 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// UniformDecl is a top-level var of a Kage program, which is a uniform.
+type UniformDecl struct {
+	Name string
+	// Type is the element type such as "float", "int" or "vec3".
+	Type string
+	// Len is the array length, or 0 if the uniform is not an array.
+	Len int
+}
+
+// Components returns the number of scalars in one element.
+func (d UniformDecl) Components() int {
+	switch d.Type {
+	case "float", "int":
+		return 1
+	case "vec2", "ivec2":
+		return 2
+	case "vec3", "ivec3":
+		return 3
+	case "vec4", "ivec4", "mat2":
+		return 4
+	case "mat3":
+		return 9
+	case "mat4":
+		return 16
+	}
+	return 0
+}
+
+// Size returns the number of scalars of the whole uniform.
+func (d UniformDecl) Size() int {
+	if d.Len > 0 {
+		return d.Len * d.Components()
+	}
+	return d.Components()
+}
+
+// ParseUniformDecls returns the uniforms declared in a Kage source in the
+// order they appear. Kage is parsed as Go, so comments, grouped
+// declarations and several names in one declaration are handled.
+// Declarations that parsed before a syntax error are still returned.
+func ParseUniformDecls(src string) ([]UniformDecl, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.AllErrors|parser.SkipObjectResolution)
+	if f == nil {
+		return nil, err
+	}
+
+	consts := map[string]int{}
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i >= len(vs.Values) {
+					break
+				}
+				if n, ok := intLiteral(vs.Values[i]); ok {
+					consts[name.Name] = n
+				}
+			}
+		}
+	}
+
+	var decls []UniformDecl
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			typ, n, ok := uniformType(vs.Type, consts)
+			if !ok {
+				continue
+			}
+			for _, name := range vs.Names {
+				decls = append(decls, UniformDecl{Name: name.Name, Type: typ, Len: n})
+			}
+		}
+	}
+	return decls, err
+}
+
+func uniformType(expr ast.Expr, consts map[string]int) (string, int, bool) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name, 0, true
+	case *ast.ArrayType:
+		elt, ok := t.Elt.(*ast.Ident)
+		if !ok || t.Len == nil {
+			return "", 0, false
+		}
+		n, ok := intLiteral(t.Len)
+		if !ok {
+			id, isIdent := t.Len.(*ast.Ident)
+			if !isIdent {
+				return "", 0, false
+			}
+			if n, ok = consts[id.Name]; !ok {
+				return "", 0, false
+			}
+		}
+		return elt.Name, n, true
+	}
+	return "", 0, false
+}
+
+func intLiteral(expr ast.Expr) (int, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+	n, err := strconv.Atoi(lit.Value)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
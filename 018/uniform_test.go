@@ -0,0 +1,210 @@
+package main
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestParseUniformDecls(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []UniformDecl
+	}{
+		{
+			name: "simple",
+			src: `package main
+
+var Time float
+var Cursor vec2
+`,
+			want: []UniformDecl{
+				{Name: "Time", Type: "float"},
+				{Name: "Cursor", Type: "vec2"},
+			},
+		},
+		{
+			name: "grouped and several names",
+			src: `package main
+
+var (
+	Speed, Scale float
+	BaseColor    vec4
+	Enabled      int
+)
+`,
+			want: []UniformDecl{
+				{Name: "Speed", Type: "float"},
+				{Name: "Scale", Type: "float"},
+				{Name: "BaseColor", Type: "vec4"},
+				{Name: "Enabled", Type: "int"},
+			},
+		},
+		{
+			name: "arrays",
+			src: `package main
+
+const N = 3
+
+var Points [4]vec2
+var Weights [N]float
+var Bad [M]float
+`,
+			want: []UniformDecl{
+				{Name: "Points", Type: "vec2", Len: 4},
+				{Name: "Weights", Type: "float", Len: 3},
+			},
+		},
+		{
+			name: "comments",
+			src: `//go:build ignore
+
+//kage:unit pixels
+
+package main
+
+// Time is the elapsed seconds.
+var Time float // updated every frame
+
+/*
+var Hidden float
+*/
+
+// var AlsoHidden vec3
+
+var Offset /* in pixels */ vec2
+`,
+			want: []UniformDecl{
+				{Name: "Time", Type: "float"},
+				{Name: "Offset", Type: "vec2"},
+			},
+		},
+		{
+			name: "local variables are not uniforms",
+			src: `package main
+
+var Time float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	var c vec4
+	return c
+}
+`,
+			want: []UniformDecl{
+				{Name: "Time", Type: "float"},
+			},
+		},
+		{
+			name: "declarations before a syntax error",
+			src: `package main
+
+var Time float
+var Glow vec3
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(
+}
+`,
+			want: []UniformDecl{
+				{Name: "Time", Type: "float"},
+				{Name: "Glow", Type: "vec3"},
+			},
+		},
+		{
+			name: "no uniforms",
+			src:  "package main\n",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := ParseUniformDecls(tt.src)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUniformDecls() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniformDeclSize(t *testing.T) {
+	tests := []struct {
+		decl UniformDecl
+		want int
+	}{
+		{UniformDecl{Type: "float"}, 1},
+		{UniformDecl{Type: "int"}, 1},
+		{UniformDecl{Type: "vec3"}, 3},
+		{UniformDecl{Type: "vec4", Len: 2}, 8},
+		{UniformDecl{Type: "mat4"}, 16},
+		{UniformDecl{Type: "foo"}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.decl.Size(); got != tt.want {
+			t.Errorf("%+v.Size() = %d, want %d", tt.decl, got, tt.want)
+		}
+	}
+}
+
+func TestInspectorUniforms(t *testing.T) {
+	in := NewInspector(image.Rect(0, 0, 200, 400))
+	in.SetDecls([]UniformDecl{
+		{Name: "Time", Type: "float"},
+		{Name: "Speed", Type: "float"},
+		{Name: "Offset", Type: "vec2"},
+		{Name: "TintColor", Type: "vec4"},
+		{Name: "Enabled", Type: "int"},
+		{Name: "Flags", Type: "int", Len: 2},
+		{Name: "Matrix", Type: "mat4"},
+	})
+
+	in.values["Speed"][0] = 0.5
+	in.values["Enabled"][0] = 1
+	want := map[string]any{
+		"Speed":     float32(0.5),
+		"Offset":    []float32{0, 0},
+		"TintColor": []float32{1, 1, 1, 1},
+		"Enabled":   int32(1),
+		"Flags":     []int32{0, 0},
+	}
+	if got := in.Uniforms(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Uniforms() = %v, want %v", got, want)
+	}
+
+	// values survive a recompile as long as the type is the same
+	in.SetDecls([]UniformDecl{
+		{Name: "Speed", Type: "float"},
+		{Name: "Enabled", Type: "vec2"},
+	})
+	want = map[string]any{
+		"Speed":   float32(0.5),
+		"Enabled": []float32{0, 0},
+	}
+	if got := in.Uniforms(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Uniforms() after SetDecls = %v, want %v", got, want)
+	}
+}
+
+func TestInspectorRows(t *testing.T) {
+	in := NewInspector(image.Rect(0, 0, 200, 400))
+	in.SetDecls([]UniformDecl{
+		{Name: "Speed", Type: "float"},
+		{Name: "Offset", Type: "vec2"},
+		{Name: "TintColor", Type: "vec4"},
+		{Name: "Enabled", Type: "int"},
+	})
+	want := []inspectorRowKind{
+		rowSlider,
+		rowLabel, rowSlider, rowSlider,
+		rowSwatch, rowSlider, rowSlider, rowSlider, rowSlider,
+		rowToggle,
+	}
+	if len(in.rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(in.rows), len(want))
+	}
+	for i, r := range in.rows {
+		if r.kind != want[i] {
+			t.Errorf("row %d (%s) kind = %v, want %v", i, r.label, r.kind, want[i])
+		}
+	}
+}
@@ -4,6 +4,7 @@ go 1.22.6
 
 require (
 	github.com/KEINOS/go-noise v0.1.0-rc1
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.3.0.20240902171903-b34f9977f6d3
 	github.com/lucasb-eyer/go-colorful v1.2.0
 )
@@ -19,3 +20,5 @@ require (
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.24.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
@@ -9,6 +9,7 @@ import (
 	"math"
 
 	"github.com/KEINOS/go-noise"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -107,7 +108,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			drawShape(screen, current, right, bottom, bottomRight, float32(r), col)
 		}
 	}
-	ebitenutil.DebugPrint(screen, fmt.Sprintf("%.2f", ebiten.ActualFPS()))
+	ebitenutil.DebugPrint(screen, fmt.Sprintf("%.2f\n%s", ebiten.ActualFPS(), seed.Label()))
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
@@ -118,6 +119,7 @@ func main() {
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("Ebiten perlin noise")
 	g := &Game{}
+	g.seed = seed.Get()
 	g.reference = genDot(screenWidth/step+1, screenHeight/step+1, g.seed, g.shift)
 	g.present = genDot(screenWidth/step+1, screenHeight/step+1, g.seed, g.shift)
 	g.grad = NewGradientTable()
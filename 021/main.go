@@ -11,6 +11,7 @@ import (
 
 	pmath "changkun.de/x/polyred/math"
 	"github.com/KEINOS/go-noise"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -36,10 +37,10 @@ type Perticle struct {
 	dead       bool
 }
 
-func (p *Perticle) Move(forceMap [][]Noise) {
+func (p *Perticle) Move(forceMap [][]Noise, r *rand.Rand) {
 	if p.dead {
-		p.X = rand.Float32() * screenWidth
-		p.Y = rand.Float32() * screenHeight
+		p.X = r.Float32() * screenWidth
+		p.Y = r.Float32() * screenHeight
 		p.OldX = p.X
 		p.OldY = p.Y
 		p.SX = 0
@@ -94,6 +95,7 @@ type Game struct {
 	time      int
 	shift     float64
 	seed      int64
+	rand      *rand.Rand
 	// grad      GradientTable
 	perticles []Perticle
 	canvas    *ebiten.Image
@@ -105,7 +107,7 @@ type Noise struct {
 func (g *Game) Update() error {
 	g.time++
 	for i := 0; i < len(g.perticles); i++ {
-		g.perticles[i].Move(g.reference)
+		g.perticles[i].Move(g.reference, g.rand)
 	}
 	if g.time%60 == 0 {
 		g.shift += .02
@@ -148,9 +150,10 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	ebitenutil.DebugPrint(
 		screen,
 		fmt.Sprintf(
-			"FPS:%.2f\nShift:%.3f",
+			"FPS:%.2f\nShift:%.3f\n%s",
 			ebiten.ActualFPS(),
 			g.shift,
+			seed.Label(),
 		),
 	)
 }
@@ -164,12 +167,14 @@ func main() {
 	ebiten.SetWindowTitle("perlin noise")
 	colors := NewColors(2)
 	g := &Game{}
+	g.seed = seed.Get()
+	g.rand = seed.New()
 	g.reference = genDot(screenWidth/step+1, screenHeight/step+1, g.seed, g.shift)
 	for i := 0; i < 3000; i++ {
 		p := Perticle{
-			X:     rand.Float32() * screenWidth,
-			Y:     rand.Float32() * screenHeight,
-			color: colors.Random(),
+			X:     g.rand.Float32() * screenWidth,
+			Y:     g.rand.Float32() * screenHeight,
+			color: colors.Random(g.rand),
 			life:  maxLife,
 		}
 		g.perticles = append(g.perticles, p)
@@ -308,8 +313,8 @@ func NewColors(palette int) *Colors {
 	}
 	return &Colors{colors: colors}
 }
-func (c *Colors) Random() color.RGBA {
-	i := rand.Intn(len(c.colors))
+func (c *Colors) Random(r *rand.Rand) color.RGBA {
+	i := r.Intn(len(c.colors))
 	return c.colors[i]
 }
 func (c *Colors) Color(colorNo uint8) color.RGBA {
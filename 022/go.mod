@@ -3,6 +3,7 @@ module github.com/demouth/ebitengine-sketch/022
 go 1.22.6
 
 require (
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.3.0.20240902171903-b34f9977f6d3
 	github.com/solarlune/tetra3d v0.15.0
 )
@@ -17,3 +18,5 @@ require (
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.24.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
@@ -5,15 +5,16 @@ import (
 	"fmt"
 	"image/color"
 	"math"
-	"math/rand"
 
 	"github.com/solarlune/tetra3d"
 	"github.com/solarlune/tetra3d/colors"
 	"github.com/solarlune/tetra3d/examples"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 
 	"github.com/demouth/ebitengine-sketch/022/colorpallet"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 )
 
 type Game struct {
@@ -38,13 +39,14 @@ func (g *Game) Init() {
 	g.Scene.World.LightingOn = true
 	g.SystemHandler = examples.NewBasicSystemHandler(g)
 	colors := colorpallet.NewColors(0)
+	r := seed.New()
 	for i := 0; i < 100; i++ {
 		cube := tetra3d.NewModel("Cube", tetra3d.NewCubeMesh())
-		color := colors.Random()
+		color := colors.Random(r)
 		cube.Color = tetra3d.NewColor(float32(color.R)/255.0, float32(color.G)/255.0, float32(color.B)/255.0, 1)
 		g.Scene.Root.AddChildren(cube)
-		cube.SetWorldPosition((rand.Float64()-0.5)*30, (rand.Float64()-0.5)*30, (rand.Float64()-0.5)*30)
-		vec := tetra3d.Vector{X: rand.Float64() - 0.5, Y: rand.Float64() - 0.5, Z: rand.Float64() - 0.5}
+		cube.SetWorldPosition((r.Float64()-0.5)*30, (r.Float64()-0.5)*30, (r.Float64()-0.5)*30)
+		vec := tetra3d.Vector{X: r.Float64() - 0.5, Y: r.Float64() - 0.5, Z: r.Float64() - 0.5}
 		vec = vec.Unit().Scale(0.04)
 		p := &Particle{model: cube, velocity: vec}
 		g.cubes = append(g.cubes, p)
@@ -212,6 +214,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		txt := fmt.Sprintf("Camera: %v %v", g.Camera.WorldPosition(), g.Camera.CameraTilt)
 		g.Camera.DebugDrawText(screen, txt, 0, 200, 1, colors.LightGray())
 	}
+	ebitenutil.DebugPrintAt(screen, seed.Label(), 0, screen.Bounds().Dy()-16)
 }
 
 func (g *Game) Layout(w, h int) (int, int) {
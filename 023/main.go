@@ -9,7 +9,8 @@ import (
 	"math/rand/v2"
 
 	"github.com/demouth/ebitencp"
-	"github.com/demouth/ebitengine-sketch/023/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 
@@ -32,12 +33,13 @@ type Game struct {
 	space  *cp.Space
 	shader *ebiten.Shader
 	ecp    *ebitencp.Drawer
+	rand   *rand.Rand
 }
 
 func (g *Game) Update() error {
 	g.count++
 	for i := 0; i < 3; i++ {
-		addCircle(g.space, 2.5, rand.Float64()*40-20, -screenHeight/2)
+		addCircle(g.space, 2.5, g.rand.Float64()*40-20, -screenHeight/2)
 	}
 
 	margin := 10.0
@@ -104,9 +106,10 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	})
 
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
-		"FPS: %0.2f\nNumCircle: %v",
+		"FPS: %0.2f\nNumCircle: %v\n%s",
 		ebiten.ActualFPS(),
 		numCircle,
+		seed.Label(),
 	))
 }
 
@@ -129,6 +132,7 @@ func main() {
 	game.ecp = ebitencp.NewDrawer(screenWidth, screenHeight)
 	game.ecp.FlipYAxis = true
 	game.shader = s
+	game.rand = rand.New(rand.NewPCG(uint64(seed.Get()), 0))
 
 	addWall(space, -150, -120, -20, -80, 5, 0)
 	addWall(space, 150, -40, 0, 0, 5, 0)
@@ -3,6 +3,7 @@ module github.com/demouth/ebitengine-sketch/024
 go 1.22.6
 
 require (
+	github.com/demouth/ebitengine-sketch/lib/drawer v0.0.0
 	github.com/ebitengine/microui v0.0.0-20240905150630-fb9eff6e24ae
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.7
 )
@@ -20,3 +21,5 @@ require (
 	golang.org/x/sys v0.25.0 // indirect
 	golang.org/x/text v0.18.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib/drawer => ../lib/drawer
@@ -9,7 +9,7 @@ import (
 	"log"
 	"math"
 
-	"github.com/demouth/ebitengine-sketch/024/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/drawer"
 	"github.com/ebitengine/microui"
 	"github.com/hajimehoshi/ebiten/v2"
 	resources "github.com/hajimehoshi/ebiten/v2/examples/resources/images/shader"
@@ -9,7 +9,8 @@ import (
 	"math/rand"
 
 	"github.com/demouth/ebitencp"
-	"github.com/demouth/ebitengine-sketch/026/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/examples/resources/fonts"
@@ -44,6 +45,7 @@ type Game struct {
 	space  *cp.Space
 	drawer *ebitencp.Drawer
 	glyphs []*Glyph
+	rand   *rand.Rand
 }
 
 func (g *Game) Update() error {
@@ -94,9 +96,10 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	})
 
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
-		"FPS: %0.2f\nNUM: %d",
+		"FPS: %0.2f\nNUM: %d\n%s",
 		ebiten.ActualFPS(),
 		len(g.glyphs),
+		seed.Label(),
 	))
 }
 
@@ -112,6 +115,7 @@ func main() {
 	g := &Game{
 		space:  space,
 		drawer: ebitencp.NewDrawer(screenWidth, screenHeight),
+		rand:   seed.New(),
 	}
 	g.drawer.FlipYAxis = true
 	g.drawer.Camera.Offset.X = screenWidth / 2
@@ -142,7 +146,7 @@ func addGlyph(g *Game) *Glyph {
 	vertices, indices = path.AppendVerticesAndIndicesForFilling(vertices, indices)
 	s := g.space
 	circles := []*cp.Body{}
-	x := rand.Float64()*180 + 120
+	x := g.rand.Float64()*180 + 120
 	y := -300.0
 	for i := range vertices {
 		circle, _ := addCircle(
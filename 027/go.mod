@@ -4,6 +4,8 @@ go 1.22.6
 
 require (
 	github.com/demouth/ebitencp v1.3.4
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
+	github.com/demouth/ebitengine-sketch/lib/drawer v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.8.0.20240914083126-e90f99bd4a9a
 	github.com/jakecoffman/cp/v2 v2.0.2
 )
@@ -19,3 +21,7 @@ require (
 	golang.org/x/sys v0.25.0 // indirect
 	golang.org/x/text v0.18.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
+
+replace github.com/demouth/ebitengine-sketch/lib/drawer => ../lib/drawer
@@ -9,7 +9,8 @@ import (
 	"math/rand"
 
 	"github.com/demouth/ebitencp"
-	"github.com/demouth/ebitengine-sketch/027/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/examples/resources/fonts"
@@ -44,6 +45,7 @@ type Game struct {
 	space  *cp.Space
 	drawer *ebitencp.Drawer
 	glyphs []*Glyph
+	rand   *rand.Rand
 }
 
 func (g *Game) Update() error {
@@ -51,7 +53,7 @@ func (g *Game) Update() error {
 
 	if g.time%140 == 0 {
 		runes := []rune{'V', 'A', 'I', 'Y', 'T', 'N'}
-		r := runes[rand.Intn(len(runes))]
+		r := runes[g.rand.Intn(len(runes))]
 
 		g.glyphs = append(g.glyphs, addGlyph(g, string(r)))
 	}
@@ -100,9 +102,10 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	})
 
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
-		"FPS: %0.2f\nNUM: %d",
+		"FPS: %0.2f\nNUM: %d\n%s",
 		ebiten.ActualFPS(),
 		len(g.glyphs),
+		seed.Label(),
 	))
 }
 
@@ -118,6 +121,7 @@ func main() {
 	g := &Game{
 		space:  space,
 		drawer: ebitencp.NewDrawer(screenWidth, screenHeight),
+		rand:   seed.New(),
 	}
 	g.drawer.FlipYAxis = true
 	g.drawer.Camera.Offset.X = screenWidth / 2
@@ -4,6 +4,8 @@ go 1.22.6
 
 require (
 	github.com/demouth/ebitencp v1.3.5
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
+	github.com/demouth/ebitengine-sketch/lib/drawer v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.8.0.20240921073508-7bd3a05a4549
 	github.com/jakecoffman/cp/v2 v2.0.2
 	golang.org/x/image v0.20.0
@@ -19,3 +21,7 @@ require (
 	golang.org/x/sys v0.25.0 // indirect
 	golang.org/x/text v0.18.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
+
+replace github.com/demouth/ebitengine-sketch/lib/drawer => ../lib/drawer
@@ -11,7 +11,8 @@ import (
 	"math/rand/v2"
 
 	"github.com/demouth/ebitencp"
-	"github.com/demouth/ebitengine-sketch/028/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
@@ -45,22 +46,23 @@ type Game struct {
 	shader2    *ebiten.Shader
 	ecp        *ebitencp.Drawer
 	softbodies []*SoftbodyCircle
+	rand       *rand.Rand
 }
 
 func (g *Game) Update() error {
 	g.count++
 	if g.count%30 == 0 {
-		x, y := (rand.Float64()-0.5)*5, (rand.Float64()-0.5)*5
+		x, y := (g.rand.Float64()-0.5)*5, (g.rand.Float64()-0.5)*5
 		g.space.SetGravity(cp.Vector{X: x, Y: y})
 	}
 	for i := 0; i < len(g.softbodies); i++ {
-		if rand.Float64() > 0.0002 {
+		if g.rand.Float64() > 0.0002 {
 			continue
 		}
 		softbody := g.softbodies[i]
-		x, y := (rand.Float64()-0.5)*40, (rand.Float64()-0.5)*40
+		x, y := (g.rand.Float64()-0.5)*40, (g.rand.Float64()-0.5)*40
 		for i := 0; i < len(softbody.parts); i++ {
-			if rand.Float64() < 0.2 {
+			if g.rand.Float64() < 0.2 {
 				continue
 			}
 			softbody.parts[i].SetVelocity(x, y)
@@ -135,8 +137,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	// cp.DrawSpace(g.space, g.ecp.WithScreen(screen))
 
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
-		"FPS: %0.2f",
+		"FPS: %0.2f\n%s",
 		ebiten.ActualFPS(),
+		seed.Label(),
 	))
 }
 
@@ -164,6 +167,7 @@ func main() {
 
 	game := &Game{}
 	game.space = space
+	game.rand = rand.New(rand.NewPCG(uint64(seed.Get()), 0))
 	game.canvas1 = ebiten.NewImage(screenWidth, screenHeight)
 	game.canvas2 = ebiten.NewImage(screenWidth, screenHeight)
 	game.canvas3 = ebiten.NewImage(screenWidth, screenHeight)
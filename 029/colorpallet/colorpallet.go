@@ -57,8 +57,8 @@ func NewColors(palette int) *Colors {
 	}
 	return &Colors{colors: colors}
 }
-func (c *Colors) Random() color.RGBA {
-	i := rand.Intn(len(c.colors))
+func (c *Colors) Random(r *rand.Rand) color.RGBA {
+	i := r.Intn(len(c.colors))
 	return c.colors[i]
 }
 func (c *Colors) Color(colorNo uint8) color.RGBA {
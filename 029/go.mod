@@ -3,6 +3,8 @@ module github.com/demouth/ebitengine-sketch/029
 go 1.22.6
 
 require (
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
+	github.com/demouth/ebitengine-sketch/lib/drawer v0.0.0
 	github.com/fogleman/ease v0.0.0-20170301025033-8da417bf1776
 	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.8.0.20240922042725-1260d779994b
 	golang.org/x/exp v0.0.0-20240909161429-701f63a606c0
@@ -16,3 +18,7 @@ require (
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.25.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
+
+replace github.com/demouth/ebitengine-sketch/lib/drawer => ../lib/drawer
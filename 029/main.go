@@ -9,6 +9,7 @@ import (
 	"math/rand"
 
 	"github.com/demouth/ebitengine-sketch/029/colorpallet"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 )
@@ -23,6 +24,7 @@ type Game struct {
 	timer       float32
 	tiles       Tiles
 	colorpallet *colorpallet.Colors
+	rand        *rand.Rand
 }
 type Tile struct {
 	image  *ebiten.Image
@@ -58,7 +60,7 @@ func (g *Game) makeTiles(canvas *ebiten.Image) Tiles {
 
 	for x := 0; x < screenWidth; x += bigStep {
 		for y := 0; y < screenHeight; y += bigStep {
-			if rand.Float32() < 0.9 {
+			if g.rand.Float32() < 0.9 {
 				continue
 			}
 			tileImage, _ := canvas.SubImage(
@@ -66,8 +68,8 @@ func (g *Game) makeTiles(canvas *ebiten.Image) Tiles {
 			).(*ebiten.Image)
 			tile := &Tile{
 				image:  tileImage,
-				drawer: BigTileDrawerFactory(),
-				color:  g.colorpallet.Random(),
+				drawer: BigTileDrawerFactory(g.rand),
+				color:  g.colorpallet.Random(g.rand),
 			}
 			tiles = append(tiles, tile)
 		}
@@ -75,7 +77,7 @@ func (g *Game) makeTiles(canvas *ebiten.Image) Tiles {
 
 	for x := 0; x < screenWidth; x += middleStep {
 		for y := 0; y < screenHeight; y += middleStep {
-			if rand.Float32() < 0.6 {
+			if g.rand.Float32() < 0.6 {
 				continue
 			}
 			tileImage, _ := canvas.SubImage(
@@ -95,8 +97,8 @@ func (g *Game) makeTiles(canvas *ebiten.Image) Tiles {
 
 			tile := &Tile{
 				image:  tileImage,
-				drawer: MiddleTileDrawerFactory(),
-				color:  g.colorpallet.Random(),
+				drawer: MiddleTileDrawerFactory(g.rand),
+				color:  g.colorpallet.Random(g.rand),
 			}
 			tiles = append(tiles, tile)
 		}
@@ -104,7 +106,7 @@ func (g *Game) makeTiles(canvas *ebiten.Image) Tiles {
 
 	for x := 0; x < screenWidth; x += step {
 		for y := 0; y < screenHeight; y += step {
-			if rand.Float32() < 0.2 {
+			if g.rand.Float32() < 0.2 {
 				continue
 			}
 			tileImage, _ := canvas.SubImage(image.Rectangle{
@@ -125,8 +127,8 @@ func (g *Game) makeTiles(canvas *ebiten.Image) Tiles {
 
 			tile := &Tile{
 				image:  tileImage,
-				drawer: SmallTileDrawerFactory(x/step, y/step),
-				color:  g.colorpallet.Random(),
+				drawer: SmallTileDrawerFactory(g.rand, x/step, y/step),
+				color:  g.colorpallet.Random(g.rand),
 			}
 			tiles = append(tiles, tile)
 		}
@@ -149,8 +151,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	}
 	screen.DrawImage(g.canvas, nil)
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
-		"FPS: %0.2f",
+		"FPS: %0.2f\n%s",
 		ebiten.ActualFPS(),
+		seed.Label(),
 	))
 }
 
@@ -163,6 +166,7 @@ func main() {
 		canvas:      ebiten.NewImage(screenWidth, screenHeight),
 		timer:       1,
 		colorpallet: colorpallet.NewColors(2),
+		rand:        seed.New(),
 	}
 	game.canvas.Fill(color.White)
 	ebiten.SetWindowSize(screenWidth, screenHeight)
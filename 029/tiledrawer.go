@@ -5,13 +5,13 @@ import (
 	"math"
 	"math/rand"
 
-	"github.com/demouth/ebitengine-sketch/029/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/drawer"
 	"github.com/fogleman/ease"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
-func BigTileDrawerFactory() TileDrawer {
+func BigTileDrawerFactory(r *rand.Rand) TileDrawer {
 	drawers := []TileDrawer{
 		// OutBack
 		&TileDrawer11{directionType: 0},
@@ -20,10 +20,9 @@ func BigTileDrawerFactory() TileDrawer {
 		&TileDrawer12{directionType: 0},
 		&TileDrawer12{directionType: 1},
 	}
-	r := rand.Intn(len(drawers))
-	return drawers[r]
+	return drawers[r.Intn(len(drawers))]
 }
-func MiddleTileDrawerFactory() TileDrawer {
+func MiddleTileDrawerFactory(r *rand.Rand) TileDrawer {
 	drawers := []TileDrawer{
 		// circle
 		&TileDrawer1{},
@@ -36,10 +35,9 @@ func MiddleTileDrawerFactory() TileDrawer {
 		&TileDrawer9{baseAngle: math.Pi},
 		&TileDrawer9{baseAngle: math.Pi * 1.5},
 	}
-	r := rand.Intn(len(drawers))
-	return drawers[r]
+	return drawers[r.Intn(len(drawers))]
 }
-func SmallTileDrawerFactory(x, y int) TileDrawer {
+func SmallTileDrawerFactory(r *rand.Rand, x, y int) TileDrawer {
 	drawers := []TileDrawer{
 		// rect
 		&TileDrawer5{},
@@ -49,8 +47,7 @@ func SmallTileDrawerFactory(x, y int) TileDrawer {
 		// arc
 		&TileDrawer10{x: x, y: y},
 	}
-	r := rand.Intn(len(drawers))
-	return drawers[r]
+	return drawers[r.Intn(len(drawers))]
 }
 
 func easing(t float32) float32 {
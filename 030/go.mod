@@ -2,7 +2,10 @@ module github.com/demouth/ebitengine-sketch/030
 
 go 1.22.6
 
-require github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.8.0.20240922042725-1260d779994b
+require (
+	github.com/demouth/ebitengine-sketch/lib/drawer v0.0.0
+	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.8.0.20240922042725-1260d779994b
+)
 
 require (
 	github.com/ebitengine/gomobile v0.0.0-20240911145611-4856209ac325 // indirect
@@ -12,3 +15,5 @@ require (
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.25.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib/drawer => ../lib/drawer
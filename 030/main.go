@@ -7,7 +7,7 @@ import (
 	_ "image/png"
 	"math"
 
-	"github.com/demouth/ebitengine-sketch/030/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/drawer"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -6,7 +6,7 @@ import (
 	"image/color"
 	_ "image/png"
 
-	"github.com/demouth/ebitencp"
+	"github.com/demouth/ebitengine-sketch/lib/ebitencp"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/jakecoffman/cp/v2"
@@ -30,7 +30,8 @@ func (g *Game) Update() error {
 
 func (g *Game) Draw(screen *ebiten.Image) {
 	screen.Fill(color.RGBA{R: 0xfd, G: 0xfc, B: 0xdc, A: 0xff})
-	cp.DrawSpace(g.space, g.ecp.WithScreen(screen))
+	g.ecp.Screen = screen
+	g.ecp.DrawSpace(g.space)
 
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
 		"FPS: %0.2f",
@@ -89,7 +90,6 @@ func main() {
 
 	g.ecp = ebitencp.NewDrawer(0, 0)
 	g.ecp.FlipYAxis = true
-	g.ecp.OptStroke.AntiAlias = true
 	g.ecp.Theme.Shape = color.RGBA{R: 0x00, G: 0x81, B: 0xa7, A: 0xff}
 	g.ecp.Theme.Outline = color.RGBA{R: 0x00, G: 0xaf, B: 0xb9, A: 0xff}
 	g.ecp.Theme.Constraint = color.RGBA{R: 0x00, G: 0xaf, B: 0xb9, A: 0xff}
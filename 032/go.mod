@@ -4,6 +4,7 @@ go 1.23.1
 
 require (
 	github.com/demouth/ebitencp v1.5.0
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.0
 	github.com/jakecoffman/cp/v2 v2.0.2
 )
@@ -16,3 +17,5 @@ require (
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.25.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
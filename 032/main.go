@@ -12,6 +12,7 @@ import (
 	"math/rand"
 
 	"github.com/demouth/ebitencp"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/jakecoffman/cp/v2"
@@ -49,10 +50,11 @@ type Game struct {
 	ecp    *ebitencp.Drawer
 	tofus  Tofus
 	shader *ebiten.Shader
+	rand   *rand.Rand
 }
 
 func (g *Game) Update() error {
-	g.tofus.Update()
+	g.tofus.Update(g.rand)
 	g.space.Step(1.0 / 60.0)
 	return nil
 }
@@ -88,8 +90,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	}
 
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
-		"FPS: %0.2f",
+		"FPS: %0.2f\n%s",
 		ebiten.ActualFPS(),
+		seed.Label(),
 	))
 }
 
@@ -108,16 +111,18 @@ func main() {
 	space.SetDamping(0.2)
 	space.SetGravity(cp.Vector{X: 0, Y: 0})
 
+	r := seed.New()
 	tofus := Tofus{}
 	for i := 0; i < 220; i++ {
-		tofu := NewTofu(space, screenWidth*rand.Float64(), screenHeight*rand.Float64(), 8)
+		tofu := NewTofu(space, r, screenWidth*r.Float64(), screenHeight*r.Float64(), 8)
 		tofus = append(tofus, tofu)
 	}
 	for _, tofu := range tofus {
-		tofu.Add(rand.Float64()*100-50, rand.Float64()*100-50)
+		tofu.Add(r.Float64()*100-50, r.Float64()*100-50)
 	}
 
 	g := &Game{}
+	g.rand = r
 	g.space = space
 	g.tofus = tofus
 	g.shader = shader
@@ -148,7 +153,7 @@ func addCircle(space *cp.Space, radius float64, x, y, elasticity float64) (*cp.B
 
 type Tofus []*Tofu
 
-func (t Tofus) Update() {
+func (t Tofus) Update(r *rand.Rand) {
 	for i := 0; i < len(t); i++ {
 		t1 := t[i]
 		var (
@@ -230,7 +235,7 @@ func (t Tofus) Update() {
 		t[i].Add(vec.X, vec.Y)
 		// When it stops, move it in a random direction
 		if t[i].vec.Length() < 1 {
-			vec := cp.Vector{X: (rand.Float64() - 0.5) * 100, Y: (rand.Float64() - 0.5) * 100}
+			vec := cp.Vector{X: (r.Float64() - 0.5) * 100, Y: (r.Float64() - 0.5) * 100}
 			t[i].Add(vec.X, vec.Y)
 		}
 	}
@@ -302,7 +307,7 @@ func (t *Tofu) Draw(screen *ebiten.Image) {
 	)
 }
 
-func NewTofu(space *cp.Space, startX, startY, step float64) *Tofu {
+func NewTofu(space *cp.Space, rnd *rand.Rand, startX, startY, step float64) *Tofu {
 	type circleForJoints struct {
 		body *cp.Body
 		x    int
@@ -354,7 +359,7 @@ func NewTofu(space *cp.Space, startX, startY, step float64) *Tofu {
 		}
 	}
 	color := TofuColorRed
-	if rand.Float64() < 0.5 {
+	if rnd.Float64() < 0.5 {
 		color = TofuColorBlack
 	}
 	t := &Tofu{
@@ -2,7 +2,7 @@ package colorpallet
 
 import (
 	"image/color"
-	"math/rand"
+	"math/rand/v2"
 )
 
 type Colors struct {
@@ -57,8 +57,8 @@ func NewColors(palette int) *Colors {
 	}
 	return &Colors{colors: colors}
 }
-func (c *Colors) Random() color.RGBA {
-	i := rand.Intn(len(c.colors))
+func (c *Colors) Random(r *rand.Rand) color.RGBA {
+	i := r.IntN(len(c.colors))
 	return c.colors[i]
 }
 func (c *Colors) Color(colorNo uint8) color.RGBA {
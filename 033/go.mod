@@ -3,7 +3,8 @@ module github.com/demouth/ebitengine-sketch/033
 go 1.23.3
 
 require (
-	github.com/demouth/ebitencp v1.5.0
+	github.com/demouth/ebitengine-sketch/lib v0.0.0
+	github.com/demouth/ebitengine-sketch/lib/ebitencp v0.0.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.5
 	github.com/jakecoffman/cp/v2 v2.1.0
 )
@@ -16,3 +17,7 @@ require (
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.25.0 // indirect
 )
+
+replace github.com/demouth/ebitengine-sketch/lib => ../lib
+
+replace github.com/demouth/ebitengine-sketch/lib/ebitencp => ../lib/ebitencp
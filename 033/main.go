@@ -6,11 +6,11 @@ import (
 	"log"
 	"math/rand/v2"
 
-	"github.com/demouth/ebitencp"
 	"github.com/demouth/ebitengine-sketch/033/colorpallet"
-	"github.com/demouth/ebitengine-sketch/033/drawer"
+	"github.com/demouth/ebitengine-sketch/lib/ebitencp"
+	"github.com/demouth/ebitengine-sketch/lib/seed"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 
 	"github.com/jakecoffman/cp/v2"
 )
@@ -26,6 +26,9 @@ type Game struct {
 	space   *cp.Space
 	drawer  *ebitencp.Drawer
 	counter uint64
+	rand    *rand.Rand
+	// verts are the corners of the block being drawn.
+	verts []cp.Vector
 }
 
 func (g *Game) Update() error {
@@ -73,35 +76,21 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		switch shape.Class.(type) {
 		case *cp.Circle:
 			circle := shape.Class.(*cp.Circle)
-			c := shape.UserData.(color.RGBA)
-			drawer.DrawCircle(
-				screen,
-				float32(body.Position().X),
-				float32(body.Position().Y),
-				float32(circle.Radius()),
-				c,
-			)
+			c := ebitencp.FColor(shape.UserData.(color.RGBA))
+			g.drawer.DrawCircle(body.Position(), body.Angle(), circle.Radius(), c, c, nil)
 		case *cp.PolyShape:
 			poly := shape.Class.(*cp.PolyShape)
-			c := shape.UserData.(color.RGBA)
-			path := vector.Path{}
+			c := ebitencp.FColor(shape.UserData.(color.RGBA))
+			g.verts = g.verts[:0]
 			for i, l := 0, poly.Count(); i < l; i++ {
-				v := poly.Vert(i)
-				v = body.LocalToWorld(v)
-				if i == 0 {
-					path.MoveTo(float32(v.X), float32(v.Y))
-				} else {
-					path.LineTo(float32(v.X), float32(v.Y))
-				}
+				g.verts = append(g.verts, body.LocalToWorld(poly.Vert(i)))
 			}
-			path.Close()
-			drawer.DrawFill(
-				screen,
-				path,
-				c,
-			)
+			g.drawer.DrawPolygon(len(g.verts), g.verts, 0, c, c, nil)
 		}
 	})
+	// every block goes out in one DrawTriangles
+	g.drawer.Flush()
+	ebitenutil.DebugPrint(screen, seed.Label())
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
@@ -111,20 +100,20 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 func (g *Game) initCP() {
 	space := g.space
 
-	boxes := NewBoxes(0, 0, screenWidth, screenHeight)
+	boxes := NewBoxes(g.rand, 0, 0, screenWidth, screenHeight)
 	const m = 0.999
 	for _, box := range boxes {
 		shiftY := -float64(screenHeight)
-		if rand.Float64() < 0.95 {
+		if g.rand.Float64() < 0.95 {
 			addBox(
-				space, box.w*m,
+				space, g.rand, box.w*m,
 				box.h*m,
 				box.x+box.w/2,
 				(box.y+box.h/2)*2+shiftY*2,
 			)
 		} else {
 			addBall(
-				space,
+				space, g.rand,
 				box.x+box.w/2,
 				(box.y+box.h/2)*2+shiftY*2,
 				box.w/2*m,
@@ -142,9 +131,10 @@ func main() {
 	// Initialising Ebitengine/v2
 	game := &Game{}
 	game.space = space
+	game.rand = rand.New(rand.NewPCG(uint64(seed.Get()), 0))
 	game.drawer = ebitencp.NewDrawer(screenWidth, screenHeight)
-	game.drawer.GeoM.Translate(-hScreenWidth, -hScreenHeight)
 	game.drawer.FlipYAxis = true
+	game.drawer.Camera.Position = cp.Vector{X: hScreenWidth, Y: hScreenHeight}
 	game.initCP()
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("ebitengine-sketch 033")
@@ -155,17 +145,17 @@ func main() {
 
 var pallet = colorpallet.NewColors(2)
 
-func addBall(space *cp.Space, x, y, radius float64) {
+func addBall(space *cp.Space, r *rand.Rand, x, y, radius float64) {
 	mass := radius * radius / 25.0
 	body := space.AddBody(cp.NewBody(mass, cp.MomentForCircle(mass, 0, radius, cp.Vector{})))
 	body.SetPosition(cp.Vector{X: x, Y: y})
 	shape := space.AddShape(cp.NewCircle(body, radius, cp.Vector{}))
 	shape.SetElasticity(0.0)
 	shape.SetFriction(1.0)
-	shape.UserData = pallet.Random()
+	shape.UserData = pallet.Random(r)
 }
 
-func addBox(space *cp.Space, w, h float64, x, y float64) {
+func addBox(space *cp.Space, r *rand.Rand, w, h float64, x, y float64) {
 	mass := w * h / 25.0
 	body := space.AddBody(cp.NewBody(mass, cp.MomentForBox(mass, w, h)))
 	body.SetPosition(cp.Vector{X: x, Y: y})
@@ -173,7 +163,7 @@ func addBox(space *cp.Space, w, h float64, x, y float64) {
 	shape := space.AddShape(cp.NewBox(body, w, h, 0))
 	shape.SetElasticity(0.0)
 	shape.SetFriction(1.0)
-	shape.UserData = pallet.Random()
+	shape.UserData = pallet.Random(r)
 }
 func removeBodyCallback(space *cp.Space, key interface{}, data interface{}) {
 	var b *cp.Body
@@ -206,10 +196,10 @@ type Box struct {
 }
 type Boxes []*Box
 
-func (b *Box) SplitRandom() []*Box {
-	return b.split()
+func (b *Box) SplitRandom(r *rand.Rand) []*Box {
+	return b.split(r)
 }
-func (b *Box) split() []*Box {
+func (b *Box) split(r *rand.Rand) []*Box {
 	splited := make([]*Box, 4)
 	splited[0] = &Box{w: b.w / 2, h: b.h / 2, x: b.x, y: b.y, count: b.count + 1}
 	splited[1] = &Box{w: b.w / 2, h: b.h / 2, x: b.x, y: b.y + b.h/2, count: b.count + 1}
@@ -219,8 +209,8 @@ func (b *Box) split() []*Box {
 	ret := make([]*Box, 0, len(splited))
 
 	for _, box := range splited {
-		if b.count < 5 && rand.Float64() < 0.6 {
-			ret = append(ret, box.split()...)
+		if b.count < 5 && r.Float64() < 0.6 {
+			ret = append(ret, box.split(r)...)
 		} else {
 			ret = append(ret, box)
 		}
@@ -228,7 +218,7 @@ func (b *Box) split() []*Box {
 	return ret
 }
 
-func NewBoxes(x, y, w, h float64) Boxes {
+func NewBoxes(r *rand.Rand, x, y, w, h float64) Boxes {
 	box := &Box{w: w, h: h, x: x, y: y, count: 0}
-	return box.SplitRandom()
+	return box.SplitRandom(r)
 }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func layout(seed uint64) []Box {
+	var boxes []Box
+	for _, b := range NewBoxes(rand.New(rand.NewPCG(seed, 0)), 0, 0, screenWidth, screenHeight) {
+		boxes = append(boxes, *b)
+	}
+	return boxes
+}
+
+func TestBoxSplitSeed(t *testing.T) {
+	tests := []uint64{0, 1, 42, 1 << 40}
+	for _, seed := range tests {
+		a, b := layout(seed), layout(seed)
+		if len(a) != len(b) {
+			t.Fatalf("seed %d: %d boxes, then %d", seed, len(a), len(b))
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				t.Errorf("seed %d: box %d is %+v, then %+v", seed, i, a[i], b[i])
+			}
+		}
+
+		// the boxes tile the screen
+		area := 0.0
+		for _, box := range a {
+			area += box.w * box.h
+		}
+		if area != screenWidth*screenHeight {
+			t.Errorf("seed %d: boxes cover %v, want %v", seed, area, screenWidth*screenHeight)
+		}
+	}
+
+	if a, b := layout(1), layout(2); len(a) == len(b) && a[0] == b[0] && a[len(a)-1] == b[len(b)-1] {
+		t.Errorf("seeds 1 and 2 give the same layout")
+	}
+}
@@ -0,0 +1,494 @@
+// Package cpjson saves a chipmunk space to JSON and builds it back.
+//
+// It keeps the space settings, the bodies with their circle, segment and
+// poly shapes, and pin, pivot and slide joints, damped springs, simple
+// motors and gear joints. Collision handlers, arbiters and the contacts
+// that warm start the solver are not kept, so a restored space follows
+// the original closely but not bit for bit.
+//
+// Constraints attached to a body that is not in the space, like the body
+// that follows the mouse, are skipped.
+package cpjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Options customise how user data is saved. Without the funcs, the
+// UserData of bodies, shapes and constraints is dropped.
+type Options struct {
+	// MarshalUserData turns the UserData of a body, shape or constraint
+	// into JSON. A nil result leaves it out.
+	MarshalUserData func(data interface{}) (json.RawMessage, error)
+	// UnmarshalUserData turns saved JSON back into UserData.
+	UnmarshalUserData func(data json.RawMessage) (interface{}, error)
+}
+
+// staticBody refers to the static body of the space in constraints.
+const staticBody = -1
+
+type document struct {
+	Gravity            vector       `json:"gravity"`
+	Damping            number       `json:"damping"`
+	Iterations         uint         `json:"iterations"`
+	SleepTimeThreshold number       `json:"sleepTimeThreshold"`
+	StaticShapes       []shape      `json:"staticShapes,omitempty"`
+	Bodies             []body       `json:"bodies,omitempty"`
+	Constraints        []constraint `json:"constraints,omitempty"`
+}
+
+type body struct {
+	Type            string          `json:"type"`
+	Mass            number          `json:"mass,omitempty"`
+	Moment          number          `json:"moment,omitempty"`
+	CenterOfGravity vector          `json:"centerOfGravity"`
+	Position        vector          `json:"position"`
+	Angle           number          `json:"angle"`
+	Velocity        vector          `json:"velocity"`
+	AngularVelocity number          `json:"angularVelocity"`
+	Shapes          []shape         `json:"shapes,omitempty"`
+	UserData        json.RawMessage `json:"userData,omitempty"`
+}
+
+type shape struct {
+	Type          string          `json:"type"`
+	Offset        *vector         `json:"offset,omitempty"`
+	A             *vector         `json:"a,omitempty"`
+	B             *vector         `json:"b,omitempty"`
+	Verts         []vector        `json:"verts,omitempty"`
+	Radius        number          `json:"radius"`
+	Friction      number          `json:"friction"`
+	Elasticity    number          `json:"elasticity"`
+	Filter        filter          `json:"filter"`
+	CollisionType uint64          `json:"collisionType,omitempty"`
+	Sensor        bool            `json:"sensor,omitempty"`
+	UserData      json.RawMessage `json:"userData,omitempty"`
+}
+
+type filter struct {
+	Group      uint `json:"group"`
+	Categories uint `json:"categories"`
+	Mask       uint `json:"mask"`
+}
+
+type constraint struct {
+	Type          string          `json:"type"`
+	A             int             `json:"a"`
+	B             int             `json:"b"`
+	AnchorA       *vector         `json:"anchorA,omitempty"`
+	AnchorB       *vector         `json:"anchorB,omitempty"`
+	Dist          *number         `json:"dist,omitempty"`
+	Min           *number         `json:"min,omitempty"`
+	Max           *number         `json:"max,omitempty"`
+	RestLength    *number         `json:"restLength,omitempty"`
+	Stiffness     *number         `json:"stiffness,omitempty"`
+	Damping       *number         `json:"damping,omitempty"`
+	Rate          *number         `json:"rate,omitempty"`
+	Phase         *number         `json:"phase,omitempty"`
+	Ratio         *number         `json:"ratio,omitempty"`
+	MaxForce      number          `json:"maxForce"`
+	ErrorBias     number          `json:"errorBias"`
+	MaxBias       number          `json:"maxBias"`
+	CollideBodies bool            `json:"collideBodies"`
+	UserData      json.RawMessage `json:"userData,omitempty"`
+}
+
+type vector struct {
+	X number `json:"x"`
+	Y number `json:"y"`
+}
+
+func toVector(v cp.Vector) vector {
+	return vector{number(v.X), number(v.Y)}
+}
+
+func (v vector) cp() cp.Vector {
+	return cp.Vector{X: float64(v.X), Y: float64(v.Y)}
+}
+
+// number is a float64 that keeps cp.INFINITY, which JSON has no literal
+// for. Infinite max forces and moments are common.
+type number float64
+
+func (n number) MarshalJSON() ([]byte, error) {
+	switch {
+	case math.IsInf(float64(n), 1):
+		return []byte(`"inf"`), nil
+	case math.IsInf(float64(n), -1):
+		return []byte(`"-inf"`), nil
+	case math.IsNaN(float64(n)):
+		return nil, fmt.Errorf("cpjson: NaN can't be saved")
+	}
+	return json.Marshal(float64(n))
+}
+
+func (n *number) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `"inf"`:
+		*n = number(math.Inf(1))
+		return nil
+	case `"-inf"`:
+		*n = number(math.Inf(-1))
+		return nil
+	}
+	var f float64
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*n = number(f)
+	return nil
+}
+
+func ptr(f float64) *number {
+	n := number(f)
+	return &n
+}
+
+func (n *number) value() float64 {
+	if n == nil {
+		return 0
+	}
+	return float64(*n)
+}
+
+var bodyTypes = map[int]string{
+	cp.BODY_DYNAMIC:   "dynamic",
+	cp.BODY_KINEMATIC: "kinematic",
+	cp.BODY_STATIC:    "static",
+}
+
+// Marshal returns the JSON of space.
+func Marshal(space *cp.Space, options Options) ([]byte, error) {
+	e := encoder{options: options, index: map[*cp.Body]int{space.StaticBody: staticBody}}
+	doc := document{
+		Gravity:            toVector(space.Gravity()),
+		Damping:            number(space.Damping()),
+		Iterations:         space.Iterations,
+		SleepTimeThreshold: number(space.SleepTimeThreshold),
+	}
+
+	doc.StaticShapes = e.shapes(space, space.StaticBody)
+	space.EachBody(func(b *cp.Body) {
+		if b == space.StaticBody {
+			return
+		}
+		e.index[b] = len(doc.Bodies)
+		doc.Bodies = append(doc.Bodies, e.body(space, b))
+	})
+	space.EachConstraint(func(c *cp.Constraint) {
+		if s, ok := e.constraint(c); ok {
+			doc.Constraints = append(doc.Constraints, s)
+		}
+	})
+	if e.err != nil {
+		return nil, e.err
+	}
+	return json.MarshalIndent(doc, "", "\t")
+}
+
+type encoder struct {
+	options Options
+	index   map[*cp.Body]int
+	err     error
+}
+
+func (e *encoder) userData(data interface{}) json.RawMessage {
+	if data == nil || e.options.MarshalUserData == nil || e.err != nil {
+		return nil
+	}
+	raw, err := e.options.MarshalUserData(data)
+	if err != nil {
+		e.err = fmt.Errorf("cpjson: user data: %w", err)
+	}
+	return raw
+}
+
+func (e *encoder) body(space *cp.Space, b *cp.Body) body {
+	s := body{
+		Type:            bodyTypes[b.GetType()],
+		CenterOfGravity: toVector(b.CenterOfGravity()),
+		Position:        toVector(b.Position()),
+		Angle:           number(b.Angle()),
+		Velocity:        toVector(b.Velocity()),
+		AngularVelocity: number(b.AngularVelocity()),
+		Shapes:          e.shapes(space, b),
+		UserData:        e.userData(b.UserData),
+	}
+	if b.GetType() == cp.BODY_DYNAMIC {
+		s.Mass = number(b.Mass())
+		s.Moment = number(b.Moment())
+	}
+	return s
+}
+
+func (e *encoder) shapes(space *cp.Space, b *cp.Body) []shape {
+	var shapes []shape
+	b.EachShape(func(sh *cp.Shape) {
+		if sh.Space() != space {
+			return
+		}
+		if s, ok := e.shape(sh); ok {
+			shapes = append(shapes, s)
+		}
+	})
+	return shapes
+}
+
+func (e *encoder) shape(sh *cp.Shape) (shape, bool) {
+	f := sh.Filter()
+	s := shape{
+		Friction:      number(sh.Friction()),
+		Elasticity:    number(sh.Elasticity()),
+		Filter:        filter{f.Group, f.Categories, f.Mask},
+		CollisionType: uint64(sh.CollisionType()),
+		Sensor:        sh.Sensor(),
+		UserData:      e.userData(sh.UserData),
+	}
+	switch class := sh.Class.(type) {
+	case *cp.Circle:
+		// the centre of gravity of a circle is its offset
+		offset := toVector(sh.CenterOfGravity())
+		s.Type = "circle"
+		s.Offset = &offset
+		s.Radius = number(class.Radius())
+	case *cp.Segment:
+		a, b := toVector(class.A()), toVector(class.B())
+		s.Type = "segment"
+		s.A, s.B = &a, &b
+		s.Radius = number(class.Radius())
+	case *cp.PolyShape:
+		s.Type = "poly"
+		for i := 0; i < class.Count(); i++ {
+			s.Verts = append(s.Verts, toVector(class.Vert(i)))
+		}
+		s.Radius = number(class.Radius())
+	default:
+		return s, false
+	}
+	return s, true
+}
+
+func (e *encoder) constraint(c *cp.Constraint) (constraint, bool) {
+	a, okA := e.index[c.BodyA()]
+	b, okB := e.index[c.BodyB()]
+	if !okA || !okB {
+		return constraint{}, false
+	}
+	s := constraint{
+		A:             a,
+		B:             b,
+		MaxForce:      number(c.MaxForce()),
+		ErrorBias:     number(c.ErrorBias()),
+		MaxBias:       number(c.MaxBias()),
+		CollideBodies: c.CollideBodies(),
+		UserData:      e.userData(c.UserData),
+	}
+	switch class := c.Class.(type) {
+	case *cp.PinJoint:
+		s.Type = "pin"
+		s.AnchorA, s.AnchorB = anchors(class.AnchorA, class.AnchorB)
+		s.Dist = ptr(class.Dist)
+	case *cp.PivotJoint:
+		s.Type = "pivot"
+		s.AnchorA, s.AnchorB = anchors(class.AnchorA, class.AnchorB)
+	case *cp.SlideJoint:
+		s.Type = "slide"
+		s.AnchorA, s.AnchorB = anchors(class.AnchorA, class.AnchorB)
+		s.Min, s.Max = ptr(class.Min), ptr(class.Max)
+	case *cp.DampedSpring:
+		s.Type = "dampedSpring"
+		s.AnchorA, s.AnchorB = anchors(class.AnchorA, class.AnchorB)
+		s.RestLength = ptr(class.RestLength)
+		s.Stiffness = ptr(class.Stiffness)
+		s.Damping = ptr(class.Damping)
+	case *cp.SimpleMotor:
+		s.Type = "simpleMotor"
+		s.Rate = ptr(class.Rate)
+	case *cp.GearJoint:
+		s.Type = "gear"
+		s.Phase, s.Ratio = ptr(class.Phase), ptr(class.Ratio)
+	default:
+		return s, false
+	}
+	return s, true
+}
+
+func anchors(a, b cp.Vector) (*vector, *vector) {
+	va, vb := toVector(a), toVector(b)
+	return &va, &vb
+}
+
+// Unmarshal builds a new space from JSON written by Marshal.
+func Unmarshal(data []byte, options Options) (*cp.Space, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("cpjson: %w", err)
+	}
+
+	d := decoder{options: options}
+	space := cp.NewSpace()
+	space.SetGravity(doc.Gravity.cp())
+	space.SetDamping(float64(doc.Damping))
+	space.Iterations = doc.Iterations
+	space.SleepTimeThreshold = float64(doc.SleepTimeThreshold)
+
+	bodies := make([]*cp.Body, len(doc.Bodies))
+	for i, s := range doc.Bodies {
+		b, err := d.body(s)
+		if err != nil {
+			return nil, fmt.Errorf("cpjson: body %d: %w", i, err)
+		}
+		bodies[i] = space.AddBody(b)
+		if err := d.addShapes(space, b, s.Shapes); err != nil {
+			return nil, fmt.Errorf("cpjson: body %d: %w", i, err)
+		}
+	}
+	if err := d.addShapes(space, space.StaticBody, doc.StaticShapes); err != nil {
+		return nil, fmt.Errorf("cpjson: static body: %w", err)
+	}
+
+	lookup := func(i int) (*cp.Body, error) {
+		if i == staticBody {
+			return space.StaticBody, nil
+		}
+		if i < 0 || i >= len(bodies) {
+			return nil, fmt.Errorf("no body %d", i)
+		}
+		return bodies[i], nil
+	}
+	for i, s := range doc.Constraints {
+		a, err := lookup(s.A)
+		if err != nil {
+			return nil, fmt.Errorf("cpjson: constraint %d: %w", i, err)
+		}
+		b, err := lookup(s.B)
+		if err != nil {
+			return nil, fmt.Errorf("cpjson: constraint %d: %w", i, err)
+		}
+		c, err := d.constraint(s, a, b)
+		if err != nil {
+			return nil, fmt.Errorf("cpjson: constraint %d: %w", i, err)
+		}
+		space.AddConstraint(c)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return space, nil
+}
+
+type decoder struct {
+	options Options
+	err     error
+}
+
+func (d *decoder) userData(data json.RawMessage) interface{} {
+	if data == nil || d.options.UnmarshalUserData == nil || d.err != nil {
+		return nil
+	}
+	v, err := d.options.UnmarshalUserData(data)
+	if err != nil {
+		d.err = fmt.Errorf("cpjson: user data: %w", err)
+	}
+	return v
+}
+
+func (d *decoder) body(s body) (*cp.Body, error) {
+	var b *cp.Body
+	switch s.Type {
+	case "dynamic":
+		b = cp.NewBody(float64(s.Mass), float64(s.Moment))
+	case "kinematic":
+		b = cp.NewKinematicBody()
+	case "static":
+		b = cp.NewStaticBody()
+	default:
+		return nil, fmt.Errorf("unknown body type %q", s.Type)
+	}
+	// The position is of the body's origin, so it is set after the centre
+	// of gravity and the angle, which move the origin around it.
+	b.SetCenterOfGravity(s.CenterOfGravity.cp())
+	b.SetAngle(float64(s.Angle))
+	b.SetPosition(s.Position.cp())
+	b.SetVelocityVector(s.Velocity.cp())
+	b.SetAngularVelocity(float64(s.AngularVelocity))
+	b.UserData = d.userData(s.UserData)
+	return b, nil
+}
+
+func (d *decoder) addShapes(space *cp.Space, b *cp.Body, shapes []shape) error {
+	for i, s := range shapes {
+		var sh *cp.Shape
+		r := float64(s.Radius)
+		switch s.Type {
+		case "circle":
+			if s.Offset == nil {
+				return fmt.Errorf("shape %d: circle without an offset", i)
+			}
+			sh = cp.NewCircle(b, r, s.Offset.cp())
+		case "segment":
+			if s.A == nil || s.B == nil {
+				return fmt.Errorf("shape %d: segment without ends", i)
+			}
+			sh = cp.NewSegment(b, s.A.cp(), s.B.cp(), r)
+		case "poly":
+			if len(s.Verts) < 3 {
+				return fmt.Errorf("shape %d: poly with %d verts", i, len(s.Verts))
+			}
+			verts := make([]cp.Vector, len(s.Verts))
+			for j, v := range s.Verts {
+				verts[j] = v.cp()
+			}
+			sh = cp.NewPolyShapeRaw(b, len(verts), verts, r)
+		default:
+			return fmt.Errorf("shape %d: unknown shape type %q", i, s.Type)
+		}
+		sh.SetFriction(float64(s.Friction))
+		sh.SetElasticity(float64(s.Elasticity))
+		sh.SetFilter(cp.NewShapeFilter(s.Filter.Group, s.Filter.Categories, s.Filter.Mask))
+		sh.SetCollisionType(cp.CollisionType(s.CollisionType))
+		sh.SetSensor(s.Sensor)
+		sh.UserData = d.userData(s.UserData)
+		space.AddShape(sh)
+	}
+	return nil
+}
+
+func (d *decoder) constraint(s constraint, a, b *cp.Body) (*cp.Constraint, error) {
+	var c *cp.Constraint
+	switch s.Type {
+	case "pin", "pivot", "slide", "dampedSpring":
+		if s.AnchorA == nil || s.AnchorB == nil {
+			return nil, fmt.Errorf("%s without anchors", s.Type)
+		}
+	}
+	switch s.Type {
+	case "pin":
+		c = cp.NewPinJoint(a, b, s.AnchorA.cp(), s.AnchorB.cp())
+		// NewPinJoint measures the distance from where the bodies are
+		// now; the saved one may have been changed since.
+		c.Class.(*cp.PinJoint).Dist = s.Dist.value()
+	case "pivot":
+		c = cp.NewPivotJoint2(a, b, s.AnchorA.cp(), s.AnchorB.cp())
+	case "slide":
+		c = cp.NewSlideJoint(a, b, s.AnchorA.cp(), s.AnchorB.cp(), s.Min.value(), s.Max.value())
+	case "dampedSpring":
+		c = cp.NewDampedSpring(a, b, s.AnchorA.cp(), s.AnchorB.cp(), s.RestLength.value(), s.Stiffness.value(), s.Damping.value())
+	case "simpleMotor":
+		c = cp.NewSimpleMotor(a, b, s.Rate.value())
+	case "gear":
+		c = cp.NewGearJoint(a, b, s.Phase.value(), s.Ratio.value())
+	default:
+		return nil, fmt.Errorf("unknown constraint type %q", s.Type)
+	}
+	c.SetMaxForce(float64(s.MaxForce))
+	c.SetErrorBias(float64(s.ErrorBias))
+	c.SetMaxBias(float64(s.MaxBias))
+	c.SetCollideBodies(s.CollideBodies)
+	c.UserData = d.userData(s.UserData)
+	return c, nil
+}
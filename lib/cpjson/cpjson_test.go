@@ -0,0 +1,220 @@
+package cpjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// scene builds a space with every kind of shape and constraint that is
+// saved, calm enough that the original and the restored copy don't drift
+// apart.
+func scene() *cp.Space {
+	space := cp.NewSpace()
+	space.SetGravity(cp.Vector{X: 0, Y: 500})
+	space.SetDamping(0.9)
+	space.Iterations = 20
+	space.SleepTimeThreshold = 0.5
+
+	floor := space.AddShape(cp.NewSegment(space.StaticBody, cp.Vector{X: -400, Y: 300}, cp.Vector{X: 400, Y: 300}, 2))
+	floor.SetFriction(1)
+	floor.SetElasticity(0.5)
+
+	// a pendulum chain hanging from the static body
+	prev := space.StaticBody
+	anchor := cp.Vector{X: -200, Y: -200}
+	for i := 0; i < 3; i++ {
+		b := space.AddBody(cp.NewBody(1, cp.MomentForBox(1, 20, 5)))
+		b.SetPosition(cp.Vector{X: -200 + float64(i+1)*25, Y: -200})
+		space.AddShape(cp.NewBox(b, 20, 5, 0)).SetFilter(cp.NewShapeFilter(1, cp.ALL_CATEGORIES, cp.ALL_CATEGORIES))
+		space.AddConstraint(cp.NewPivotJoint(prev, b, anchor))
+		prev = b
+		anchor = b.Position().Add(cp.Vector{X: 12.5, Y: 0})
+	}
+	pin := space.AddConstraint(cp.NewPinJoint(prev, space.StaticBody, cp.Vector{}, cp.Vector{X: -100, Y: -250}))
+	pin.Class.(*cp.PinJoint).Dist = 60
+
+	// a ball on a spring, held on a slide joint
+	ball := space.AddBody(cp.NewBody(2, cp.MomentForCircle(2, 0, 15, cp.Vector{})))
+	ball.SetPosition(cp.Vector{X: 100, Y: 0})
+	ball.SetVelocity(30, 0)
+	circle := space.AddShape(cp.NewCircle(ball, 15, cp.Vector{}))
+	circle.SetElasticity(0.2)
+	circle.SetCollisionType(2)
+	space.AddConstraint(cp.NewDampedSpring(space.StaticBody, ball, cp.Vector{X: 100, Y: -100}, cp.Vector{}, 80, 40, 2))
+	slide := space.AddConstraint(cp.NewSlideJoint(space.StaticBody, ball, cp.Vector{X: 100, Y: -100}, cp.Vector{}, 20, 150))
+	slide.SetMaxForce(1000)
+
+	// two wheels turned by a motor and geared to each other
+	wheels := make([]*cp.Body, 2)
+	for i := range wheels {
+		b := space.AddBody(cp.NewBody(1, cp.MomentForCircle(1, 0, 20, cp.Vector{})))
+		b.SetPosition(cp.Vector{X: 200 + float64(i)*50, Y: 100})
+		b.SetAngle(0.3)
+		space.AddShape(cp.NewCircle(b, 20, cp.Vector{X: 2, Y: 0}))
+		space.AddConstraint(cp.NewPivotJoint(space.StaticBody, b, b.Position())).SetCollideBodies(false)
+		wheels[i] = b
+	}
+	space.AddConstraint(cp.NewSimpleMotor(space.StaticBody, wheels[0], 2)).SetMaxForce(50000)
+	space.AddConstraint(cp.NewGearJoint(wheels[0], wheels[1], 0, -1))
+
+	// balls falling on the floor
+	for i := 0; i < 4; i++ {
+		b := space.AddBody(cp.NewBody(1, cp.INFINITY))
+		b.SetPosition(cp.Vector{X: -50 + float64(i)*30, Y: 200 - float64(i)*20})
+		space.AddShape(cp.NewCircle(b, 10, cp.Vector{})).SetFriction(0.7)
+	}
+
+	// a kinematic paddle
+	paddle := space.AddBody(cp.NewKinematicBody())
+	paddle.SetPosition(cp.Vector{X: -300, Y: 250})
+	paddle.SetAngularVelocity(1)
+	space.AddShape(cp.NewSegment(paddle, cp.Vector{X: -30, Y: 0}, cp.Vector{X: 30, Y: 0}, 4))
+
+	return space
+}
+
+func bodies(space *cp.Space) []*cp.Body {
+	var bodies []*cp.Body
+	space.EachBody(func(b *cp.Body) {
+		bodies = append(bodies, b)
+	})
+	return bodies
+}
+
+func TestRoundTrip(t *testing.T) {
+	original := scene()
+	// moved and turned bodies keep the local offsets and ends of their shapes
+	for i := 0; i < 10; i++ {
+		original.Step(1.0 / 60)
+	}
+	data, err := Marshal(original, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := Unmarshal(data, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := Marshal(restored, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Fatalf("marshalling the restored space gives different JSON:\n%s\nthen\n%s", data, again)
+	}
+
+	a, b := bodies(original), bodies(restored)
+	if len(a) != len(b) {
+		t.Fatalf("%d bodies restored, want %d", len(b), len(a))
+	}
+
+	const eps = 1e-6
+	for step := 0; step < 120; step++ {
+		original.Step(1.0 / 60)
+		restored.Step(1.0 / 60)
+		for i := range a {
+			if d := a[i].Position().Distance(b[i].Position()); d > eps {
+				t.Fatalf("step %d: body %d is at %v, want %v", step, i, b[i].Position(), a[i].Position())
+			}
+			if d := a[i].Velocity().Distance(b[i].Velocity()); d > eps {
+				t.Fatalf("step %d: body %d moves at %v, want %v", step, i, b[i].Velocity(), a[i].Velocity())
+			}
+			if d := math.Abs(a[i].Angle() - b[i].Angle()); d > eps {
+				t.Fatalf("step %d: body %d is at angle %v, want %v", step, i, b[i].Angle(), a[i].Angle())
+			}
+			if d := math.Abs(a[i].AngularVelocity() - b[i].AngularVelocity()); d > eps {
+				t.Fatalf("step %d: body %d turns at %v, want %v", step, i, b[i].AngularVelocity(), a[i].AngularVelocity())
+			}
+		}
+	}
+}
+
+func TestUserData(t *testing.T) {
+	space := cp.NewSpace()
+	b := space.AddBody(cp.NewBody(1, 1))
+	b.UserData = "body"
+	space.AddShape(cp.NewCircle(b, 5, cp.Vector{})).UserData = "circle"
+	space.AddShape(cp.NewSegment(space.StaticBody, cp.Vector{}, cp.Vector{X: 1}, 0))
+
+	options := Options{
+		MarshalUserData: func(data interface{}) (json.RawMessage, error) {
+			return json.Marshal(data)
+		},
+		UnmarshalUserData: func(data json.RawMessage) (interface{}, error) {
+			var s string
+			err := json.Unmarshal(data, &s)
+			return s, err
+		},
+	}
+	data, err := Marshal(space, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := Unmarshal(data, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rb := bodies(restored)[0]
+	if rb.UserData != "body" {
+		t.Errorf("body UserData is %v, want body", rb.UserData)
+	}
+	rb.EachShape(func(s *cp.Shape) {
+		if s.UserData != "circle" {
+			t.Errorf("shape UserData is %v, want circle", s.UserData)
+		}
+	})
+
+	// without the funcs, user data is dropped
+	restored, err = Unmarshal(data, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ud := bodies(restored)[0].UserData; ud != nil {
+		t.Errorf("body UserData is %v, want nil", ud)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"json", `{`},
+		{"body type", `{"bodies":[{"type":"floating"}]}`},
+		{"shape type", `{"staticShapes":[{"type":"star"}]}`},
+		{"poly", `{"staticShapes":[{"type":"poly","verts":[{"x":0,"y":0}]}]}`},
+		{"body index", `{"constraints":[{"type":"simpleMotor","a":-1,"b":3}]}`},
+		{"anchors", `{"bodies":[{"type":"dynamic","mass":1,"moment":1}],"constraints":[{"type":"pin","a":-1,"b":0}]}`},
+	}
+	for _, tt := range tests {
+		if _, err := Unmarshal([]byte(tt.data), Options{}); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestNumber(t *testing.T) {
+	tests := []float64{0, 1.5, -2, math.Inf(1), math.Inf(-1)}
+	for _, f := range tests {
+		data, err := json.Marshal(number(f))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n number
+		if err := json.Unmarshal(data, &n); err != nil {
+			t.Fatal(err)
+		}
+		if float64(n) != f {
+			t.Errorf("%v came back as %v via %s", f, float64(n), data)
+		}
+	}
+	if _, err := json.Marshal(number(math.NaN())); err == nil {
+		t.Errorf("NaN: no error")
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/demouth/ebitengine-sketch/lib/cpjson
+
+go 1.21.5
+
+require github.com/jakecoffman/cp/v2 v2.1.0
@@ -0,0 +1,136 @@
+// Package drawer draws shapes with DrawTriangles over a white image. The
+// shapes are built by the mesh package.
+package drawer
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/demouth/ebitengine-sketch/lib/drawer/mesh"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+var (
+	whiteSubImage *ebiten.Image
+	// AntiAlias is off by default, as in the sketches the drawer came from.
+	// It costs an extra offscreen pass per draw.
+	AntiAlias bool
+
+	// scratch is reused by every function; drawing happens on one goroutine.
+	scratch  mesh.Mesh
+	vertices []ebiten.Vertex
+)
+
+func init() {
+	whiteSubImage = ebiten.NewImage(3, 3)
+	whiteSubImage.Fill(color.White)
+}
+
+func DrawTriangles(screen *ebiten.Image, vertices []ebiten.Vertex, indices []uint16) {
+	op := &ebiten.DrawTrianglesOptions{}
+	op.FillRule = ebiten.FillRuleFillAll
+	op.AntiAlias = AntiAlias
+	screen.DrawTriangles(vertices, indices, whiteSubImage, op)
+}
+
+// DrawMesh draws m with the colors of its vertices.
+func DrawMesh(screen *ebiten.Image, m *mesh.Mesh) {
+	vertices = vertices[:0]
+	for _, v := range m.Vertices {
+		vertices = append(vertices, ebiten.Vertex{
+			DstX:   v.X,
+			DstY:   v.Y,
+			SrcX:   1,
+			SrcY:   1,
+			ColorR: v.R,
+			ColorG: v.G,
+			ColorB: v.B,
+			ColorA: v.A,
+		})
+	}
+	DrawTriangles(screen, vertices, m.Indices)
+}
+
+func drawScratch(screen *ebiten.Image, c color.Color) {
+	scratch.SetColor(c)
+	DrawMesh(screen, &scratch)
+	scratch.Reset()
+}
+
+func DrawFill(screen *ebiten.Image, path vector.Path, c color.Color) {
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	r, g, b, a := c.RGBA()
+	for i := range vs {
+		vs[i].SrcX = 1
+		vs[i].SrcY = 1
+		vs[i].ColorR = float32(r) / math.MaxUint16
+		vs[i].ColorG = float32(g) / math.MaxUint16
+		vs[i].ColorB = float32(b) / math.MaxUint16
+		vs[i].ColorA = float32(a) / math.MaxUint16
+	}
+	op := &ebiten.DrawTrianglesOptions{}
+	op.FillRule = ebiten.FillRuleFillAll
+	op.AntiAlias = AntiAlias
+	op.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+	screen.DrawTriangles(vs, is, whiteSubImage, op)
+}
+
+// DrawLine draws a line with round caps.
+func DrawLine(screen *ebiten.Image, x1, y1, x2, y2, width float32, c color.Color) {
+	StrokePolyline(screen, []mesh.Point{{X: x1, Y: y1}, {X: x2, Y: y2}}, false, mesh.StrokeOptions{
+		Width: width,
+		Cap:   mesh.CapRound,
+	}, c)
+}
+
+func DrawCircle(screen *ebiten.Image, x, y, radius float32, c color.Color) {
+	scratch.AppendCircle(x, y, radius)
+	drawScratch(screen, c)
+}
+
+func DrawEllipse(screen *ebiten.Image, cx, cy, rx, ry float32, c color.Color) {
+	scratch.AppendEllipse(cx, cy, rx, ry)
+	drawScratch(screen, c)
+}
+
+func DrawRoundedRect(screen *ebiten.Image, x, y, width, height, radius float32, c color.Color) {
+	scratch.AppendRoundedRect(x, y, width, height, radius)
+	drawScratch(screen, c)
+}
+
+// DrawRoundedRectGradient fills a rounded rectangle with g, e.g. a
+// mesh.LinearGradient.
+func DrawRoundedRectGradient(screen *ebiten.Image, x, y, width, height, radius float32, g mesh.Gradient) {
+	scratch.AppendRoundedRect(x, y, width, height, radius)
+	scratch.Fill(g)
+	DrawMesh(screen, &scratch)
+	scratch.Reset()
+}
+
+// DrawCircleGradient fills a circle with g, e.g. a mesh.RadialGradient.
+func DrawCircleGradient(screen *ebiten.Image, x, y, radius float32, g mesh.Gradient) {
+	scratch.AppendCircle(x, y, radius)
+	scratch.Fill(g)
+	DrawMesh(screen, &scratch)
+	scratch.Reset()
+}
+
+func StrokePolyline(screen *ebiten.Image, pts []mesh.Point, closed bool, op mesh.StrokeOptions, c color.Color) {
+	scratch.AppendStroke(pts, closed, op)
+	drawScratch(screen, c)
+}
+
+// StrokeArc draws an arc from the angle start to end, clockwise on the screen.
+func StrokeArc(screen *ebiten.Image, cx, cy, radius, start, end float32, op mesh.StrokeOptions, c color.Color) {
+	scratch.AppendArc(cx, cy, radius, start, end, op)
+	drawScratch(screen, c)
+}
+
+func StrokeEllipse(screen *ebiten.Image, cx, cy, rx, ry float32, op mesh.StrokeOptions, c color.Color) {
+	StrokePolyline(screen, mesh.AppendEllipsePoints(nil, cx, cy, rx, ry), true, op, c)
+}
+
+func StrokeRoundedRect(screen *ebiten.Image, x, y, width, height, radius float32, op mesh.StrokeOptions, c color.Color) {
+	StrokePolyline(screen, mesh.AppendRoundedRectPoints(nil, x, y, width, height, radius), true, op, c)
+}
@@ -0,0 +1,14 @@
+module github.com/demouth/ebitengine-sketch/lib/drawer
+
+go 1.22.6
+
+require github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.6.0.20240911013634-df33bc3e274a
+
+require (
+	github.com/ebitengine/gomobile v0.0.0-20240911013058-19d2b8b92254 // indirect
+	github.com/ebitengine/hideconsole v1.0.0 // indirect
+	github.com/ebitengine/purego v0.8.0-alpha.5 // indirect
+	github.com/jezek/xgb v1.1.1 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.25.0 // indirect
+)
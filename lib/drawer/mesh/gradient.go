@@ -0,0 +1,80 @@
+package mesh
+
+import (
+	"image/color"
+	"math"
+)
+
+// Gradient returns the straight alpha color at a position.
+type Gradient interface {
+	At(x, y float32) (r, g, b, a float32)
+}
+
+// ColorStop is a color at Offset in [0, 1] along a gradient.
+type ColorStop struct {
+	Offset float32
+	Color  color.Color
+}
+
+// LinearGradient goes from (X0, Y0) at offset 0 to (X1, Y1) at offset 1.
+type LinearGradient struct {
+	X0, Y0, X1, Y1 float32
+	Stops          []ColorStop
+}
+
+func (g LinearGradient) At(x, y float32) (r, gr, b, a float32) {
+	d := Point{g.X1 - g.X0, g.Y1 - g.Y0}
+	l := d.dot(d)
+	if l == 0 {
+		return stopsAt(g.Stops, 0)
+	}
+	return stopsAt(g.Stops, Point{x - g.X0, y - g.Y0}.dot(d)/l)
+}
+
+// RadialGradient goes from the center at offset 0 to Radius at offset 1.
+type RadialGradient struct {
+	CX, CY, Radius float32
+	Stops          []ColorStop
+}
+
+func (g RadialGradient) At(x, y float32) (r, gr, b, a float32) {
+	if g.Radius <= 0 {
+		return stopsAt(g.Stops, 1)
+	}
+	return stopsAt(g.Stops, Point{x - g.CX, y - g.CY}.length()/g.Radius)
+}
+
+// stopsAt interpolates the stops, which must be sorted by Offset. Offsets
+// out of the stops take the color of the nearest one.
+func stopsAt(stops []ColorStop, t float32) (r, g, b, a float32) {
+	if len(stops) == 0 {
+		return 0, 0, 0, 0
+	}
+	if t <= stops[0].Offset {
+		return straight(stops[0].Color)
+	}
+	for i := 1; i < len(stops); i++ {
+		s0, s1 := stops[i-1], stops[i]
+		if t > s1.Offset {
+			continue
+		}
+		r0, g0, b0, a0 := straight(s0.Color)
+		r1, g1, b1, a1 := straight(s1.Color)
+		f := float32(0)
+		if s1.Offset > s0.Offset {
+			f = (t - s0.Offset) / (s1.Offset - s0.Offset)
+		}
+		return r0 + (r1-r0)*f, g0 + (g1-g0)*f, b0 + (b1-b0)*f, a0 + (a1-a0)*f
+	}
+	return straight(stops[len(stops)-1].Color)
+}
+
+// straight returns c as straight alpha components in [0, 1]. Straight alpha
+// colors are taken as they are, so that a transparent stop keeps its color.
+func straight(c color.Color) (r, g, b, a float32) {
+	if n, ok := c.(color.NRGBA); ok {
+		return float32(n.R) / 0xff, float32(n.G) / 0xff, float32(n.B) / 0xff, float32(n.A) / 0xff
+	}
+	n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	return float32(n.R) / math.MaxUint16, float32(n.G) / math.MaxUint16, float32(n.B) / math.MaxUint16, float32(n.A) / math.MaxUint16
+}
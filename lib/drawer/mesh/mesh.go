@@ -0,0 +1,243 @@
+// Package mesh builds triangle meshes of 2D shapes. It doesn't depend on
+// ebiten, so the geometry can be tested without a GPU; the drawer package
+// turns a Mesh into ebiten vertices.
+package mesh
+
+import (
+	"image/color"
+	"math"
+)
+
+type Point struct {
+	X, Y float32
+}
+
+func (p Point) add(q Point) Point     { return Point{p.X + q.X, p.Y + q.Y} }
+func (p Point) sub(q Point) Point     { return Point{p.X - q.X, p.Y - q.Y} }
+func (p Point) scale(s float32) Point { return Point{p.X * s, p.Y * s} }
+func (p Point) dot(q Point) float32   { return p.X*q.X + p.Y*q.Y }
+func (p Point) cross(q Point) float32 { return p.X*q.Y - p.Y*q.X }
+func (p Point) length() float32       { return float32(math.Hypot(float64(p.X), float64(p.Y))) }
+func (p Point) near(q Point) bool     { return p.sub(q).length() < 1e-4 }
+func (p Point) lerp(q Point, t float32) Point {
+	return Point{p.X + (q.X-p.X)*t, p.Y + (q.Y-p.Y)*t}
+}
+
+func (p Point) normalize() Point {
+	l := p.length()
+	if l == 0 {
+		return Point{}
+	}
+	return p.scale(1 / l)
+}
+
+// Vertex is a position with a straight alpha color.
+type Vertex struct {
+	X, Y       float32
+	R, G, B, A float32
+}
+
+// Mesh is a list of triangles. Every triangle is clockwise on the screen
+// (the y axis points down), that is its signed area is not negative.
+//
+// Indices are uint16 like ebiten's, so a mesh must stay under 65536
+// vertices; Reset it between shapes when drawing many.
+type Mesh struct {
+	Vertices []Vertex
+	Indices  []uint16
+}
+
+func (m *Mesh) Reset() {
+	m.Vertices = m.Vertices[:0]
+	m.Indices = m.Indices[:0]
+}
+
+// Bounds returns the bounding box of the vertices.
+func (m *Mesh) Bounds() (min, max Point) {
+	if len(m.Vertices) == 0 {
+		return Point{}, Point{}
+	}
+	min = Point{m.Vertices[0].X, m.Vertices[0].Y}
+	max = min
+	for _, v := range m.Vertices[1:] {
+		min.X = float32(math.Min(float64(min.X), float64(v.X)))
+		min.Y = float32(math.Min(float64(min.Y), float64(v.Y)))
+		max.X = float32(math.Max(float64(max.X), float64(v.X)))
+		max.Y = float32(math.Max(float64(max.Y), float64(v.Y)))
+	}
+	return min, max
+}
+
+// SetColor paints every vertex with c.
+func (m *Mesh) SetColor(c color.Color) {
+	r, g, b, a := straight(c)
+	for i := range m.Vertices {
+		v := &m.Vertices[i]
+		v.R, v.G, v.B, v.A = r, g, b, a
+	}
+}
+
+// Fill paints every vertex with the color of g at its position. Colors are
+// interpolated linearly between vertices, so gradients with more than two
+// stops need enough vertices along the gradient.
+func (m *Mesh) Fill(g Gradient) {
+	for i := range m.Vertices {
+		v := &m.Vertices[i]
+		v.R, v.G, v.B, v.A = g.At(v.X, v.Y)
+	}
+}
+
+func (m *Mesh) point(i uint16) Point {
+	v := m.Vertices[i]
+	return Point{v.X, v.Y}
+}
+
+func (m *Mesh) addVertex(p Point) uint16 {
+	m.Vertices = append(m.Vertices, Vertex{X: p.X, Y: p.Y, R: 1, G: 1, B: 1, A: 1})
+	return uint16(len(m.Vertices) - 1)
+}
+
+// addTriangle flips the triangle when needed so that all the triangles have
+// the same winding.
+func (m *Mesh) addTriangle(a, b, c uint16) {
+	pa := m.point(a)
+	if m.point(b).sub(pa).cross(m.point(c).sub(pa)) < 0 {
+		b, c = c, b
+	}
+	m.Indices = append(m.Indices, a, b, c)
+}
+
+// AppendRect adds a filled rectangle as 4 vertices and 2 triangles.
+func (m *Mesh) AppendRect(x, y, width, height float32) {
+	i0 := m.addVertex(Point{x, y})
+	i1 := m.addVertex(Point{x + width, y})
+	i2 := m.addVertex(Point{x + width, y + height})
+	i3 := m.addVertex(Point{x, y + height})
+	m.addTriangle(i0, i1, i2)
+	m.addTriangle(i0, i2, i3)
+}
+
+// AppendConvexPolygon adds a filled convex polygon as a fan around its
+// center: len(pts)+1 vertices and len(pts) triangles.
+func (m *Mesh) AppendConvexPolygon(pts []Point) {
+	if len(pts) < 3 {
+		return
+	}
+	var c Point
+	for _, p := range pts {
+		c = c.add(p)
+	}
+	ci := m.addVertex(c.scale(1 / float32(len(pts))))
+	first := m.addVertex(pts[0])
+	prev := first
+	for _, p := range pts[1:] {
+		i := m.addVertex(p)
+		m.addTriangle(ci, prev, i)
+		prev = i
+	}
+	m.addTriangle(ci, prev, first)
+}
+
+func (m *Mesh) AppendCircle(cx, cy, radius float32) {
+	m.AppendEllipse(cx, cy, radius, radius)
+}
+
+func (m *Mesh) AppendEllipse(cx, cy, rx, ry float32) {
+	m.AppendConvexPolygon(AppendEllipsePoints(nil, cx, cy, rx, ry))
+}
+
+func (m *Mesh) AppendRoundedRect(x, y, width, height, radius float32) {
+	m.AppendConvexPolygon(AppendRoundedRectPoints(nil, x, y, width, height, radius))
+}
+
+// appendFan adds a circular sector around center from the angle start
+// sweeping by sweep radians.
+func (m *Mesh) appendFan(center Point, radius, start, sweep float32) {
+	n := Segments(radius, sweep)
+	ci := m.addVertex(center)
+	prev := m.addVertex(polar(center, radius, radius, start))
+	for i := 1; i <= n; i++ {
+		a := start + sweep*float32(i)/float32(n)
+		v := m.addVertex(polar(center, radius, radius, a))
+		m.addTriangle(ci, prev, v)
+		prev = v
+	}
+}
+
+// Tolerance is the largest distance in pixels between a curve and the
+// segments approximating it.
+const Tolerance = 0.25
+
+// Segments returns how many segments approximate an arc of radius sweeping
+// by sweep radians within Tolerance. The number is even, so the middle of
+// the arc is always a vertex.
+func Segments(radius, sweep float32) int {
+	s := math.Abs(float64(sweep))
+	var n int
+	if radius <= Tolerance {
+		n = int(math.Ceil(s / (math.Pi / 2)))
+	} else {
+		n = int(math.Ceil(s / (2 * math.Acos(1-Tolerance/float64(radius)))))
+	}
+	return max(2, (n+1)/2*2)
+}
+
+func polar(c Point, rx, ry, angle float32) Point {
+	s, co := math.Sincos(float64(angle))
+	return Point{c.X + rx*float32(co), c.Y + ry*float32(s)}
+}
+
+// appendPoint skips p when it is the same as the last point.
+func appendPoint(pts []Point, p Point) []Point {
+	if len(pts) > 0 && pts[len(pts)-1].near(p) {
+		return pts
+	}
+	return append(pts, p)
+}
+
+// AppendArcPoints appends the points of an elliptic arc from the angle start
+// to end, both ends included. Angles are clockwise on the screen.
+func AppendArcPoints(pts []Point, cx, cy, rx, ry, start, end float32) []Point {
+	n := Segments(max(rx, ry), end-start)
+	c := Point{cx, cy}
+	for i := 0; i <= n; i++ {
+		pts = appendPoint(pts, polar(c, rx, ry, start+(end-start)*float32(i)/float32(n)))
+	}
+	return pts
+}
+
+// AppendEllipsePoints appends the outline of an ellipse without repeating
+// the first point at the end. The number of points is a multiple of 4, so
+// the ends of both axes are on the outline.
+func AppendEllipsePoints(pts []Point, cx, cy, rx, ry float32) []Point {
+	n := (Segments(max(rx, ry), 2*math.Pi) + 3) / 4 * 4
+	c := Point{cx, cy}
+	for i := 0; i < n; i++ {
+		pts = append(pts, polar(c, rx, ry, 2*math.Pi*float32(i)/float32(n)))
+	}
+	return pts
+}
+
+// AppendRoundedRectPoints appends the outline of a rounded rectangle
+// clockwise from the top edge. The radius is clamped to half the shorter
+// side.
+func AppendRoundedRectPoints(pts []Point, x, y, width, height, radius float32) []Point {
+	radius = min(radius, width/2, height/2)
+	if radius <= 0 {
+		return append(pts,
+			Point{x, y},
+			Point{x + width, y},
+			Point{x + width, y + height},
+			Point{x, y + height},
+		)
+	}
+	start := len(pts)
+	pts = AppendArcPoints(pts, x+width-radius, y+radius, radius, radius, -math.Pi/2, 0)
+	pts = AppendArcPoints(pts, x+width-radius, y+height-radius, radius, radius, 0, math.Pi/2)
+	pts = AppendArcPoints(pts, x+radius, y+height-radius, radius, radius, math.Pi/2, math.Pi)
+	pts = AppendArcPoints(pts, x+radius, y+radius, radius, radius, math.Pi, 3*math.Pi/2)
+	if len(pts)-start > 1 && pts[len(pts)-1].near(pts[start]) {
+		pts = pts[:len(pts)-1]
+	}
+	return pts
+}
@@ -0,0 +1,400 @@
+package mesh
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+const epsilon = 1e-3
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < epsilon
+}
+
+// checkMesh checks that the indices are in range and every triangle is
+// clockwise on the screen.
+func checkMesh(t *testing.T, m *Mesh) {
+	t.Helper()
+	if len(m.Indices)%3 != 0 {
+		t.Fatalf("len(Indices) = %d, not a multiple of 3", len(m.Indices))
+	}
+	for i := 0; i < len(m.Indices); i += 3 {
+		for _, idx := range m.Indices[i : i+3] {
+			if int(idx) >= len(m.Vertices) {
+				t.Fatalf("index %d out of range (%d vertices)", idx, len(m.Vertices))
+			}
+		}
+		a, b, c := m.point(m.Indices[i]), m.point(m.Indices[i+1]), m.point(m.Indices[i+2])
+		if area := b.sub(a).cross(c.sub(a)); area < 0 {
+			t.Errorf("triangle %d (%v %v %v) has area %v, want >= 0", i/3, a, b, c, area)
+		}
+	}
+}
+
+func checkBounds(t *testing.T, m *Mesh, wantMin, wantMax Point) {
+	t.Helper()
+	min, max := m.Bounds()
+	if !near(min.X, wantMin.X) || !near(min.Y, wantMin.Y) || !near(max.X, wantMax.X) || !near(max.Y, wantMax.Y) {
+		t.Errorf("Bounds() = %v, %v, want %v, %v", min, max, wantMin, wantMax)
+	}
+}
+
+func TestFills(t *testing.T) {
+	tests := []struct {
+		name      string
+		append    func(m *Mesh)
+		vertices  int
+		triangles int
+		min, max  Point
+	}{
+		{
+			name:      "rect",
+			append:    func(m *Mesh) { m.AppendRect(10, 20, 30, 40) },
+			vertices:  4,
+			triangles: 2,
+			min:       Point{10, 20},
+			max:       Point{40, 60},
+		},
+		{
+			name:      "square rounded rect",
+			append:    func(m *Mesh) { m.AppendRoundedRect(0, 0, 100, 50, 0) },
+			vertices:  5,
+			triangles: 4,
+			min:       Point{0, 0},
+			max:       Point{100, 50},
+		},
+		{
+			name:      "rounded rect",
+			append:    func(m *Mesh) { m.AppendRoundedRect(0, 0, 100, 50, 10) },
+			vertices:  4*(Segments(10, math.Pi/2)+1) + 1,
+			triangles: 4 * (Segments(10, math.Pi/2) + 1),
+			min:       Point{0, 0},
+			max:       Point{100, 50},
+		},
+		{
+			// the radius is clamped and the straight edges vanish
+			name:      "round rounded rect",
+			append:    func(m *Mesh) { m.AppendRoundedRect(0, 0, 20, 20, 50) },
+			vertices:  4*Segments(10, math.Pi/2) + 1,
+			triangles: 4 * Segments(10, math.Pi/2),
+			min:       Point{0, 0},
+			max:       Point{20, 20},
+		},
+		{
+			name:      "circle",
+			append:    func(m *Mesh) { m.AppendCircle(50, 50, 20) },
+			vertices:  (Segments(20, 2*math.Pi)+3)/4*4 + 1,
+			triangles: (Segments(20, 2*math.Pi) + 3) / 4 * 4,
+			min:       Point{30, 30},
+			max:       Point{70, 70},
+		},
+		{
+			name:      "ellipse",
+			append:    func(m *Mesh) { m.AppendEllipse(0, 0, 40, 10) },
+			vertices:  (Segments(40, 2*math.Pi)+3)/4*4 + 1,
+			triangles: (Segments(40, 2*math.Pi) + 3) / 4 * 4,
+			min:       Point{-40, -10},
+			max:       Point{40, 10},
+		},
+		{
+			name: "counter clockwise polygon",
+			append: func(m *Mesh) {
+				m.AppendConvexPolygon([]Point{{0, 0}, {0, 10}, {10, 10}, {10, 0}})
+			},
+			vertices:  5,
+			triangles: 4,
+			min:       Point{0, 0},
+			max:       Point{10, 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Mesh
+			tt.append(&m)
+			checkMesh(t, &m)
+			if len(m.Vertices) != tt.vertices || len(m.Indices) != 3*tt.triangles {
+				t.Errorf("got %d vertices and %d triangles, want %d and %d", len(m.Vertices), len(m.Indices)/3, tt.vertices, tt.triangles)
+			}
+			checkBounds(t, &m, tt.min, tt.max)
+		})
+	}
+}
+
+func TestSegments(t *testing.T) {
+	// a larger circle needs more segments
+	if s, l := Segments(10, 2*math.Pi), Segments(100, 2*math.Pi); s >= l {
+		t.Errorf("Segments(10) = %d, Segments(100) = %d", s, l)
+	}
+	if got := Segments(100, 0); got != 2 {
+		t.Errorf("Segments(100, 0) = %d, want 2", got)
+	}
+	// the distance between the chord and the arc is within the tolerance
+	r := float32(100)
+	n := Segments(r, 2*math.Pi)
+	if sagitta := r * (1 - float32(math.Cos(math.Pi/float64(n)))); sagitta > Tolerance {
+		t.Errorf("sagitta = %v, want <= %v", sagitta, Tolerance)
+	}
+}
+
+func TestStrokeCaps(t *testing.T) {
+	pts := []Point{{0, 0}, {100, 0}}
+	fan := Segments(5, math.Pi) + 2
+	tests := []struct {
+		cap      LineCap
+		vertices int
+		min, max Point
+	}{
+		{CapButt, 4, Point{0, -5}, Point{100, 5}},
+		{CapSquare, 4, Point{-5, -5}, Point{105, 5}},
+		{CapRound, 4 + 2*fan, Point{-5, -5}, Point{105, 5}},
+	}
+	for _, tt := range tests {
+		var m Mesh
+		m.AppendStroke(pts, false, StrokeOptions{Width: 10, Cap: tt.cap})
+		checkMesh(t, &m)
+		if len(m.Vertices) != tt.vertices {
+			t.Errorf("cap %d: got %d vertices, want %d", tt.cap, len(m.Vertices), tt.vertices)
+		}
+		checkBounds(t, &m, tt.min, tt.max)
+	}
+}
+
+func TestStrokeJoins(t *testing.T) {
+	// a right angle turning down on the screen
+	pts := []Point{{0, 0}, {100, 0}, {100, 100}}
+	tests := []struct {
+		name     string
+		op       StrokeOptions
+		vertices int
+		max      Point
+	}{
+		{"bevel", StrokeOptions{Width: 10, Join: JoinBevel}, 8 + 3, Point{105, 100}},
+		{"miter", StrokeOptions{Width: 10, Join: JoinMiter}, 8 + 4, Point{105, 100}},
+		{"round", StrokeOptions{Width: 10, Join: JoinRound}, 8 + Segments(5, math.Pi/2) + 2, Point{105, 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Mesh
+			m.AppendStroke(pts, false, tt.op)
+			checkMesh(t, &m)
+			if len(m.Vertices) != tt.vertices {
+				t.Errorf("got %d vertices, want %d", len(m.Vertices), tt.vertices)
+			}
+			checkBounds(t, &m, Point{0, -5}, tt.max)
+		})
+	}
+
+	// the miter reaches the outer corner
+	var m Mesh
+	m.AppendStroke(pts, false, StrokeOptions{Width: 10, Join: JoinMiter})
+	found := false
+	for _, v := range m.Vertices {
+		if near(v.X, 105) && near(v.Y, -5) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("no miter vertex at (105, -5)")
+	}
+
+	// a sharp corner over the miter limit is beveled
+	m.Reset()
+	m.AppendStroke([]Point{{0, 0}, {100, 0}, {0, 10}}, false, StrokeOptions{Width: 10, Join: JoinMiter})
+	checkMesh(t, &m)
+	if len(m.Vertices) != 8+3 {
+		t.Errorf("sharp miter: got %d vertices, want a bevel (%d)", len(m.Vertices), 8+3)
+	}
+
+	// a straight joint adds nothing
+	m.Reset()
+	m.AppendStroke([]Point{{0, 0}, {50, 0}, {100, 0}}, false, StrokeOptions{Width: 10, Join: JoinRound})
+	if len(m.Vertices) != 8 {
+		t.Errorf("straight: got %d vertices, want 8", len(m.Vertices))
+	}
+}
+
+func TestStrokeClosed(t *testing.T) {
+	square := []Point{{0, 0}, {100, 0}, {100, 100}, {0, 100}}
+	var m Mesh
+	m.AppendStroke(square, true, StrokeOptions{Width: 10, Join: JoinMiter, Cap: CapRound})
+	checkMesh(t, &m)
+	// 4 segments and 4 miters, no caps
+	if len(m.Vertices) != 4*4+4*4 {
+		t.Errorf("got %d vertices, want %d", len(m.Vertices), 4*4+4*4)
+	}
+	checkBounds(t, &m, Point{-5, -5}, Point{105, 105})
+}
+
+func TestStrokeDegenerate(t *testing.T) {
+	var m Mesh
+	m.AppendStroke([]Point{{10, 10}, {10, 10}}, false, StrokeOptions{Width: 4, Cap: CapButt})
+	if len(m.Vertices) != 0 {
+		t.Errorf("butt dot: got %d vertices, want 0", len(m.Vertices))
+	}
+	m.AppendStroke([]Point{{10, 10}, {10, 10}}, false, StrokeOptions{Width: 4, Cap: CapSquare})
+	checkBounds(t, &m, Point{8, 8}, Point{12, 12})
+	m.Reset()
+	m.AppendStroke([]Point{{0, 0}, {10, 0}}, false, StrokeOptions{})
+	if len(m.Vertices) != 0 {
+		t.Errorf("zero width: got %d vertices, want 0", len(m.Vertices))
+	}
+}
+
+func TestDash(t *testing.T) {
+	line := []Point{{0, 0}, {100, 0}}
+	tests := []struct {
+		name    string
+		pts     []Point
+		closed  bool
+		pattern []float32
+		offset  float32
+		want    [][2]float32 // the X of the ends of every dash on the line
+	}{
+		{
+			name:    "simple",
+			pts:     line,
+			pattern: []float32{20, 10},
+			want:    [][2]float32{{0, 20}, {30, 50}, {60, 80}, {90, 100}},
+		},
+		{
+			name:    "offset",
+			pts:     line,
+			pattern: []float32{20, 10},
+			offset:  25,
+			want:    [][2]float32{{5, 25}, {35, 55}, {65, 85}, {95, 100}},
+		},
+		{
+			name:    "negative offset",
+			pts:     line,
+			pattern: []float32{20, 10},
+			offset:  -10,
+			want:    [][2]float32{{10, 30}, {40, 60}, {70, 90}},
+		},
+		{
+			name:    "odd pattern",
+			pts:     line,
+			pattern: []float32{30},
+			want:    [][2]float32{{0, 30}, {60, 90}},
+		},
+		{
+			name:    "dots",
+			pts:     []Point{{0, 0}, {30, 0}},
+			pattern: []float32{0, 10},
+			want:    [][2]float32{{0, 0}, {10, 10}, {20, 20}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Dash(tt.pts, tt.closed, tt.pattern, tt.offset)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d dashes %v, want %d", len(got), got, len(tt.want))
+			}
+			for i, d := range got {
+				first, last := d[0], d[len(d)-1]
+				if !near(first.X, tt.want[i][0]) || !near(last.X, tt.want[i][1]) {
+					t.Errorf("dash %d = %v, want x from %v to %v", i, d, tt.want[i][0], tt.want[i][1])
+				}
+			}
+		})
+	}
+
+	// a dash goes around corners
+	got := Dash([]Point{{0, 0}, {10, 0}, {10, 10}}, false, []float32{15, 100}, 0)
+	if len(got) != 1 || len(got[0]) != 3 || !near(got[0][2].Y, 5) {
+		t.Errorf("Dash around a corner = %v", got)
+	}
+
+	// closed paths come back to the first point
+	got = Dash([]Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, true, []float32{100}, 0)
+	if len(got) != 1 || len(got[0]) != 5 {
+		t.Errorf("Dash of a closed square = %v", got)
+	}
+
+	if got := Dash(line, false, []float32{0, 0}, 0); got != nil {
+		t.Errorf("Dash with an empty pattern = %v, want nil", got)
+	}
+}
+
+func TestStrokeDashes(t *testing.T) {
+	var m Mesh
+	m.AppendStroke([]Point{{0, 0}, {100, 0}}, false, StrokeOptions{Width: 2, Dashes: []float32{20, 10}})
+	checkMesh(t, &m)
+	if len(m.Vertices) != 4*4 {
+		t.Errorf("got %d vertices, want 4 quads", len(m.Vertices))
+	}
+}
+
+func TestArc(t *testing.T) {
+	pts := AppendArcPoints(nil, 0, 0, 10, 10, 0, math.Pi/2)
+	if n := Segments(10, math.Pi/2) + 1; len(pts) != n {
+		t.Fatalf("got %d points, want %d", len(pts), n)
+	}
+	first, last := pts[0], pts[len(pts)-1]
+	// angles are clockwise on the screen: from the right to the bottom
+	if !near(first.X, 10) || !near(first.Y, 0) || !near(last.X, 0) || !near(last.Y, 10) {
+		t.Errorf("arc from %v to %v", first, last)
+	}
+
+	var m Mesh
+	m.AppendArc(0, 0, 10, 0, math.Pi/2, StrokeOptions{Width: 2, Join: JoinBevel})
+	checkMesh(t, &m)
+	if min, max := m.Bounds(); min.X < -1 || min.Y < -1 || max.X > 11 || max.Y > 11 || max.X < 10.5 || max.Y < 10.5 {
+		t.Errorf("Bounds() = %v, %v, want within the stroke of the arc", min, max)
+	}
+}
+
+func TestGradients(t *testing.T) {
+	stops := []ColorStop{
+		{0, color.NRGBA{0xff, 0, 0, 0xff}},
+		{0.5, color.NRGBA{0, 0xff, 0, 0xff}},
+		{1, color.NRGBA{0, 0, 0xff, 0x00}},
+	}
+	type rgba [4]float32
+	tests := []struct {
+		name string
+		g    Gradient
+		x, y float32
+		want rgba
+	}{
+		{"linear start", LinearGradient{0, 0, 100, 0, stops}, 0, 50, rgba{1, 0, 0, 1}},
+		{"linear before", LinearGradient{0, 0, 100, 0, stops}, -10, 0, rgba{1, 0, 0, 1}},
+		{"linear middle", LinearGradient{0, 0, 100, 0, stops}, 25, 0, rgba{0.5, 0.5, 0, 1}},
+		{"linear after", LinearGradient{0, 0, 100, 0, stops}, 200, 0, rgba{0, 0, 1, 0}},
+		{"linear vertical", LinearGradient{0, 0, 0, 100, stops}, 30, 50, rgba{0, 1, 0, 1}},
+		{"radial center", RadialGradient{10, 10, 20, stops}, 10, 10, rgba{1, 0, 0, 1}},
+		{"radial edge", RadialGradient{10, 10, 20, stops}, 10, 30, rgba{0, 0, 1, 0}},
+		{"radial", RadialGradient{10, 10, 20, stops}, 25, 10, rgba{0, 0.5, 0.5, 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, g, b, a := tt.g.At(tt.x, tt.y)
+			got := rgba{r, g, b, a}
+			for i := range got {
+				if !near(got[i], tt.want[i]) {
+					t.Errorf("At(%v, %v) = %v, want %v", tt.x, tt.y, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	// premultiplied colors are converted to straight alpha
+	r, _, _, a := LinearGradient{Stops: []ColorStop{{0, color.RGBA{0x80, 0, 0, 0x80}}}}.At(0, 0)
+	if !near(r, 1) || !near(a, float32(0x80)/0xff) {
+		t.Errorf("premultiplied color = %v, %v", r, a)
+	}
+
+	var m Mesh
+	m.AppendRect(0, 0, 100, 10)
+	m.Fill(LinearGradient{0, 0, 100, 0, stops})
+	if v := m.Vertices[1]; !near(v.B, 1) || !near(v.A, 0) {
+		t.Errorf("Fill: vertex at the end = %+v", v)
+	}
+	m.SetColor(color.White)
+	for _, v := range m.Vertices {
+		if v.R != 1 || v.A != 1 {
+			t.Errorf("SetColor: vertex = %+v", v)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package mesh
+
+import "math"
+
+type LineJoin int
+
+const (
+	JoinMiter LineJoin = iota
+	JoinBevel
+	JoinRound
+)
+
+type LineCap int
+
+const (
+	CapButt LineCap = iota
+	CapRound
+	CapSquare
+)
+
+// DefaultMiterLimit is used when StrokeOptions.MiterLimit is 0.
+const DefaultMiterLimit = 4
+
+type StrokeOptions struct {
+	Width float32
+	Join  LineJoin
+	Cap   LineCap
+
+	// MiterLimit is the largest ratio of the miter length to Width. Sharper
+	// corners are beveled. This is the same as SVG's stroke-miterlimit.
+	MiterLimit float32
+
+	// Dashes are the lengths of the alternating dashes and gaps. An odd
+	// number of lengths is repeated twice. Each dash gets the caps.
+	Dashes     []float32
+	DashOffset float32
+}
+
+// AppendStroke adds the stroke of a polyline. When closed is true the last
+// point is joined to the first one.
+//
+// The segments, joins and caps are separate triangles that overlap, so a
+// translucent stroke should be drawn opaque to an offscreen image first.
+func (m *Mesh) AppendStroke(pts []Point, closed bool, op StrokeOptions) {
+	if patternLength(op.Dashes) > 0 {
+		for _, d := range Dash(pts, closed, op.Dashes, op.DashOffset) {
+			m.appendStroke(d, false, op)
+		}
+		return
+	}
+	m.appendStroke(pts, closed, op)
+}
+
+// AppendArc adds the stroke of a circular arc from the angle start to end.
+func (m *Mesh) AppendArc(cx, cy, radius, start, end float32, op StrokeOptions) {
+	m.AppendStroke(AppendArcPoints(nil, cx, cy, radius, radius, start, end), false, op)
+}
+
+func (m *Mesh) appendStroke(pts []Point, closed bool, op StrokeOptions) {
+	hw := op.Width / 2
+	if hw <= 0 || len(pts) == 0 {
+		return
+	}
+	var ps []Point
+	for _, p := range pts {
+		ps = appendPoint(ps, p)
+	}
+	if closed && len(ps) > 2 && ps[len(ps)-1].near(ps[0]) {
+		ps = ps[:len(ps)-1]
+	}
+	if len(ps) < 3 {
+		closed = false
+	}
+
+	if len(ps) == 1 {
+		// a zero length line is a dot when it has caps
+		switch op.Cap {
+		case CapRound:
+			m.AppendCircle(ps[0].X, ps[0].Y, hw)
+		case CapSquare:
+			m.AppendRect(ps[0].X-hw, ps[0].Y-hw, op.Width, op.Width)
+		}
+		return
+	}
+
+	n := len(ps)
+	segs := n - 1
+	if closed {
+		segs = n
+	}
+	for i := 0; i < segs; i++ {
+		a, b := ps[i], ps[(i+1)%n]
+		d := b.sub(a).normalize()
+		if !closed && op.Cap == CapSquare {
+			if i == 0 {
+				a = a.sub(d.scale(hw))
+			}
+			if i == segs-1 {
+				b = b.add(d.scale(hw))
+			}
+		}
+		nrm := Point{-d.Y, d.X}.scale(hw)
+		i0 := m.addVertex(a.add(nrm))
+		i1 := m.addVertex(b.add(nrm))
+		i2 := m.addVertex(b.sub(nrm))
+		i3 := m.addVertex(a.sub(nrm))
+		m.addTriangle(i0, i1, i2)
+		m.addTriangle(i0, i2, i3)
+	}
+
+	if closed {
+		for i := range ps {
+			m.appendJoin(ps[(i+n-1)%n], ps[i], ps[(i+1)%n], hw, op)
+		}
+		return
+	}
+	for i := 1; i < n-1; i++ {
+		m.appendJoin(ps[i-1], ps[i], ps[i+1], hw, op)
+	}
+	if op.Cap == CapRound {
+		d := ps[1].sub(ps[0])
+		m.appendFan(ps[0], hw, angle(Point{-d.Y, d.X}), math.Pi)
+		d = ps[n-1].sub(ps[n-2])
+		m.appendFan(ps[n-1], hw, angle(Point{d.Y, -d.X}), math.Pi)
+	}
+}
+
+func angle(p Point) float32 {
+	return float32(math.Atan2(float64(p.Y), float64(p.X)))
+}
+
+// appendJoin fills the gap on the outer side of the corner at p.
+func (m *Mesh) appendJoin(prev, p, next Point, hw float32, op StrokeOptions) {
+	d0 := p.sub(prev).normalize()
+	d1 := next.sub(p).normalize()
+	c := d0.cross(d1)
+	if math.Abs(float64(c)) < 1e-6 && d0.dot(d1) > 0 {
+		// straight
+		return
+	}
+	// the outer side is the opposite of the turn
+	s := hw
+	if c > 0 {
+		s = -hw
+	}
+	u := Point{-d0.Y, d0.X}.scale(s)
+	v := Point{-d1.Y, d1.X}.scale(s)
+
+	switch op.Join {
+	case JoinRound:
+		sweep := float32(math.Atan2(float64(u.cross(v)), float64(u.dot(v))))
+		m.appendFan(p, hw, angle(u), sweep)
+		return
+	case JoinMiter:
+		limit := op.MiterLimit
+		if limit == 0 {
+			limit = DefaultMiterLimit
+		}
+		bisector := u.add(v).normalize()
+		// cos of half the angle between u and v; the miter length is
+		// the width divided by it.
+		cosHalf := bisector.dot(u) / hw
+		if cosHalf > 1e-6 && 1/cosHalf <= limit {
+			pi := m.addVertex(p)
+			ai := m.addVertex(p.add(u))
+			mi := m.addVertex(p.add(bisector.scale(hw / cosHalf)))
+			bi := m.addVertex(p.add(v))
+			m.addTriangle(pi, ai, mi)
+			m.addTriangle(pi, mi, bi)
+			return
+		}
+	}
+	pi := m.addVertex(p)
+	ai := m.addVertex(p.add(u))
+	bi := m.addVertex(p.add(v))
+	m.addTriangle(pi, ai, bi)
+}
+
+func patternLength(pattern []float32) float32 {
+	var l float32
+	for _, p := range pattern {
+		if p < 0 {
+			return 0
+		}
+		l += p
+	}
+	return l
+}
+
+// Dash splits a polyline into the dashes of pattern starting offset along
+// the line. A pattern without a positive length returns nil.
+func Dash(pts []Point, closed bool, pattern []float32, offset float32) [][]Point {
+	total := patternLength(pattern)
+	if total <= 0 || len(pts) == 0 {
+		return nil
+	}
+	if len(pattern)%2 == 1 {
+		pattern = append(pattern[:len(pattern):len(pattern)], pattern...)
+		total *= 2
+	}
+	if closed && len(pts) > 1 {
+		pts = append(pts[:len(pts):len(pts)], pts[0])
+	}
+
+	offset = float32(math.Mod(float64(offset), float64(total)))
+	if offset < 0 {
+		offset += total
+	}
+	i := 0
+	// a dash of zero length at the offset is kept so that dots are drawn
+	for pattern[i] > 0 && offset >= pattern[i] || pattern[i] == 0 && offset > 0 {
+		offset -= pattern[i]
+		i = (i + 1) % len(pattern)
+	}
+	remain := pattern[i] - offset
+	on := i%2 == 0
+
+	var dashes [][]Point
+	var cur []Point
+	if on {
+		cur = []Point{pts[0]}
+	}
+	for s := 0; s+1 < len(pts); s++ {
+		a, b := pts[s], pts[s+1]
+		l := b.sub(a).length()
+		var pos float32
+		for l-pos > remain {
+			pos += remain
+			p := a.lerp(b, pos/l)
+			if on {
+				dashes = append(dashes, append(cur, p))
+				cur = nil
+			} else {
+				cur = []Point{p}
+			}
+			on = !on
+			i = (i + 1) % len(pattern)
+			remain = pattern[i]
+		}
+		remain -= l - pos
+		if on {
+			cur = append(cur, b)
+		}
+	}
+	if on && len(cur) > 1 {
+		dashes = append(dashes, cur)
+	}
+	return dashes
+}
@@ -0,0 +1,135 @@
+package ebitencp
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// batching: every shape appends its triangles to one vertex buffer, in the
+// order it is drawn, and Flush draws them all with a single DrawTriangles.
+// Fills and strokes share the buffer, so shapes still cover each other in
+// the order Chipmunk2D draws them.
+
+// maxVertices is how many vertices one DrawTriangles call can address with
+// uint16 indices.
+const maxVertices = math.MaxUint16
+
+type point struct {
+	X, Y float32
+}
+
+// toScreen moves a point of the space to the screen, through the camera.
+func (d *Drawer) toScreen(v cp.Vector) point {
+	x, y := d.WorldToScreen(v)
+	return point{X: float32(x), Y: float32(y)}
+}
+
+// reserve makes room for n more vertices, flushing when they wouldn't fit
+// in the current batch.
+func (d *Drawer) reserve(n int) {
+	if len(d.vertices)+n > maxVertices {
+		d.Flush()
+	}
+}
+
+func (d *Drawer) addVertex(p point, c cp.FColor) {
+	d.vertices = append(d.vertices, ebiten.Vertex{
+		DstX:   p.X,
+		DstY:   p.Y,
+		SrcX:   1,
+		SrcY:   1,
+		ColorR: c.R,
+		ColorG: c.G,
+		ColorB: c.B,
+		ColorA: c.A,
+	})
+}
+
+// fillConvex adds a convex polygon as a fan around its first point.
+func (d *Drawer) fillConvex(pts []point, c cp.FColor) {
+	if len(pts) < 3 {
+		return
+	}
+	d.reserve(len(pts))
+	base := uint16(len(d.vertices))
+	for _, p := range pts {
+		d.addVertex(p, c)
+	}
+	for i := 1; i < len(pts)-1; i++ {
+		d.indices = append(d.indices, base, base+uint16(i), base+uint16(i+1))
+	}
+}
+
+// strokeLine adds the line from a to b as a quad of StrokeWidth. Its ends
+// stick out by half the width, which fills the joints of a polyline.
+func (d *Drawer) strokeLine(a, b point, c cp.FColor) {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	l := float32(math.Hypot(float64(dx), float64(dy)))
+	if l == 0 {
+		return
+	}
+	h := d.StrokeWidth / 2
+	dx, dy = dx/l*h, dy/l*h
+	// nx, ny is normal to the line
+	nx, ny := -dy, dx
+
+	d.reserve(4)
+	base := uint16(len(d.vertices))
+	d.addVertex(point{a.X - dx + nx, a.Y - dy + ny}, c)
+	d.addVertex(point{b.X + dx + nx, b.Y + dy + ny}, c)
+	d.addVertex(point{b.X + dx - nx, b.Y + dy - ny}, c)
+	d.addVertex(point{a.X - dx - nx, a.Y - dy - ny}, c)
+	d.indices = append(d.indices, base, base+1, base+2, base, base+2, base+3)
+}
+
+// strokeLoop adds the outline of a closed polygon.
+func (d *Drawer) strokeLoop(pts []point, c cp.FColor) {
+	for i := range pts {
+		d.strokeLine(pts[i], pts[(i+1)%len(pts)], c)
+	}
+}
+
+// arc appends to dst the points of an arc of the space around center,
+// from angle a0 to a1, on the screen.
+func (d *Drawer) arc(dst []point, center cp.Vector, radius, a0, a1 float64) []point {
+	n := arcSegments(radius * d.Camera.zoom() * math.Abs(a1-a0))
+	for i := 0; i <= n; i++ {
+		a := a0 + (a1-a0)*float64(i)/float64(n)
+		dst = append(dst, d.toScreen(center.Add(cp.Vector{X: math.Cos(a), Y: math.Sin(a)}.Mult(radius))))
+	}
+	return dst
+}
+
+// arcSegments returns how many segments an arc of the given length is cut
+// into: about one every 4 pixels, at least 4 and at most 64.
+func arcSegments(length float64) int {
+	n := int(math.Ceil(length / 4))
+	return max(4, min(n, 64))
+}
+
+// Flush draws what has been added since the last Flush onto Screen, in one
+// DrawTriangles call. DrawSpace flushes by itself; call Flush after drawing
+// shapes with cp.DrawShape or the Draw methods directly.
+func (d *Drawer) Flush() {
+	if len(d.indices) == 0 {
+		d.vertices = d.vertices[:0]
+		return
+	}
+	if d.Screen != nil {
+		op := &ebiten.DrawTrianglesOptions{}
+		op.FillRule = ebiten.FillAll
+		op.AntiAlias = d.AntiAlias
+		d.Screen.DrawTriangles(d.vertices, d.indices, d.whiteImage, op)
+	}
+	d.calls++
+	d.vertices = d.vertices[:0]
+	d.indices = d.indices[:0]
+}
+
+// DrawSpace draws space onto Screen.
+func (d *Drawer) DrawSpace(space *cp.Space) {
+	cp.DrawSpace(space, d)
+	d.Flush()
+}
@@ -0,0 +1,158 @@
+package ebitencp
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Camera is where the drawer looks at the space from.
+type Camera struct {
+	// Position is the point of the space at the centre of the screen.
+	Position cp.Vector
+	// Zoom is how many pixels a unit of the space takes.
+	Zoom float64
+	// Rotation turns the view counter-clockwise, in radians, or clockwise
+	// with FlipYAxis.
+	Rotation float64
+
+	// Target, when set, is the body HandleCamera follows.
+	Target *cp.Body
+	// Follow is how much of the way to Target the camera goes every
+	// frame, from 0 to 1.
+	Follow float64
+}
+
+const (
+	MinZoom = 0.1
+	MaxZoom = 10
+	// wheelZoom is how much one notch of the wheel zooms.
+	wheelZoom = 1.1
+)
+
+// ScreenToWorld returns the point of the space at (x, y) on the screen.
+func (d *Drawer) ScreenToWorld(x, y float64) cp.Vector {
+	c := &d.Camera
+	v := cp.Vector{
+		X: x - float64(d.ScreenWidth)/2,
+		Y: d.yAxis() * (y - float64(d.ScreenHeight)/2),
+	}
+	return v.Mult(1 / c.zoom()).Rotate(cp.ForAngle(c.Rotation)).Add(c.Position)
+}
+
+// WorldToScreen returns where v of the space is on the screen.
+func (d *Drawer) WorldToScreen(v cp.Vector) (x, y float64) {
+	c := &d.Camera
+	v = v.Sub(c.Position).Unrotate(cp.ForAngle(c.Rotation)).Mult(c.zoom())
+	return v.X + float64(d.ScreenWidth)/2, d.yAxis()*v.Y + float64(d.ScreenHeight)/2
+}
+
+// yAxis is which way the y axis of the space points on the screen: -1 up,
+// or 1 down with FlipYAxis.
+func (d *Drawer) yAxis() float64 {
+	if d.FlipYAxis {
+		return 1
+	}
+	return -1
+}
+
+func (c *Camera) zoom() float64 {
+	if c.Zoom == 0 {
+		return 1
+	}
+	return c.Zoom
+}
+
+// ZoomAt zooms by factor, keeping the point of the space at (x, y) on the
+// screen where it is.
+func (d *Drawer) ZoomAt(x, y, factor float64) {
+	c := &d.Camera
+	before := d.ScreenToWorld(x, y)
+	c.Zoom = cp.Clamp(c.zoom()*factor, MinZoom, MaxZoom)
+	c.Position = c.Position.Add(before.Sub(d.ScreenToWorld(x, y)))
+}
+
+// Pan moves the view so that the space follows a drag of (dx, dy) on the
+// screen.
+func (d *Drawer) Pan(dx, dy float64) {
+	c := &d.Camera
+	delta := cp.Vector{X: dx, Y: d.yAxis() * dy}.Mult(1 / c.zoom()).Rotate(cp.ForAngle(c.Rotation))
+	c.Position = c.Position.Sub(delta)
+}
+
+// cameraInput is what HandleCamera remembers between frames.
+type cameraInput struct {
+	// dragging is whether the middle button was down the last frame, and
+	// last where the cursor was then.
+	dragging bool
+	last     cp.Vector
+	ids      []ebiten.TouchID
+	// touches are the two fingers panning, when they touched nothing.
+	touches [2]ebiten.TouchID
+	pinch   bool
+	mid     cp.Vector
+	dist    float64
+}
+
+// HandleCamera zooms with the wheel about the cursor, pans with the middle
+// button or two fingers that don't hold a body, which also pinch to zoom,
+// and follows Target. Call it after HandleMouseEvent.
+func (d *Drawer) HandleCamera() {
+	in := d.input()
+	h := &d.cameraInput
+
+	x, y := in.CursorPosition()
+	cursor := cp.Vector{X: float64(x), Y: float64(y)}
+	if _, wy := in.Wheel(); wy != 0 {
+		d.ZoomAt(cursor.X, cursor.Y, math.Pow(wheelZoom, wy))
+	}
+
+	if in.MiddlePressed() {
+		if h.dragging {
+			d.Pan(cursor.X-h.last.X, cursor.Y-h.last.Y)
+		}
+		h.last = cursor
+	}
+	h.dragging = in.MiddlePressed()
+
+	d.handleTouchPan(in)
+
+	if c := &d.Camera; c.Target != nil {
+		c.Position = c.Position.Lerp(c.Target.Position(), cp.Clamp01(c.Follow))
+	}
+}
+
+func (d *Drawer) handleTouchPan(in Input) {
+	h := &d.cameraInput
+	h.ids = in.AppendTouchIDs(h.ids[:0])
+	ids := h.ids
+	if len(ids) != 2 || d.holds(ids[0]) || d.holds(ids[1]) {
+		h.pinch = false
+		return
+	}
+	if ids[0] > ids[1] {
+		ids[0], ids[1] = ids[1], ids[0]
+	}
+	ax, ay := in.TouchPosition(ids[0])
+	bx, by := in.TouchPosition(ids[1])
+	a := cp.Vector{X: float64(ax), Y: float64(ay)}
+	b := cp.Vector{X: float64(bx), Y: float64(by)}
+	mid, dist := a.Lerp(b, 0.5), a.Distance(b)
+
+	if h.pinch && h.touches == [2]ebiten.TouchID{ids[0], ids[1]} {
+		d.Pan(mid.X-h.mid.X, mid.Y-h.mid.Y)
+		if h.dist > 0 && dist > 0 {
+			d.ZoomAt(mid.X, mid.Y, dist/h.dist)
+		}
+	}
+	h.pinch = true
+	h.touches = [2]ebiten.TouchID{ids[0], ids[1]}
+	h.mid, h.dist = mid, dist
+}
+
+// holds reports whether the touch id holds a body.
+func (d *Drawer) holds(id ebiten.TouchID) bool {
+	g, ok := d.handler.grabs[id]
+	return ok && g.joint != nil
+}
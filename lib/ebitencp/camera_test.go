@@ -0,0 +1,209 @@
+package ebitencp
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+func near(a, b cp.Vector) bool {
+	return a.Distance(b) < 1e-9
+}
+
+func cameras() []Camera {
+	return []Camera{
+		{Zoom: 1},
+		{},
+		{Position: cp.Vector{X: 30, Y: -20}, Zoom: 2.5},
+		{Position: cp.Vector{X: -7, Y: 12}, Zoom: 0.4, Rotation: 1},
+		{Zoom: 3, Rotation: -math.Pi / 2},
+	}
+}
+
+func TestCameraRoundTrip(t *testing.T) {
+	d := NewDrawer(640, 480)
+	for _, flip := range []bool{false, true} {
+		d.FlipYAxis = flip
+		for _, c := range cameras() {
+			d.Camera = c
+			for _, v := range []cp.Vector{{}, {X: 100, Y: 50}, {X: -3.5, Y: 200}} {
+				x, y := d.WorldToScreen(v)
+				if got := d.ScreenToWorld(x, y); !near(got, v) {
+					t.Errorf("flip %v, %+v: %v goes to (%v, %v) and back to %v", flip, c, v, x, y, got)
+				}
+			}
+		}
+	}
+}
+
+func TestCameraDefault(t *testing.T) {
+	d := NewDrawer(640, 480)
+	// the origin at the centre of the screen and the y axis up
+	if x, y := d.WorldToScreen(cp.Vector{X: 10, Y: 20}); x != 330 || y != 220 {
+		t.Errorf("(10, 20) at (%v, %v), want (330, 220)", x, y)
+	}
+}
+
+func TestCameraFlipYAxis(t *testing.T) {
+	d := NewDrawer(640, 480)
+	d.FlipYAxis = true
+	// a space laid out like the screen shows as it is
+	d.Camera.Position = cp.Vector{X: 320, Y: 240}
+	if x, y := d.WorldToScreen(cp.Vector{X: 10, Y: 20}); x != 10 || y != 20 {
+		t.Errorf("(10, 20) at (%v, %v), want (10, 20)", x, y)
+	}
+	d.Pan(30, -40)
+	if want := (cp.Vector{X: 290, Y: 280}); !near(d.Camera.Position, want) {
+		t.Errorf("camera at %v after a drag, want %v", d.Camera.Position, want)
+	}
+}
+
+func TestCameraRotation(t *testing.T) {
+	d := NewDrawer(200, 200)
+	d.Camera.Rotation = math.Pi / 2
+	// turned a quarter counter-clockwise, the x axis of the space points
+	// down the screen
+	x, y := d.WorldToScreen(cp.Vector{X: 10})
+	if math.Abs(x-100) > 1e-9 || math.Abs(y-110) > 1e-9 {
+		t.Errorf("(10, 0) at (%v, %v), want (100, 110)", x, y)
+	}
+}
+
+func TestZoomAt(t *testing.T) {
+	d := NewDrawer(640, 480)
+	for _, c := range cameras() {
+		d.Camera = c
+		for _, factor := range []float64{1.1, 0.5, 4} {
+			before := d.ScreenToWorld(500, 100)
+			zoom := d.Camera.zoom()
+			d.ZoomAt(500, 100, factor)
+			if got := d.ScreenToWorld(500, 100); !near(got, before) {
+				t.Errorf("%+v zoomed by %v: %v under the cursor, was %v", c, factor, got, before)
+			}
+			if want := cp.Clamp(zoom*factor, MinZoom, MaxZoom); d.Camera.Zoom != want {
+				t.Errorf("zoom %v, want %v", d.Camera.Zoom, want)
+			}
+		}
+	}
+}
+
+func TestPan(t *testing.T) {
+	d := NewDrawer(640, 480)
+	for _, flip := range []bool{false, true} {
+		d.FlipYAxis = flip
+		for _, c := range cameras() {
+			d.Camera = c
+			before := d.ScreenToWorld(100, 100)
+			d.Pan(30, -40)
+			// what was at (100, 100) followed the drag
+			if got := d.ScreenToWorld(130, 60); !near(got, before) {
+				t.Errorf("flip %v, %+v: %v at the end of the drag, want %v", flip, c, got, before)
+			}
+		}
+	}
+}
+
+func TestHandleCameraWheel(t *testing.T) {
+	d, in := newTestDrawer()
+	in.cursor = image.Pt(30, 170)
+	before := d.ScreenToWorld(30, 170)
+	in.wheel = 2
+	d.HandleCamera()
+	if want := wheelZoom * wheelZoom; math.Abs(d.Camera.Zoom-want) > 1e-9 {
+		t.Errorf("zoom %v, want %v", d.Camera.Zoom, want)
+	}
+	if got := d.ScreenToWorld(30, 170); !near(got, before) {
+		t.Errorf("%v under the cursor, was %v", got, before)
+	}
+}
+
+func TestHandleCameraMiddleDrag(t *testing.T) {
+	d, in := newTestDrawer()
+	in.middle = true
+	in.cursor = image.Pt(50, 50)
+	d.HandleCamera()
+	in.cursor = image.Pt(60, 80)
+	d.HandleCamera()
+	if want := (cp.Vector{X: -10, Y: 30}); !near(d.Camera.Position, want) {
+		t.Errorf("camera at %v, want %v", d.Camera.Position, want)
+	}
+	// moving without the button does nothing
+	in.middle = false
+	in.cursor = image.Pt(0, 0)
+	d.HandleCamera()
+	if want := (cp.Vector{X: -10, Y: 30}); !near(d.Camera.Position, want) {
+		t.Errorf("camera at %v after letting go, want %v", d.Camera.Position, want)
+	}
+}
+
+func TestHandleCameraTwoFingers(t *testing.T) {
+	space, _ := testScene(image.Pt(20, 20))
+	d, in := newTestDrawer()
+
+	in.touches[1] = image.Pt(80, 100)
+	in.touches[2] = image.Pt(120, 100)
+	d.HandleMouseEvent(space)
+	d.HandleCamera()
+	// both fingers slide 10 right and spread to twice as far apart
+	in.touches[1] = image.Pt(70, 100)
+	in.touches[2] = image.Pt(150, 100)
+	before := d.ScreenToWorld(100, 100)
+	d.HandleMouseEvent(space)
+	d.HandleCamera()
+	if d.Camera.Zoom != 2 {
+		t.Errorf("zoom %v, want 2", d.Camera.Zoom)
+	}
+	if got := d.ScreenToWorld(110, 100); !near(got, before) {
+		t.Errorf("%v between the fingers, want %v", got, before)
+	}
+
+	// a finger on a body drags it instead
+	d, in = newTestDrawer()
+	in.touches = map[ebiten.TouchID]image.Point{1: image.Pt(20, 20), 2: image.Pt(120, 100)}
+	d.HandleMouseEvent(space)
+	d.HandleCamera()
+	in.touches[2] = image.Pt(180, 100)
+	d.HandleMouseEvent(space)
+	d.HandleCamera()
+	if d.Camera.Zoom != 1 || d.Camera.Position != (cp.Vector{}) {
+		t.Errorf("the camera moved to %v at zoom %v", d.Camera.Position, d.Camera.Zoom)
+	}
+}
+
+func TestHandleCameraFollow(t *testing.T) {
+	_, bodies := testScene(image.Pt(200, 100))
+	d, _ := newTestDrawer()
+	d.Camera.Target = bodies[0]
+	d.Camera.Follow = 0.5
+	d.HandleCamera()
+	if want := bodies[0].Position().Mult(0.5); !near(d.Camera.Position, want) {
+		t.Errorf("camera at %v, want %v", d.Camera.Position, want)
+	}
+	for i := 0; i < 60; i++ {
+		d.HandleCamera()
+	}
+	if !near(d.Camera.Position, bodies[0].Position()) {
+		t.Errorf("camera at %v, never reached %v", d.Camera.Position, bodies[0].Position())
+	}
+}
+
+func TestGrabZoomed(t *testing.T) {
+	space, bodies := testScene(image.Pt(100, 100))
+	d, in := newTestDrawer()
+	// the ball at the origin of the space shows up at (140, 100)
+	d.Camera.Position = cp.Vector{X: -20}
+	d.Camera.Zoom = 2
+	bodies[0].SetPosition(cp.Vector{})
+
+	in.touches[1] = image.Pt(140, 100)
+	d.HandleMouseEvent(space)
+	if g := d.handler.grabs[1]; g == nil || g.target != bodies[0] {
+		t.Fatalf("the touch didn't grab the ball through the camera")
+	}
+	if p := d.handler.grabs[1].body.Position(); !near(p, cp.Vector{}) {
+		t.Errorf("grabbed at %v, want the origin", p)
+	}
+}
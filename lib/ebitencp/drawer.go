@@ -0,0 +1,171 @@
+package ebitencp
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+type Drawer struct {
+	whiteImage *ebiten.Image
+	Screen     *ebiten.Image
+
+	ScreenWidth  int
+	ScreenHeight int
+	AntiAlias    bool
+	StrokeWidth  float32
+
+	// Input is where HandleMouseEvent reads the cursor and the touches
+	// from. Nil means Ebitengine's.
+	Input   Input
+	handler mouseEventHandler
+
+	// FlipYAxis makes the y axis of the space point down the screen, as
+	// Ebitengine's does, instead of up.
+	FlipYAxis bool
+	// Camera is where the space is seen from.
+	Camera      Camera
+	cameraInput cameraInput
+
+	// Theme overrides the colours DrawSpace draws with.
+	Theme Theme
+
+	// Overlay is the layers of the debug overlay DrawOverlay draws.
+	Overlay Layer
+	overlay Overlay
+
+	// vertices and indices are the triangles waiting for Flush.
+	vertices []ebiten.Vertex
+	indices  []uint16
+	// calls counts the DrawTriangles calls made by Flush.
+	calls  int
+	points []point
+}
+
+func NewDrawer(screenWidth, screenHeight int) *Drawer {
+	whiteImage := ebiten.NewImage(3, 3)
+	whiteImage.Fill(color.White)
+	return &Drawer{
+		whiteImage:   whiteImage,
+		ScreenWidth:  screenWidth,
+		ScreenHeight: screenHeight,
+		AntiAlias:    true,
+		StrokeWidth:  1,
+		Camera:       Camera{Zoom: 1, Follow: 0.1},
+	}
+}
+
+func (d *Drawer) DrawCircle(pos cp.Vector, angle, radius float64, outline, fill cp.FColor, data interface{}) {
+	d.points = d.arc(d.points[:0], pos, radius, 0, 2*math.Pi)
+	// the last point is the first one again
+	pts := d.points[:len(d.points)-1]
+	d.fillConvex(pts, fill)
+	d.strokeLoop(pts, outline)
+	// a radius shows the angle
+	d.strokeLine(
+		d.toScreen(pos),
+		d.toScreen(pos.Add(cp.ForAngle(angle).Mult(radius))),
+		outline)
+}
+
+func (d *Drawer) DrawSegment(a, b cp.Vector, fill cp.FColor, data interface{}) {
+	d.strokeLine(d.toScreen(a), d.toScreen(b), fill)
+}
+
+func (d *Drawer) DrawFatSegment(a, b cp.Vector, radius float64, outline, fill cp.FColor, data interface{}) {
+	if radius <= 0 {
+		d.strokeLine(d.toScreen(a), d.toScreen(b), outline)
+		return
+	}
+	// a half circle round each end
+	t := math.Atan2(b.Y-a.Y, b.X-a.X)
+	d.points = d.arc(d.points[:0], b, radius, t-math.Pi/2, t+math.Pi/2)
+	d.points = d.arc(d.points, a, radius, t+math.Pi/2, t+3*math.Pi/2)
+	d.fillConvex(d.points, fill)
+	d.strokeLoop(d.points, outline)
+}
+
+func (d *Drawer) DrawPolygon(count int, verts []cp.Vector, radius float64, outline, fill cp.FColor, data interface{}) {
+	// rounded polygons are drawn grown by their radius
+	d.points = d.points[:0]
+	for i := 0; i < count; i++ {
+		v0 := verts[(i-1+count)%count]
+		v1 := verts[i]
+		v2 := verts[(i+1)%count]
+
+		n1 := v1.Sub(v0).ReversePerp().Normalize()
+		n2 := v2.Sub(v1).ReversePerp().Normalize()
+
+		offset := n1.Add(n2).Mult(1.0 / (n1.Dot(n2) + 1.0))
+		d.points = append(d.points, d.toScreen(v1.Add(offset.Mult(radius))))
+	}
+	d.fillConvex(d.points, fill)
+	d.strokeLoop(d.points, outline)
+}
+
+func (d *Drawer) DrawDot(size float64, pos cp.Vector, fill cp.FColor, data interface{}) {
+	d.points = d.arc(d.points[:0], pos, 2, 0, 2*math.Pi)
+	d.fillConvex(d.points[:len(d.points)-1], fill)
+}
+
+func (d *Drawer) Flags() uint {
+	return 0
+}
+
+func (d *Drawer) OutlineColor() cp.FColor {
+	return d.Theme.color(d.Theme.Outline, cp.FColor{R: 200.0 / 255.0, G: 210.0 / 255.0, B: 230.0 / 255.0, A: 1})
+}
+
+func (d *Drawer) ShapeColor(shape *cp.Shape, data interface{}) cp.FColor {
+	body := shape.Body()
+	if body.IsSleeping() {
+		return cp.FColor{R: .2, G: .2, B: .2, A: 1}
+	}
+
+	if body.IdleTime() > shape.Space().SleepTimeThreshold {
+		return cp.FColor{R: .66, G: .66, B: .66, A: 1}
+	}
+	return d.Theme.color(d.Theme.Shape, cp.FColor{R: 0.7, G: 0.3, B: 0.6, A: 0.5})
+}
+
+func (d *Drawer) ConstraintColor() cp.FColor {
+	return d.Theme.color(d.Theme.Constraint, cp.FColor{R: 0, G: 0.75, B: 0, A: 1})
+}
+
+func (d *Drawer) CollisionPointColor() cp.FColor {
+	return d.Theme.color(d.Theme.CollisionPoint, cp.FColor{R: 1, G: 0.1, B: 0.2, A: 1})
+}
+
+func (d *Drawer) Data() interface{} {
+	return nil
+}
+
+// Theme is the colours of DrawSpace. A nil colour keeps the drawer's own.
+// Shapes of sleeping and resting bodies stay grey.
+type Theme struct {
+	Shape          color.Color
+	Outline        color.Color
+	Constraint     color.Color
+	CollisionPoint color.Color
+}
+
+func (t *Theme) color(c color.Color, fallback cp.FColor) cp.FColor {
+	if c == nil {
+		return fallback
+	}
+	return FColor(c)
+}
+
+// FColor returns c as the colour of a vertex, whose alpha isn't
+// premultiplied.
+func FColor(c color.Color) cp.FColor {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return cp.FColor{
+		R: float32(n.R) / 0xff,
+		G: float32(n.G) / 0xff,
+		B: float32(n.B) / 0xff,
+		A: float32(n.A) / 0xff,
+	}
+}
@@ -0,0 +1,264 @@
+package ebitencp
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+var (
+	red  = cp.FColor{R: 1, A: 1}
+	blue = cp.FColor{B: 1, A: 0.5}
+)
+
+// checkIndices fails when an index of d points past its vertices.
+func checkIndices(t *testing.T, d *Drawer) {
+	t.Helper()
+	if len(d.indices)%3 != 0 {
+		t.Fatalf("%d indices, not triangles", len(d.indices))
+	}
+	for i, idx := range d.indices {
+		if int(idx) >= len(d.vertices) {
+			t.Fatalf("index %d is %d, past the %d vertices", i, idx, len(d.vertices))
+		}
+	}
+}
+
+func TestDrawPolygon(t *testing.T) {
+	d := NewDrawer(200, 100)
+	d.StrokeWidth = 2
+	square := []cp.Vector{{X: -10, Y: -10}, {X: 10, Y: -10}, {X: 10, Y: 10}, {X: -10, Y: 10}}
+	d.DrawPolygon(len(square), square, 0, blue, red, nil)
+	checkIndices(t, d)
+
+	// 4 fill vertices, then 4 per edge
+	if got, want := len(d.vertices), 4+4*4; got != want {
+		t.Fatalf("%d vertices, want %d", got, want)
+	}
+	if got, want := len(d.indices), 2*3+4*6; got != want {
+		t.Fatalf("%d indices, want %d", got, want)
+	}
+	// the y axis flips and the origin moves to the centre of the screen
+	want := []point{{90, 60}, {110, 60}, {110, 40}, {90, 40}}
+	for i, p := range want {
+		v := d.vertices[i]
+		if v.DstX != p.X || v.DstY != p.Y {
+			t.Errorf("fill vertex %d at (%v, %v), want %v", i, v.DstX, v.DstY, p)
+		}
+		if v.ColorR != red.R || v.ColorB != red.B || v.ColorA != red.A {
+			t.Errorf("fill vertex %d is %v %v %v %v, want red", i, v.ColorR, v.ColorG, v.ColorB, v.ColorA)
+		}
+		if v.SrcX != 1 || v.SrcY != 1 {
+			t.Errorf("fill vertex %d samples (%v, %v)", i, v.SrcX, v.SrcY)
+		}
+	}
+	for i, v := range d.vertices[4:] {
+		if v.ColorR != blue.R || v.ColorB != blue.B || v.ColorA != blue.A {
+			t.Errorf("outline vertex %d is %v %v %v %v, want blue", i, v.ColorR, v.ColorG, v.ColorB, v.ColorA)
+		}
+	}
+	// the first edge is 2 wide and sticks out by 1 at both ends
+	edge := d.vertices[4:8]
+	minX, maxX, minY, maxY := float32(math.Inf(1)), float32(math.Inf(-1)), float32(math.Inf(1)), float32(math.Inf(-1))
+	for _, v := range edge {
+		minX, maxX = min(minX, v.DstX), max(maxX, v.DstX)
+		minY, maxY = min(minY, v.DstY), max(maxY, v.DstY)
+	}
+	if minX != 89 || maxX != 111 || minY != 59 || maxY != 61 {
+		t.Errorf("first edge spans x %v to %v and y %v to %v", minX, maxX, minY, maxY)
+	}
+}
+
+func TestDrawRoundedPolygon(t *testing.T) {
+	d := NewDrawer(0, 0)
+	square := []cp.Vector{{X: -10, Y: -10}, {X: 10, Y: -10}, {X: 10, Y: 10}, {X: -10, Y: 10}}
+	d.DrawPolygon(len(square), square, 2, blue, red, nil)
+	// grown by the radius along the diagonals
+	if v := d.vertices[0]; v.DstX != -12 || v.DstY != 12 {
+		t.Errorf("first vertex at (%v, %v), want (-12, 12)", v.DstX, v.DstY)
+	}
+}
+
+func TestDrawCircle(t *testing.T) {
+	d := NewDrawer(0, 0)
+	d.DrawCircle(cp.Vector{X: 5, Y: 5}, 0, 10, blue, red, nil)
+	checkIndices(t, d)
+
+	fill := 0
+	for _, v := range d.vertices {
+		if v.ColorA != red.A {
+			break
+		}
+		fill++
+		if r := math.Hypot(float64(v.DstX-5), float64(v.DstY+5)); math.Abs(r-10) > 1e-4 {
+			t.Errorf("fill vertex at (%v, %v) is %v from the centre", v.DstX, v.DstY, r)
+		}
+	}
+	if n := arcSegments(2 * math.Pi * 10); fill != n {
+		t.Errorf("%d fill vertices, want %d", fill, n)
+	}
+	// an outline quad per segment and one for the radius
+	if got, want := len(d.vertices), fill+4*(fill+1); got != want {
+		t.Errorf("%d vertices, want %d", got, want)
+	}
+}
+
+func TestDrawSegment(t *testing.T) {
+	d := NewDrawer(0, 0)
+	d.DrawSegment(cp.Vector{X: 0, Y: 0}, cp.Vector{X: 10, Y: 0}, red, nil)
+	if len(d.vertices) != 4 || len(d.indices) != 6 {
+		t.Fatalf("%d vertices and %d indices, want a quad", len(d.vertices), len(d.indices))
+	}
+	// nothing for a segment of no length
+	d.DrawSegment(cp.Vector{X: 1, Y: 1}, cp.Vector{X: 1, Y: 1}, red, nil)
+	if len(d.vertices) != 4 {
+		t.Errorf("%d vertices after an empty segment", len(d.vertices))
+	}
+}
+
+func TestDrawFatSegment(t *testing.T) {
+	d := NewDrawer(0, 0)
+	a, b := cp.Vector{X: 0, Y: 0}, cp.Vector{X: 30, Y: 40}
+	d.DrawFatSegment(a, b, 5, blue, red, nil)
+	checkIndices(t, d)
+	for _, v := range d.vertices {
+		if v.ColorA != red.A {
+			break
+		}
+		// every fill vertex is on the capsule around a and b
+		p := cp.Vector{X: float64(v.DstX), Y: -float64(v.DstY)}
+		dist := math.Min(p.Distance(a), p.Distance(b))
+		if math.Abs(dist-5) > 1e-3 {
+			t.Errorf("fill vertex %v is %v from the ends", p, dist)
+		}
+	}
+}
+
+func TestTheme(t *testing.T) {
+	space := cp.NewSpace()
+	body := space.AddBody(cp.NewBody(1, 1))
+	shape := space.AddShape(cp.NewCircle(body, 10, cp.Vector{}))
+
+	d := NewDrawer(0, 0)
+	outline, fill := d.OutlineColor(), d.ShapeColor(shape, nil)
+	d.Theme.Shape = color.RGBA{R: 0x00, G: 0x81, B: 0xa7, A: 0xff}
+	if got, want := d.ShapeColor(shape, nil), (cp.FColor{R: 0, G: 0x81 / 255.0, B: 0xa7 / 255.0, A: 1}); got != want {
+		t.Errorf("shape colour %v, want %v", got, want)
+	}
+	if got := d.OutlineColor(); got != outline {
+		t.Errorf("outline colour %v without a theme outline, want %v", got, outline)
+	}
+	d.Theme = Theme{}
+	if got := d.ShapeColor(shape, nil); got != fill {
+		t.Errorf("shape colour %v without a theme, want %v", got, fill)
+	}
+}
+
+func TestFColor(t *testing.T) {
+	for _, tt := range []struct {
+		c    color.Color
+		want cp.FColor
+	}{
+		{color.White, cp.FColor{R: 1, G: 1, B: 1, A: 1}},
+		{color.RGBA{R: 0xff, A: 0xff}, cp.FColor{R: 1, A: 1}},
+		// premultiplied half transparent red is still fully red
+		{color.RGBA{R: 0x80, A: 0x80}, cp.FColor{R: 1, A: 0x80 / 255.0}},
+		{color.Transparent, cp.FColor{}},
+	} {
+		if got := FColor(tt.c); got != tt.want {
+			t.Errorf("FColor(%v) = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestFlush(t *testing.T) {
+	d := NewDrawer(100, 100)
+	d.Flush()
+	if d.calls != 0 {
+		t.Errorf("%d calls for nothing", d.calls)
+	}
+
+	for i := 0; i < 10; i++ {
+		d.DrawCircle(cp.Vector{X: float64(i)}, 0, 10, blue, red, nil)
+		d.DrawSegment(cp.Vector{}, cp.Vector{X: float64(i)}, red, nil)
+	}
+	d.Flush()
+	if d.calls != 1 {
+		t.Errorf("%d calls, want 1", d.calls)
+	}
+	if len(d.vertices) != 0 || len(d.indices) != 0 {
+		t.Errorf("%d vertices and %d indices left", len(d.vertices), len(d.indices))
+	}
+}
+
+func TestFlushSplits(t *testing.T) {
+	d := NewDrawer(100, 100)
+	added := 0
+	for d.calls == 0 {
+		before := len(d.vertices)
+		d.DrawCircle(cp.Vector{}, 0, 100, blue, red, nil)
+		checkIndices(t, d)
+		if len(d.vertices) > maxVertices {
+			t.Fatalf("%d vertices in one batch", len(d.vertices))
+		}
+		if d.calls == 0 {
+			added += len(d.vertices) - before
+		}
+	}
+	if added > maxVertices {
+		t.Errorf("%d vertices went into the first batch", added)
+	}
+}
+
+func scene(d interface {
+	DrawCircle(cp.Vector, float64, float64, cp.FColor, cp.FColor, interface{})
+	DrawPolygon(int, []cp.Vector, float64, cp.FColor, cp.FColor, interface{})
+	DrawSegment(cp.Vector, cp.Vector, cp.FColor, interface{})
+}) {
+	box := []cp.Vector{{X: -10, Y: -10}, {X: 10, Y: -10}, {X: 10, Y: 10}, {X: -10, Y: 10}}
+	for i := 0; i < 500; i++ {
+		d.DrawCircle(cp.Vector{X: float64(i % 30), Y: float64(i / 30)}, 0, 8, blue, red, nil)
+		d.DrawPolygon(len(box), box, 0, blue, red, nil)
+		d.DrawSegment(cp.Vector{}, cp.Vector{X: 100}, red, nil)
+	}
+}
+
+// unbatched flushes after every shape, like the drawer did before it
+// batched.
+type unbatched struct {
+	*Drawer
+}
+
+func (u unbatched) DrawCircle(pos cp.Vector, angle, radius float64, outline, fill cp.FColor, data interface{}) {
+	u.Drawer.DrawCircle(pos, angle, radius, outline, fill, data)
+	u.Flush()
+}
+
+func (u unbatched) DrawPolygon(count int, verts []cp.Vector, radius float64, outline, fill cp.FColor, data interface{}) {
+	u.Drawer.DrawPolygon(count, verts, radius, outline, fill, data)
+	u.Flush()
+}
+
+func (u unbatched) DrawSegment(a, b cp.Vector, fill cp.FColor, data interface{}) {
+	u.Drawer.DrawSegment(a, b, fill, data)
+	u.Flush()
+}
+
+func BenchmarkBatched(b *testing.B) {
+	d := NewDrawer(640, 480)
+	for i := 0; i < b.N; i++ {
+		scene(d)
+		d.Flush()
+	}
+	b.ReportMetric(float64(d.calls)/float64(b.N), "calls/op")
+}
+
+func BenchmarkUnbatched(b *testing.B) {
+	d := NewDrawer(640, 480)
+	for i := 0; i < b.N; i++ {
+		scene(unbatched{d})
+	}
+	b.ReportMetric(float64(d.calls)/float64(b.N), "calls/op")
+}
@@ -0,0 +1,17 @@
+module github.com/demouth/ebitengine-sketch/lib/ebitencp
+
+go 1.22.1
+
+require (
+	github.com/hajimehoshi/ebiten/v2 v2.8.0-alpha.3.0.20240811190802-435c8b75ebc0
+	github.com/jakecoffman/cp/v2 v2.0.2
+)
+
+require (
+	github.com/ebitengine/gomobile v0.0.0-20240802043200-192f051f4fcc // indirect
+	github.com/ebitengine/hideconsole v1.0.0 // indirect
+	github.com/ebitengine/purego v0.8.0-alpha.4 // indirect
+	github.com/jezek/xgb v1.1.1 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+)
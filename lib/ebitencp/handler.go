@@ -0,0 +1,206 @@
+package ebitencp
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// event handling: every finger, and the mouse, grabs its own body with its
+// own kinematic body and pivot joint, and flings it when it lets go.
+
+const GRABBABLE_MASK_BIT uint = 1 << 31
+
+var grabFilter cp.ShapeFilter = cp.ShapeFilter{
+	Group:      cp.NO_GROUP,
+	Categories: GRABBABLE_MASK_BIT,
+	Mask:       GRABBABLE_MASK_BIT,
+}
+
+// Input is where HandleMouseEvent and HandleCamera read the cursor, the
+// wheel and the touches from.
+type Input interface {
+	CursorPosition() (x, y int)
+	// MousePressed reports whether the left button is down.
+	MousePressed() bool
+	// MiddlePressed reports whether the middle button is down.
+	MiddlePressed() bool
+	Wheel() (x, y float64)
+	AppendTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID
+	TouchPosition(id ebiten.TouchID) (x, y int)
+}
+
+// ebitenInput is the Input of Ebitengine.
+type ebitenInput struct{}
+
+func (ebitenInput) CursorPosition() (int, int) { return ebiten.CursorPosition() }
+func (ebitenInput) MousePressed() bool {
+	return ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+}
+func (ebitenInput) MiddlePressed() bool {
+	return ebiten.IsMouseButtonPressed(ebiten.MouseButtonMiddle)
+}
+func (ebitenInput) Wheel() (float64, float64) { return ebiten.Wheel() }
+func (ebitenInput) AppendTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID {
+	return ebiten.AppendTouchIDs(ids)
+}
+func (ebitenInput) TouchPosition(id ebiten.TouchID) (int, int) { return ebiten.TouchPosition(id) }
+
+// mouseID is the key of the mouse among the touches.
+const mouseID ebiten.TouchID = -1
+
+const (
+	// tick is the time between two calls of HandleMouseEvent.
+	tick = 1 / 60.0
+	// flingFrames is how far back the speed of a pointer is measured
+	// when it lets go.
+	flingFrames = 5
+)
+
+type sample struct {
+	pos   cp.Vector
+	frame int
+}
+
+// grab is what one pointer holds.
+type grab struct {
+	// body follows the pointer.
+	body *cp.Body
+	// joint pins target to body. Both are nil when the pointer went down
+	// on nothing.
+	joint  *cp.Constraint
+	target *cp.Body
+	// history is where the pointer was in the last flingFrames frames.
+	history []sample
+}
+
+type mouseEventHandler struct {
+	grabs    map[ebiten.TouchID]*grab
+	frame    int
+	touchIDs []ebiten.TouchID
+	down     []ebiten.TouchID
+}
+
+// HandleMouseEvent lets the mouse and every finger drag bodies of space.
+func (d *Drawer) HandleMouseEvent(space *cp.Space) {
+	d.handler.handle(space, d.input(), func(x, y int) cp.Vector {
+		return d.ScreenToWorld(float64(x), float64(y))
+	})
+}
+
+func (d *Drawer) input() Input {
+	if d.Input == nil {
+		return ebitenInput{}
+	}
+	return d.Input
+}
+
+func (h *mouseEventHandler) handle(space *cp.Space, in Input, toSpace func(x, y int) cp.Vector) {
+	if h.grabs == nil {
+		h.grabs = map[ebiten.TouchID]*grab{}
+	}
+	h.frame++
+	pointers := map[ebiten.TouchID]cp.Vector{}
+	h.touchIDs = in.AppendTouchIDs(h.touchIDs[:0])
+	for _, id := range h.touchIDs {
+		pointers[id] = toSpace(in.TouchPosition(id))
+	}
+	// browsers move the cursor with the first finger, so the mouse only
+	// counts without touches
+	if len(h.touchIDs) == 0 && in.MousePressed() {
+		pointers[mouseID] = toSpace(in.CursorPosition())
+	}
+
+	for id, g := range h.grabs {
+		if _, ok := pointers[id]; !ok {
+			h.release(space, g)
+			delete(h.grabs, id)
+		}
+	}
+
+	// give the click a little radius of the screen to make it easier to
+	// click small shapes
+	radius := toSpace(5, 0).Distance(toSpace(0, 0))
+
+	h.down = h.down[:0]
+	for id, pos := range pointers {
+		if g, ok := h.grabs[id]; ok {
+			g.move(pos, h.frame)
+		} else {
+			h.down = append(h.down, id)
+		}
+	}
+	// the first finger down on a body gets it, whatever the map order
+	sort.Slice(h.down, func(i, j int) bool { return h.down[i] < h.down[j] })
+	for _, id := range h.down {
+		h.grabs[id] = h.grab(space, pointers[id], radius)
+	}
+}
+
+// held reports whether a pointer already holds body.
+func (h *mouseEventHandler) held(body *cp.Body) bool {
+	for _, g := range h.grabs {
+		if g.target == body {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *mouseEventHandler) grab(space *cp.Space, pos cp.Vector, radius float64) *grab {
+	g := &grab{body: cp.NewKinematicBody()}
+	g.body.SetPosition(pos)
+	g.history = append(g.history, sample{pos, h.frame})
+
+	info := space.PointQueryNearest(pos, radius, grabFilter)
+	if info.Shape == nil || info.Shape.Body().Mass() >= cp.INFINITY || h.held(info.Shape.Body()) {
+		return g
+	}
+	nearest := pos
+	if info.Distance > 0 {
+		nearest = info.Point
+	}
+
+	g.target = info.Shape.Body()
+	g.joint = cp.NewPivotJoint2(g.body, g.target, cp.Vector{}, g.target.WorldToLocal(nearest))
+	g.joint.SetMaxForce(50000)
+	g.joint.SetErrorBias(math.Pow(1.0-0.15, 60.0))
+	space.AddConstraint(g.joint)
+	return g
+}
+
+func (g *grab) move(pos cp.Vector, frame int) {
+	newPoint := g.body.Position().Lerp(pos, 0.25)
+	g.body.SetVelocityVector(newPoint.Sub(g.body.Position()).Mult(1 / tick))
+	g.body.SetPosition(newPoint)
+
+	g.history = append(g.history, sample{pos, frame})
+	for len(g.history) > 0 && frame-g.history[0].frame > flingFrames {
+		g.history = g.history[1:]
+	}
+}
+
+// velocity returns how fast the pointer moved over its history.
+func (g *grab) velocity() cp.Vector {
+	if len(g.history) < 2 {
+		return cp.Vector{}
+	}
+	first, last := g.history[0], g.history[len(g.history)-1]
+	return last.pos.Sub(first.pos).Mult(1 / (float64(last.frame-first.frame) * tick))
+}
+
+// release lets go of the target, which flies off as fast as the pointer
+// moved.
+func (h *mouseEventHandler) release(space *cp.Space, g *grab) {
+	if g.joint == nil {
+		return
+	}
+	// the target may have been removed with its joint
+	if space.ContainsConstraint(g.joint) {
+		space.RemoveConstraint(g.joint)
+	}
+	g.target.SetVelocityVector(g.velocity())
+	g.target.Activate()
+}
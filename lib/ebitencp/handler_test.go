@@ -0,0 +1,234 @@
+package ebitencp
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// fakeInput is an Input the tests move by hand.
+type fakeInput struct {
+	cursor  image.Point
+	pressed bool
+	middle  bool
+	wheel   float64
+	touches map[ebiten.TouchID]image.Point
+}
+
+func (f *fakeInput) CursorPosition() (int, int) { return f.cursor.X, f.cursor.Y }
+func (f *fakeInput) MousePressed() bool         { return f.pressed }
+func (f *fakeInput) MiddlePressed() bool        { return f.middle }
+func (f *fakeInput) Wheel() (float64, float64)  { return 0, f.wheel }
+func (f *fakeInput) AppendTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID {
+	for id := range f.touches {
+		ids = append(ids, id)
+	}
+	return ids
+}
+func (f *fakeInput) TouchPosition(id ebiten.TouchID) (int, int) {
+	p := f.touches[id]
+	return p.X, p.Y
+}
+
+const (
+	testWidth  = 200
+	testHeight = 200
+)
+
+// testScene returns a space with balls at the given points of the screen.
+func testScene(points ...image.Point) (*cp.Space, []*cp.Body) {
+	space := cp.NewSpace()
+	var bodies []*cp.Body
+	for _, p := range points {
+		b := space.AddBody(cp.NewBody(1, cp.MomentForCircle(1, 0, 10, cp.Vector{})))
+		b.SetPosition(cp.Vector{X: float64(p.X - testWidth/2), Y: float64(-p.Y + testHeight/2)})
+		space.AddShape(cp.NewCircle(b, 10, cp.Vector{}))
+		bodies = append(bodies, b)
+	}
+	return space, bodies
+}
+
+func newTestDrawer() (*Drawer, *fakeInput) {
+	in := &fakeInput{touches: map[ebiten.TouchID]image.Point{}}
+	d := NewDrawer(testWidth, testHeight)
+	d.Input = in
+	return d, in
+}
+
+func constraints(space *cp.Space) int {
+	n := 0
+	space.EachConstraint(func(*cp.Constraint) { n++ })
+	return n
+}
+
+func TestGrabPerTouch(t *testing.T) {
+	space, bodies := testScene(image.Pt(50, 50), image.Pt(150, 150))
+	d, in := newTestDrawer()
+
+	in.touches[1] = image.Pt(50, 50)
+	in.touches[2] = image.Pt(150, 150)
+	d.HandleMouseEvent(space)
+	if n := constraints(space); n != 2 {
+		t.Fatalf("%d joints, want 2", n)
+	}
+	for id, want := range map[ebiten.TouchID]*cp.Body{1: bodies[0], 2: bodies[1]} {
+		g := d.handler.grabs[id]
+		if g == nil || g.joint == nil {
+			t.Fatalf("touch %d holds nothing", id)
+		}
+		if g.target != want {
+			t.Errorf("touch %d holds the wrong body", id)
+		}
+		if !space.ContainsConstraint(g.joint) {
+			t.Errorf("the joint of touch %d is not in the space", id)
+		}
+	}
+
+	// lifting one finger lets go of its body only
+	joint := d.handler.grabs[1].joint
+	delete(in.touches, 1)
+	d.HandleMouseEvent(space)
+	if n := constraints(space); n != 1 {
+		t.Fatalf("%d joints, want 1", n)
+	}
+	if space.ContainsConstraint(joint) {
+		t.Errorf("the joint of touch 1 is still in the space")
+	}
+	if _, ok := d.handler.grabs[1]; ok {
+		t.Errorf("touch 1 still grabs")
+	}
+	if g := d.handler.grabs[2]; g == nil || !space.ContainsConstraint(g.joint) {
+		t.Errorf("touch 2 let go")
+	}
+
+	delete(in.touches, 2)
+	d.HandleMouseEvent(space)
+	if n := constraints(space); n != 0 {
+		t.Errorf("%d joints left", n)
+	}
+}
+
+func TestGrabNothing(t *testing.T) {
+	space, _ := testScene(image.Pt(50, 50))
+	d, in := newTestDrawer()
+
+	in.touches[3] = image.Pt(150, 150)
+	d.HandleMouseEvent(space)
+	if n := constraints(space); n != 0 {
+		t.Errorf("%d joints for a touch on nothing", n)
+	}
+	// the finger slides onto the ball, which it didn't go down on
+	in.touches[3] = image.Pt(50, 50)
+	d.HandleMouseEvent(space)
+	if n := constraints(space); n != 0 {
+		t.Errorf("%d joints for a touch sliding onto a body", n)
+	}
+	delete(in.touches, 3)
+	d.HandleMouseEvent(space)
+	if len(d.handler.grabs) != 0 {
+		t.Errorf("%d grabs left", len(d.handler.grabs))
+	}
+}
+
+func TestGrabOneBodyOnce(t *testing.T) {
+	space, _ := testScene(image.Pt(100, 100))
+	d, in := newTestDrawer()
+
+	in.touches[5] = image.Pt(100, 100)
+	in.touches[4] = image.Pt(102, 100)
+	d.HandleMouseEvent(space)
+	if n := constraints(space); n != 1 {
+		t.Fatalf("%d joints, want 1", n)
+	}
+	if d.handler.grabs[4].joint == nil {
+		t.Errorf("the lower touch ID didn't get the body")
+	}
+	if d.handler.grabs[5].joint != nil {
+		t.Errorf("both touches hold the body")
+	}
+}
+
+func TestGrabFilter(t *testing.T) {
+	space, bodies := testScene(image.Pt(100, 100))
+	d, in := newTestDrawer()
+	bodies[0].EachShape(func(s *cp.Shape) {
+		// not in the grabbable category
+		s.SetFilter(cp.NewShapeFilter(cp.NO_GROUP, 1, cp.ALL_CATEGORIES))
+	})
+
+	in.touches[1] = image.Pt(100, 100)
+	d.HandleMouseEvent(space)
+	if n := constraints(space); n != 0 {
+		t.Errorf("%d joints on a body that isn't grabbable", n)
+	}
+}
+
+func TestFling(t *testing.T) {
+	space, bodies := testScene(image.Pt(100, 100))
+	d, in := newTestDrawer()
+
+	// 10 pixels right and 5 up a frame
+	for i := 0; i < 10; i++ {
+		in.touches[1] = image.Pt(100+10*i, 100-5*i)
+		d.HandleMouseEvent(space)
+	}
+	delete(in.touches, 1)
+	d.HandleMouseEvent(space)
+
+	want := cp.Vector{X: 10 / tick, Y: 5 / tick}
+	if got := bodies[0].Velocity(); got.Distance(want) > 1e-6 {
+		t.Errorf("flung at %v, want %v", got, want)
+	}
+}
+
+func TestFlingStill(t *testing.T) {
+	space, bodies := testScene(image.Pt(100, 100))
+	d, in := newTestDrawer()
+
+	// a swipe, then the finger rests for longer than the history
+	for i := 0; i < 5; i++ {
+		in.touches[1] = image.Pt(100+10*i, 100)
+		d.HandleMouseEvent(space)
+	}
+	for i := 0; i < flingFrames+2; i++ {
+		d.HandleMouseEvent(space)
+	}
+	delete(in.touches, 1)
+	d.HandleMouseEvent(space)
+
+	if v := bodies[0].Velocity(); math.Abs(v.X) > 1e-9 || math.Abs(v.Y) > 1e-9 {
+		t.Errorf("flung at %v after resting", v)
+	}
+}
+
+func TestMouse(t *testing.T) {
+	space, _ := testScene(image.Pt(50, 50), image.Pt(150, 150))
+	d, in := newTestDrawer()
+
+	in.cursor = image.Pt(50, 50)
+	in.pressed = true
+	d.HandleMouseEvent(space)
+	if g := d.handler.grabs[mouseID]; g == nil || g.joint == nil {
+		t.Fatalf("the mouse holds nothing")
+	}
+
+	// a touch takes over from the mouse, which browsers move with it
+	in.touches[0] = image.Pt(150, 150)
+	d.HandleMouseEvent(space)
+	if _, ok := d.handler.grabs[mouseID]; ok {
+		t.Errorf("the mouse still grabs with a touch down")
+	}
+	if n := constraints(space); n != 1 {
+		t.Errorf("%d joints, want 1", n)
+	}
+
+	delete(in.touches, 0)
+	in.pressed = false
+	d.HandleMouseEvent(space)
+	if n := constraints(space); n != 0 {
+		t.Errorf("%d joints left", n)
+	}
+}
@@ -0,0 +1,196 @@
+package ebitencp
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Layer is a part of the debug overlay. Layers are bits, so that they can
+// be toggled one by one.
+type Layer uint
+
+const (
+	// LayerBodies shows centres of gravity with velocity and angular
+	// velocity arrows.
+	LayerBodies Layer = 1 << iota
+	// LayerContacts shows contact normals, as long as their impulse.
+	LayerContacts
+	// LayerBounds shows the bounding boxes of shapes.
+	LayerBounds
+	// LayerConstraints colours constraints from green to red by the
+	// force they take.
+	LayerConstraints
+	// LayerSleeping circles sleeping bodies.
+	LayerSleeping
+	// LayerHUD shows how many bodies, shapes, constraints and arbiters
+	// there are.
+	LayerHUD
+
+	LayerAll = LayerBodies | LayerContacts | LayerBounds | LayerConstraints | LayerSleeping | LayerHUD
+)
+
+// Overlay is what the debug overlay shows, read from a space without
+// drawing anything.
+type Overlay struct {
+	Bodies      []OverlayBody
+	Contacts    []OverlayContact
+	Bounds      []cp.BB
+	Constraints []OverlayConstraint
+	Counts      OverlayCounts
+
+	// forces holds the force and the max force of every constraint.
+	forces []float64
+}
+
+type OverlayBody struct {
+	// Center is the centre of gravity, in the space.
+	Center          cp.Vector
+	Velocity        cp.Vector
+	AngularVelocity float64
+	Sleeping        bool
+}
+
+type OverlayContact struct {
+	Point  cp.Vector
+	Normal cp.Vector
+	// Impulse is the impulse of the arbiter of the contact, shared
+	// between its points.
+	Impulse float64
+}
+
+type OverlayConstraint struct {
+	// A and B are the positions of the two bodies, in no given order.
+	A, B cp.Vector
+	// Load is the force on the constraint, from 0 to 1: against its max
+	// force, or against the strongest constraint when it has none.
+	Load float64
+}
+
+type OverlayCounts struct {
+	Bodies      int
+	Shapes      int
+	Constraints int
+	Arbiters    int
+}
+
+// Collect reads space into o, reusing its slices. The static body is
+// left out of the bodies, and constraints to bodies outside the space are
+// left out of the constraints, though they are counted.
+func (o *Overlay) Collect(space *cp.Space) {
+	o.Bodies = o.Bodies[:0]
+	o.Contacts = o.Contacts[:0]
+	o.Bounds = o.Bounds[:0]
+	o.Constraints = o.Constraints[:0]
+	o.Counts = OverlayCounts{}
+
+	// every arbiter is seen from both of its bodies
+	seen := map[*cp.Arbiter]bool{}
+	addArbiter := func(arb *cp.Arbiter) {
+		if seen[arb] {
+			return
+		}
+		seen[arb] = true
+		o.Counts.Arbiters++
+		set := arb.ContactPointSet()
+		if set.Count == 0 {
+			return
+		}
+		impulse := arb.TotalImpulse().Length() / float64(set.Count)
+		for i := 0; i < set.Count; i++ {
+			p := set.Points[i]
+			o.Contacts = append(o.Contacts, OverlayContact{
+				Point:   p.PointA.Lerp(p.PointB, 0.5),
+				Normal:  set.Normal,
+				Impulse: impulse,
+			})
+		}
+	}
+
+	dt := space.TimeStep()
+	o.forces = o.forces[:0]
+	index := map[*cp.Constraint]int{}
+	space.EachConstraint(func(c *cp.Constraint) {
+		o.Counts.Constraints++
+		force := 0.0
+		if dt > 0 {
+			force = math.Abs(c.Class.GetImpulse()) / dt
+		}
+		o.forces = append(o.forces, force, c.MaxForce())
+		index[c] = len(o.Constraints)
+		o.Constraints = append(o.Constraints, OverlayConstraint{})
+	})
+	// a constraint doesn't tell its bodies, so it is found from both of
+	// them: the first one gives A and the second B
+	ends := make([]int, len(o.Constraints))
+	addEnd := func(b *cp.Body) {
+		b.EachConstraint(func(c *cp.Constraint) {
+			i := index[c]
+			k := &o.Constraints[i]
+			if ends[i] > 0 {
+				k.B = b.Position()
+			} else {
+				k.A = b.Position()
+			}
+			ends[i]++
+		})
+	}
+
+	space.EachBody(func(b *cp.Body) {
+		o.Counts.Bodies++
+		o.Bodies = append(o.Bodies, OverlayBody{
+			Center:          b.LocalToWorld(b.CenterOfGravity()),
+			Velocity:        b.Velocity(),
+			AngularVelocity: b.AngularVelocity(),
+			Sleeping:        b.IsSleeping(),
+		})
+		b.EachArbiter(addArbiter)
+		addEnd(b)
+	})
+	addEnd(space.StaticBody)
+
+	space.EachShape(func(s *cp.Shape) {
+		o.Counts.Shapes++
+		o.Bounds = append(o.Bounds, s.BB())
+	})
+
+	// a constraint to a body that isn't in the space, like the kinematic
+	// body of a mouse grab, has one end only and is left out
+	strongest := 0.0
+	for i := range o.Constraints {
+		if ends[i] == 2 {
+			strongest = math.Max(strongest, o.forces[2*i])
+		}
+	}
+	n := 0
+	for i, k := range o.Constraints {
+		if ends[i] != 2 {
+			continue
+		}
+		k.Load = load(o.forces[2*i], o.forces[2*i+1], strongest)
+		o.Constraints[n] = k
+		n++
+	}
+	o.Constraints = o.Constraints[:n]
+}
+
+// load is force from 0 to 1, against maxForce when the constraint has
+// one, or else against the strongest force of the frame.
+func load(force, maxForce, strongest float64) float64 {
+	if maxForce > 0 && maxForce < cp.INFINITY {
+		return cp.Clamp01(force / maxForce)
+	}
+	if strongest <= 0 {
+		return 0
+	}
+	return cp.Clamp01(force / strongest)
+}
+
+// Ramp returns a colour from green at 0 through yellow to red at 1.
+func Ramp(t float64) cp.FColor {
+	t = cp.Clamp01(t)
+	if t < 0.5 {
+		return cp.FColor{R: float32(t * 2), G: 1, B: 0, A: 1}
+	}
+	return cp.FColor{R: 1, G: float32(2 - t*2), B: 0, A: 1}
+}
@@ -0,0 +1,121 @@
+package ebitencp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// OverlayKeys are the keys HandleOverlayKeys toggles the layers of the
+// overlay with.
+var OverlayKeys = map[ebiten.Key]Layer{
+	ebiten.KeyF1: LayerBodies,
+	ebiten.KeyF2: LayerContacts,
+	ebiten.KeyF3: LayerBounds,
+	ebiten.KeyF4: LayerConstraints,
+	ebiten.KeyF5: LayerSleeping,
+	ebiten.KeyF6: LayerHUD,
+}
+
+const (
+	// velocityScale is how long a velocity arrow is per unit of speed.
+	velocityScale = 0.1
+	// impulseScale is how long a contact normal is per unit of impulse.
+	impulseScale = 0.05
+	// spinRadius is the radius of the arc showing the angular velocity,
+	// which sweeps a radian per radian a second.
+	spinRadius = 12
+	// arrowHead is the length of the head of an arrow.
+	arrowHead = 5
+)
+
+var (
+	velocityColor = cp.FColor{R: 0.2, G: 0.6, B: 1, A: 1}
+	spinColor     = cp.FColor{R: 1, G: 0.4, B: 1, A: 1}
+	contactColor  = cp.FColor{R: 1, G: 0.3, B: 0.3, A: 1}
+	boundsColor   = cp.FColor{R: 0.5, G: 0.5, B: 0.5, A: 0.6}
+	sleepingColor = cp.FColor{R: 0.4, G: 0.4, B: 1, A: 1}
+)
+
+// HandleOverlayKeys toggles the layers of the overlay with OverlayKeys.
+func (d *Drawer) HandleOverlayKeys() {
+	for key, layer := range OverlayKeys {
+		if inpututil.IsKeyJustPressed(key) {
+			d.Overlay ^= layer
+		}
+	}
+}
+
+// DrawOverlay draws the layers of the overlay that are on over what is
+// already on Screen.
+func (d *Drawer) DrawOverlay(space *cp.Space) {
+	if d.Overlay == 0 {
+		return
+	}
+	o := &d.overlay
+	o.Collect(space)
+
+	if d.Overlay&LayerBounds != 0 {
+		for _, bb := range o.Bounds {
+			d.points = append(d.points[:0],
+				d.toScreen(cp.Vector{X: bb.L, Y: bb.B}),
+				d.toScreen(cp.Vector{X: bb.R, Y: bb.B}),
+				d.toScreen(cp.Vector{X: bb.R, Y: bb.T}),
+				d.toScreen(cp.Vector{X: bb.L, Y: bb.T}))
+			d.strokeLoop(d.points, boundsColor)
+		}
+	}
+	if d.Overlay&LayerConstraints != 0 {
+		for _, c := range o.Constraints {
+			d.strokeLine(d.toScreen(c.A), d.toScreen(c.B), Ramp(c.Load))
+		}
+	}
+	if d.Overlay&LayerContacts != 0 {
+		for _, c := range o.Contacts {
+			d.arrow(c.Point, c.Point.Add(c.Normal.Mult(c.Impulse*impulseScale)), contactColor)
+		}
+	}
+	for _, b := range o.Bodies {
+		if d.Overlay&LayerSleeping != 0 && b.Sleeping {
+			d.points = d.arc(d.points[:0], b.Center, spinRadius+4, 0, 2*math.Pi)
+			d.strokeLoop(d.points[:len(d.points)-1], sleepingColor)
+		}
+		if d.Overlay&LayerBodies == 0 {
+			continue
+		}
+		d.DrawDot(4, b.Center, velocityColor, nil)
+		d.arrow(b.Center, b.Center.Add(b.Velocity.Mult(velocityScale)), velocityColor)
+		if spin := cp.Clamp(b.AngularVelocity, -2*math.Pi, 2*math.Pi); spin != 0 {
+			d.points = d.arc(d.points[:0], b.Center, spinRadius, 0, spin)
+			for i := 1; i < len(d.points); i++ {
+				d.strokeLine(d.points[i-1], d.points[i], spinColor)
+			}
+		}
+	}
+	d.Flush()
+
+	if d.Overlay&LayerHUD != 0 && d.Screen != nil {
+		ebitenutil.DebugPrintAt(d.Screen, fmt.Sprintf(
+			"bodies: %d\nshapes: %d\nconstraints: %d\narbiters: %d",
+			o.Counts.Bodies, o.Counts.Shapes, o.Counts.Constraints, o.Counts.Arbiters,
+		), 0, d.ScreenHeight-4*16)
+	}
+}
+
+// arrow adds an arrow of the space from a to b.
+func (d *Drawer) arrow(a, b cp.Vector, c cp.FColor) {
+	dir := b.Sub(a)
+	length := dir.Length()
+	if length == 0 {
+		return
+	}
+	head := dir.Mult(-math.Min(arrowHead, length) / length)
+	tip := d.toScreen(b)
+	d.strokeLine(d.toScreen(a), tip, c)
+	d.strokeLine(tip, d.toScreen(b.Add(head.Rotate(cp.ForAngle(math.Pi/6)))), c)
+	d.strokeLine(tip, d.toScreen(b.Add(head.Rotate(cp.ForAngle(-math.Pi/6)))), c)
+}
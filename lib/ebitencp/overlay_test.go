@@ -0,0 +1,166 @@
+package ebitencp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// overlayScene returns a space with two balls pinned together running
+// into each other and a third asleep on its own, stepped once.
+func overlayScene() (*cp.Space, []*cp.Body) {
+	space := cp.NewSpace()
+	// the ball at rest falls asleep at the first step
+	space.SleepTimeThreshold = 1e-3
+	var bodies []*cp.Body
+	for _, p := range []cp.Vector{{X: -5}, {X: 5}, {X: 100}} {
+		b := space.AddBody(cp.NewBody(1, cp.MomentForCircle(1, 0, 10, cp.Vector{})))
+		b.SetPosition(p)
+		space.AddShape(cp.NewCircle(b, 10, cp.Vector{}))
+		bodies = append(bodies, b)
+	}
+	bodies[0].SetVelocity(10, 0)
+	bodies[1].SetVelocity(-10, 0)
+	bodies[1].SetAngularVelocity(2)
+	space.AddConstraint(cp.NewPinJoint(bodies[0], bodies[1], cp.Vector{}, cp.Vector{}))
+	space.Step(1 / 60.0)
+	return space, bodies
+}
+
+func TestCollect(t *testing.T) {
+	space, bodies := overlayScene()
+	var o Overlay
+	o.Collect(space)
+
+	want := OverlayCounts{Bodies: 3, Shapes: 3, Constraints: 1, Arbiters: 1}
+	if o.Counts != want {
+		t.Errorf("counts %+v, want %+v", o.Counts, want)
+	}
+
+	if len(o.Bodies) != 3 {
+		t.Fatalf("%d bodies", len(o.Bodies))
+	}
+	for i, b := range o.Bodies {
+		if b.Center != bodies[i].Position() {
+			t.Errorf("body %d centred at %v, want %v", i, b.Center, bodies[i].Position())
+		}
+		if b.Velocity != bodies[i].Velocity() {
+			t.Errorf("body %d moves at %v, want %v", i, b.Velocity, bodies[i].Velocity())
+		}
+		if b.Sleeping != (i == 2) {
+			t.Errorf("body %d sleeping: %v", i, b.Sleeping)
+		}
+	}
+	if o.Bodies[1].AngularVelocity != 2 {
+		t.Errorf("body 1 spins at %v, want 2", o.Bodies[1].AngularVelocity)
+	}
+
+	if len(o.Contacts) != 1 {
+		t.Fatalf("%d contacts, want 1", len(o.Contacts))
+	}
+	c := o.Contacts[0]
+	if c.Normal.Distance(cp.Vector{X: 1}) > 1e-9 {
+		t.Errorf("contact normal %v, want (1, 0)", c.Normal)
+	}
+	mid := bodies[0].Position().Lerp(bodies[1].Position(), 0.5)
+	if c.Point.Distance(mid) > 1e-9 {
+		t.Errorf("contact at %v, want %v", c.Point, mid)
+	}
+	if c.Impulse <= 0 {
+		t.Errorf("contact impulse %v", c.Impulse)
+	}
+
+	if len(o.Bounds) != 3 {
+		t.Fatalf("%d bounds", len(o.Bounds))
+	}
+	p := bodies[2].Position()
+	if bb := o.Bounds[2]; bb != (cp.BB{L: p.X - 10, B: p.Y - 10, R: p.X + 10, T: p.Y + 10}) {
+		t.Errorf("bounds of body 2 %v", bb)
+	}
+
+	if len(o.Constraints) != 1 {
+		t.Fatalf("%d constraints", len(o.Constraints))
+	}
+	a, b := bodies[0].Position(), bodies[1].Position()
+	if k := o.Constraints[0]; !(k.A == a && k.B == b) && !(k.A == b && k.B == a) {
+		t.Errorf("constraint from %v to %v, want between %v and %v", k.A, k.B, a, b)
+	}
+}
+
+func TestCollectBodyOutsideSpace(t *testing.T) {
+	space, bodies := overlayScene()
+	// a mouse grab pulls with a joint to a body that isn't in the space
+	grab := cp.NewKinematicBody()
+	grab.SetPosition(cp.Vector{X: 100, Y: 50})
+	space.AddConstraint(cp.NewPivotJoint2(grab, bodies[2], cp.Vector{}, cp.Vector{}))
+
+	var o Overlay
+	o.Collect(space)
+	if o.Counts.Constraints != 2 {
+		t.Errorf("%d constraints counted, want 2", o.Counts.Constraints)
+	}
+	if len(o.Constraints) != 1 {
+		t.Fatalf("%d constraints, want the pin joint only", len(o.Constraints))
+	}
+	a, b := bodies[0].Position(), bodies[1].Position()
+	if k := o.Constraints[0]; !(k.A == a && k.B == b) && !(k.A == b && k.B == a) {
+		t.Errorf("constraint from %v to %v, want between %v and %v", k.A, k.B, a, b)
+	}
+}
+
+func TestCollectReuses(t *testing.T) {
+	space, _ := overlayScene()
+	var o Overlay
+	o.Collect(space)
+	o.Collect(space)
+	if o.Counts.Bodies != 3 || len(o.Bodies) != 3 || len(o.Contacts) != 1 {
+		t.Errorf("collecting twice gives %+v, %d bodies, %d contacts", o.Counts, len(o.Bodies), len(o.Contacts))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	for _, tt := range []struct {
+		force, maxForce, strongest, want float64
+	}{
+		{50, 100, 400, 0.5},
+		{200, 100, 400, 1},
+		{100, cp.INFINITY, 400, 0.25},
+		{100, cp.INFINITY, 0, 0},
+		{0, cp.INFINITY, 0, 0},
+	} {
+		if got := load(tt.force, tt.maxForce, tt.strongest); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("load(%v, %v, %v) = %v, want %v", tt.force, tt.maxForce, tt.strongest, got, tt.want)
+		}
+	}
+}
+
+func TestRamp(t *testing.T) {
+	for _, tt := range []struct {
+		t    float64
+		want cp.FColor
+	}{
+		{0, cp.FColor{G: 1, A: 1}},
+		{0.5, cp.FColor{R: 1, G: 1, A: 1}},
+		{1, cp.FColor{R: 1, A: 1}},
+		{2, cp.FColor{R: 1, A: 1}},
+	} {
+		if got := Ramp(tt.t); got != tt.want {
+			t.Errorf("Ramp(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestDrawOverlayOff(t *testing.T) {
+	space, _ := overlayScene()
+	d := NewDrawer(200, 200)
+	d.DrawOverlay(space)
+	if d.calls != 0 {
+		t.Errorf("%d calls with the overlay off", d.calls)
+	}
+	d.Overlay = LayerAll
+	d.DrawOverlay(space)
+	if d.calls != 1 {
+		t.Errorf("%d calls, want 1", d.calls)
+	}
+}
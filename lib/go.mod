@@ -0,0 +1,3 @@
+module github.com/demouth/ebitengine-sketch/lib
+
+go 1.21.0
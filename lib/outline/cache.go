@@ -0,0 +1,73 @@
+package outline
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// document is the JSON form of an outline. Vertices are [x, y] pairs.
+type document struct {
+	Width  int            `json:"width"`
+	Height int            `json:"height"`
+	Pieces [][][2]float64 `json:"pieces"`
+}
+
+// Marshal writes outlines by name, as Load reads them.
+func Marshal(outlines map[string]*Outline) ([]byte, error) {
+	docs := map[string]document{}
+	for name, o := range outlines {
+		d := document{Width: o.Width, Height: o.Height, Pieces: [][][2]float64{}}
+		for _, p := range o.Pieces {
+			piece := make([][2]float64, len(p))
+			for i, v := range p {
+				piece[i] = [2]float64{v.X, v.Y}
+			}
+			d.Pieces = append(d.Pieces, piece)
+		}
+		docs[name] = d
+	}
+	return json.MarshalIndent(docs, "", "  ")
+}
+
+// Load reads outlines by name. Every piece has to be convex and
+// counter-clockwise, so that a hand edited file can't hand Chipmunk2D a
+// shape it would quietly turn into its convex hull.
+func Load(data []byte) (map[string]*Outline, error) {
+	var docs map[string]document
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return nil, fmt.Errorf("outline: %w", err)
+	}
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	// report the first bad outline by name, whatever the map order
+	sort.Strings(names)
+
+	outlines := map[string]*Outline{}
+	for _, name := range names {
+		d := docs[name]
+		if d.Width <= 0 || d.Height <= 0 {
+			return nil, fmt.Errorf("outline: %s is %dx%d", name, d.Width, d.Height)
+		}
+		if len(d.Pieces) == 0 {
+			return nil, fmt.Errorf("outline: %s has no piece", name)
+		}
+		o := &Outline{Width: d.Width, Height: d.Height}
+		for i, piece := range d.Pieces {
+			p := make([]cp.Vector, len(piece))
+			for j, v := range piece {
+				p[j] = cp.Vector{X: v[0], Y: v[1]}
+			}
+			if !Convex(p) {
+				return nil, fmt.Errorf("outline: piece %d of %s is not convex and counter-clockwise", i, name)
+			}
+			o.Pieces = append(o.Pieces, p)
+		}
+		outlines[name] = o
+	}
+	return outlines, nil
+}
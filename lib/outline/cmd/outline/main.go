@@ -0,0 +1,72 @@
+// Command outline traces PNG files into the JSON that outline.Load reads.
+//
+//	go run github.com/demouth/ebitengine-sketch/lib/outline/cmd/outline -o outlines.json apple.png kiwi.png
+//
+// Outlines are keyed by the base name of the file without its extension.
+// The coverage of every outline is printed, so that a tolerance that cuts
+// off too much shows up.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	_ "image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/demouth/ebitengine-sketch/lib/outline"
+)
+
+func main() {
+	out := flag.String("o", "outlines.json", "file to write")
+	threshold := flag.Float64("threshold", 0.5, "alpha above which a pixel is opaque")
+	tolerance := flag.Float64("tolerance", 1, "how far in pixels the outline may stray when simplified")
+	flag.Parse()
+	log.SetFlags(0)
+
+	opts := outline.Options{Threshold: *threshold, Tolerance: *tolerance}
+	outlines := map[string]*outline.Outline{}
+	for _, path := range flag.Args() {
+		img, err := decode(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		o, err := outline.Trace(img, opts)
+		if err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		outlines[name] = o
+
+		verts := 0
+		for _, p := range o.Pieces {
+			verts += len(p)
+		}
+		fmt.Fprintf(os.Stderr, "%s: %d pieces, %d vertices, %.1f%% covered\n",
+			name, len(o.Pieces), verts, o.Coverage(img, *threshold)*100)
+	}
+
+	data, err := outline.Marshal(outlines)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func decode(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return img, nil
+}
@@ -0,0 +1,313 @@
+package outline
+
+import (
+	"errors"
+	"math"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// eps is how far from 0 a cross product can be and still count as 0. The
+// coordinates are pixels, so rounding errors are far below it.
+const eps = 1e-9
+
+// area returns the signed area of a polygon, positive when it winds
+// counter-clockwise.
+func area(poly []cp.Vector) float64 {
+	a := 0.0
+	for i := range poly {
+		a += poly[i].Cross(poly[(i+1)%len(poly)])
+	}
+	return a / 2
+}
+
+// turn is positive when a, b, c turn left.
+func turn(a, b, c cp.Vector) float64 {
+	return b.Sub(a).Cross(c.Sub(b))
+}
+
+func reverse(poly []cp.Vector) {
+	for i, j := 0, len(poly)-1; i < j; i, j = i+1, j-1 {
+		poly[i], poly[j] = poly[j], poly[i]
+	}
+}
+
+// CCW reports whether poly winds counter-clockwise.
+func CCW(poly []cp.Vector) bool {
+	return len(poly) >= 3 && area(poly) > 0
+}
+
+// Convex reports whether poly is a convex polygon wound
+// counter-clockwise. Collinear vertices are allowed.
+func Convex(poly []cp.Vector) bool {
+	if !CCW(poly) {
+		return false
+	}
+	n := len(poly)
+	for i := range poly {
+		if turn(poly[(i+n-1)%n], poly[i], poly[(i+1)%n]) < -eps {
+			return false
+		}
+	}
+	// a star also turns left at every vertex, but goes round more than
+	// once
+	total := 0.0
+	for i := range poly {
+		a := poly[(i+1)%n].Sub(poly[i])
+		b := poly[(i+2)%n].Sub(poly[(i+1)%n])
+		total += math.Atan2(a.Cross(b), a.Dot(b))
+	}
+	return total < 3*math.Pi
+}
+
+// inConvex reports whether p is inside or on the edge of the convex,
+// counter-clockwise poly.
+func inConvex(p cp.Vector, poly []cp.Vector) bool {
+	for i := range poly {
+		if turn(poly[i], poly[(i+1)%len(poly)], p) < -eps {
+			return false
+		}
+	}
+	return true
+}
+
+// clean drops repeated and collinear vertices.
+func clean(poly []cp.Vector) []cp.Vector {
+	out := append([]cp.Vector(nil), poly...)
+	for changed := true; changed && len(out) >= 3; {
+		changed = false
+		for i := 0; i < len(out) && len(out) >= 3; i++ {
+			n := len(out)
+			a, b, c := out[(i+n-1)%n], out[i], out[(i+1)%n]
+			if a == b || math.Abs(turn(a, b, c)) <= eps {
+				out = append(out[:i], out[i+1:]...)
+				changed = true
+				i--
+			}
+		}
+	}
+	return out
+}
+
+// simplify drops the vertices of ring that are within tol of the
+// outline without them (Ramer-Douglas-Peucker). When that makes the
+// outline cross itself, it tries again with half the tolerance.
+func simplify(ring []cp.Vector, tol float64) []cp.Vector {
+	for ; tol > 0.01; tol /= 2 {
+		s := clean(simplifyRing(ring, tol))
+		if len(s) >= 3 && area(s) > 0 && !selfIntersects(s) {
+			return s
+		}
+	}
+	return ring
+}
+
+func simplifyRing(ring []cp.Vector, tol float64) []cp.Vector {
+	// split the ring at the vertex farthest from the first one, and
+	// simplify both halves as open lines
+	far := 0
+	for i := range ring {
+		if ring[i].DistanceSq(ring[0]) > ring[far].DistanceSq(ring[0]) {
+			far = i
+		}
+	}
+	if far == 0 {
+		return ring
+	}
+	a := simplifyLine(ring[:far+1], tol)
+	b := simplifyLine(append(append([]cp.Vector(nil), ring[far:]...), ring[0]), tol)
+	return append(a[:len(a)-1], b[:len(b)-1]...)
+}
+
+func simplifyLine(line []cp.Vector, tol float64) []cp.Vector {
+	if len(line) < 3 {
+		return append([]cp.Vector(nil), line...)
+	}
+	first, last := line[0], line[len(line)-1]
+	far, dist := 0, -1.0
+	for i := 1; i < len(line)-1; i++ {
+		if d := distance(line[i], first, last); d > dist {
+			far, dist = i, d
+		}
+	}
+	if dist <= tol {
+		return []cp.Vector{first, last}
+	}
+	a := simplifyLine(line[:far+1], tol)
+	b := simplifyLine(line[far:], tol)
+	return append(a[:len(a)-1], b...)
+}
+
+// distance returns how far p is from the segment ab.
+func distance(p, a, b cp.Vector) float64 {
+	ab := b.Sub(a)
+	t := 0.0
+	if l := ab.LengthSq(); l > 0 {
+		t = cp.Clamp01(p.Sub(a).Dot(ab) / l)
+	}
+	return p.Distance(a.Add(ab.Mult(t)))
+}
+
+// selfIntersects reports whether two edges of ring that don't follow each
+// other touch.
+func selfIntersects(ring []cp.Vector) bool {
+	n := len(ring)
+	for i := 0; i < n; i++ {
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+			if intersects(ring[i], ring[(i+1)%n], ring[j], ring[(j+1)%n]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func intersects(a, b, c, d cp.Vector) bool {
+	d1, d2 := turn(c, d, a), turn(c, d, b)
+	d3, d4 := turn(a, b, c), turn(a, b, d)
+	if (d1 > eps && d2 < -eps || d1 < -eps && d2 > eps) &&
+		(d3 > eps && d4 < -eps || d3 < -eps && d4 > eps) {
+		return true
+	}
+	on := func(p, a, b cp.Vector) bool {
+		return math.Abs(turn(a, b, p)) <= eps &&
+			p.X >= math.Min(a.X, b.X)-eps && p.X <= math.Max(a.X, b.X)+eps &&
+			p.Y >= math.Min(a.Y, b.Y)-eps && p.Y <= math.Max(a.Y, b.Y)+eps
+	}
+	return on(a, c, d) || on(b, c, d) || on(c, a, b) || on(d, a, b)
+}
+
+var errNotSimple = errors.New("outline: the outline crosses itself or winds clockwise")
+
+// decompose cuts a simple, counter-clockwise ring into convex pieces: it
+// clips ears into triangles, then joins neighbours while the result stays
+// convex (Hertel-Mehlhorn).
+func decompose(ring []cp.Vector) ([][]cp.Vector, error) {
+	if area(ring) <= 0 || selfIntersects(ring) {
+		return nil, errNotSimple
+	}
+	tris, err := triangulate(ring)
+	if err != nil {
+		return nil, err
+	}
+	pieces := merge(ring, tris)
+	out := make([][]cp.Vector, 0, len(pieces))
+	for _, p := range pieces {
+		poly := make([]cp.Vector, len(p))
+		for i, v := range p {
+			poly[i] = ring[v]
+		}
+		if poly = clean(poly); len(poly) >= 3 {
+			out = append(out, poly)
+		}
+	}
+	return out, nil
+}
+
+// triangulate returns the triangles of ring as indices into it.
+func triangulate(ring []cp.Vector) ([][]int, error) {
+	idx := make([]int, len(ring))
+	for i := range idx {
+		idx[i] = i
+	}
+	var tris [][]int
+	for len(idx) > 3 {
+		n := len(idx)
+		clipped := false
+		for i := 0; i < n && !clipped; i++ {
+			p, c, q := idx[(i+n-1)%n], idx[i], idx[(i+1)%n]
+			if turn(ring[p], ring[c], ring[q]) <= eps || !isEar(ring, idx, p, c, q) {
+				continue
+			}
+			tris = append(tris, []int{p, c, q})
+			idx = append(idx[:i], idx[i+1:]...)
+			clipped = true
+		}
+		if clipped {
+			continue
+		}
+		// what is left may only have flat corners, which are no ears
+		for i := 0; i < n && !clipped; i++ {
+			p, c, q := idx[(i+n-1)%n], idx[i], idx[(i+1)%n]
+			if math.Abs(turn(ring[p], ring[c], ring[q])) <= eps {
+				idx = append(idx[:i], idx[i+1:]...)
+				clipped = true
+			}
+		}
+		if !clipped {
+			return nil, errNotSimple
+		}
+	}
+	if len(idx) == 3 && turn(ring[idx[0]], ring[idx[1]], ring[idx[2]]) > eps {
+		tris = append(tris, idx)
+	}
+	return tris, nil
+}
+
+// isEar reports whether no other vertex left in idx is in the triangle
+// p, c, q.
+func isEar(ring []cp.Vector, idx []int, p, c, q int) bool {
+	tri := []cp.Vector{ring[p], ring[c], ring[q]}
+	for _, i := range idx {
+		if i == p || i == c || i == q {
+			continue
+		}
+		v := ring[i]
+		if v == tri[0] || v == tri[1] || v == tri[2] {
+			continue
+		}
+		if inConvex(v, tri) {
+			return false
+		}
+	}
+	return true
+}
+
+// merge joins pieces that share an edge while the result is convex.
+func merge(ring []cp.Vector, pieces [][]int) [][]int {
+	for merged := true; merged; {
+		merged = false
+	search:
+		for i := range pieces {
+			for j := i + 1; j < len(pieces); j++ {
+				if m, ok := join(ring, pieces[i], pieces[j]); ok {
+					pieces[i] = m
+					pieces = append(pieces[:j], pieces[j+1:]...)
+					merged = true
+					break search
+				}
+			}
+		}
+	}
+	return pieces
+}
+
+// join returns a and b as one piece, when they share an edge and the
+// piece is convex.
+func join(ring []cp.Vector, a, b []int) ([]int, bool) {
+	for i := range a {
+		u, v := a[i], a[(i+1)%len(a)]
+		for j := range b {
+			if b[j] != v || b[(j+1)%len(b)] != u {
+				continue
+			}
+			// a from v round to u, then b from after u round to before v
+			m := make([]int, 0, len(a)+len(b)-2)
+			for k := 0; k < len(a); k++ {
+				m = append(m, a[(i+1+k)%len(a)])
+			}
+			for k := 2; k < len(b); k++ {
+				m = append(m, b[(j+k)%len(b)])
+			}
+			poly := make([]cp.Vector, len(m))
+			for k, v := range m {
+				poly[k] = ring[v]
+			}
+			return m, Convex(poly)
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,123 @@
+package outline
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+func poly(xy ...float64) []cp.Vector {
+	p := make([]cp.Vector, 0, len(xy)/2)
+	for i := 0; i < len(xy); i += 2 {
+		p = append(p, cp.Vector{X: xy[i], Y: xy[i+1]})
+	}
+	return p
+}
+
+func TestConvex(t *testing.T) {
+	tests := []struct {
+		name string
+		poly []cp.Vector
+		want bool
+	}{
+		{"triangle", poly(0, 0, 1, 0, 0, 1), true},
+		{"clockwise", poly(0, 0, 0, 1, 1, 0), false},
+		{"square", poly(0, 0, 1, 0, 1, 1, 0, 1), true},
+		{"collinear", poly(0, 0, 1, 0, 2, 0, 2, 2, 0, 2), true},
+		{"dent", poly(0, 0, 4, 0, 2, 1, 4, 4, 0, 4), false},
+		{"flat", poly(0, 0, 1, 0, 2, 0), false},
+		// a pentagram turns left at every vertex
+		{"pentagram", poly(0, 10, -5.9, -8.1, 9.5, 3.1, -9.5, 3.1, 5.9, -8.1), false},
+	}
+	for _, tt := range tests {
+		if got := Convex(tt.poly); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	tests := []struct {
+		name string
+		ring []cp.Vector
+		// max is the most pieces the ring should be cut into
+		max int
+	}{
+		{"square", poly(0, 0, 2, 0, 2, 2, 0, 2), 1},
+		{"l", poly(0, 0, 3, 0, 3, 1, 1, 1, 1, 3, 0, 3), 2},
+		{"u", poly(0, 0, 3, 0, 3, 3, 2, 3, 2, 1, 1, 1, 1, 3, 0, 3), 3},
+		{"comb", poly(0, 0, 5, 0, 5, 3, 4, 3, 4, 1, 3, 1, 3, 3, 2, 3, 2, 1, 1, 1, 1, 3, 0, 3), 5},
+		{"collinear", poly(0, 0, 1, 0, 2, 0, 2, 2, 1, 2, 0, 2), 1},
+	}
+	for _, tt := range tests {
+		pieces, err := decompose(tt.ring)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(pieces) == 0 || len(pieces) > tt.max {
+			t.Errorf("%s: %d pieces, want 1 to %d", tt.name, len(pieces), tt.max)
+		}
+		sum := 0.0
+		for i, p := range pieces {
+			if !Convex(p) {
+				t.Errorf("%s: piece %d is not convex: %v", tt.name, i, p)
+			}
+			sum += area(p)
+		}
+		if want := area(tt.ring); math.Abs(sum-want) > 1e-9 {
+			t.Errorf("%s: pieces add up to %v, want %v", tt.name, sum, want)
+		}
+	}
+}
+
+func TestDecomposeNotSimple(t *testing.T) {
+	// a bow tie
+	if _, err := decompose(poly(0, 0, 2, 2, 2, 0, 0, 2)); err == nil {
+		t.Errorf("no error")
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	// a square with a wobbly edge
+	var ring []cp.Vector
+	for x := 0.0; x < 20; x++ {
+		ring = append(ring, cp.Vector{X: x, Y: 0.2 * math.Sin(x)})
+	}
+	ring = append(ring, poly(20, 0, 20, 20, 0, 20)...)
+
+	s := simplify(ring, 0.5)
+	if len(s) != 4 {
+		t.Errorf("%d vertices, want 4: %v", len(s), s)
+	}
+	if !CCW(s) {
+		t.Errorf("not counter-clockwise: %v", s)
+	}
+}
+
+func TestSimplifyNoCrossing(t *testing.T) {
+	// a narrow slit that a coarse tolerance would close across the
+	// opposite side
+	ring := poly(0, 0, 10, 0, 10, 10, 5.2, 10, 5.2, 1, 4.8, 1, 4.8, 10, 0, 10)
+	s := simplify(ring, 5)
+	if selfIntersects(s) {
+		t.Errorf("the simplified ring crosses itself: %v", s)
+	}
+	if !CCW(s) {
+		t.Errorf("not counter-clockwise: %v", s)
+	}
+}
+
+func TestSelfIntersects(t *testing.T) {
+	if selfIntersects(poly(0, 0, 1, 0, 1, 1, 0, 1)) {
+		t.Errorf("square")
+	}
+	if !selfIntersects(poly(0, 0, 2, 2, 2, 0, 0, 2)) {
+		t.Errorf("bow tie")
+	}
+	// an hourglass pinched at one vertex
+	if !selfIntersects(poly(0, 0, 2, 0, 1, 1, 2, 2, 0, 2, 1, 1)) {
+		t.Errorf("touching")
+	}
+}
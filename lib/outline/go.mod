@@ -0,0 +1,5 @@
+module github.com/demouth/ebitengine-sketch/lib/outline
+
+go 1.21.5
+
+require github.com/jakecoffman/cp/v2 v2.0.2
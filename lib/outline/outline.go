@@ -0,0 +1,174 @@
+// Package outline turns the opaque part of an image into convex polygons
+// that Chipmunk2D can collide.
+//
+// cp.NewPolyShape takes the convex hull of the vertices it is given, so a
+// concave outline straight out of cp.MarchSoft fills in its dents, and a
+// simplified outline can cross itself. Trace marches the alpha channel,
+// simplifies the outline without letting it cross itself and cuts it into
+// convex pieces. It is slow enough that sketches run it once, through the
+// outline command, and embed the JSON it writes.
+package outline
+
+import (
+	"errors"
+	"image"
+	"math"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// ErrEmpty is returned for an image with no opaque pixel.
+var ErrEmpty = errors.New("outline: no opaque pixel")
+
+type Options struct {
+	// Threshold is the alpha, from 0 to 1, above which a pixel is opaque.
+	// 0 means 0.5.
+	Threshold float64
+	// Tolerance is how far, in pixels, the simplified outline may stray
+	// from the marched one. 0 means 1.
+	Tolerance float64
+}
+
+func (o Options) threshold() float64 {
+	if o.Threshold <= 0 {
+		return 0.5
+	}
+	return o.Threshold
+}
+
+func (o Options) tolerance() float64 {
+	if o.Tolerance <= 0 {
+		return 1
+	}
+	return o.Tolerance
+}
+
+// Outline is the opaque part of an image as convex pieces.
+type Outline struct {
+	// Width and Height are the size of the traced image.
+	Width  int
+	Height int
+	// Pieces are convex polygons wound counter-clockwise, that is with a
+	// positive area as cp.AreaForPoly counts it, in the pixel coordinates
+	// of the image.
+	Pieces [][]cp.Vector
+}
+
+// Trace outlines the largest opaque area of img. Holes and smaller
+// islands are left out.
+func Trace(img image.Image, opts Options) (*Outline, error) {
+	ring := march(img, opts.threshold())
+	if len(ring) < 3 {
+		return nil, ErrEmpty
+	}
+	ring = simplify(ring, opts.tolerance())
+	pieces, err := decompose(ring)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	o := &Outline{Width: b.Dx(), Height: b.Dy()}
+	for _, p := range pieces {
+		// the origin is the top left corner of the image, whatever its
+		// bounds are
+		for i := range p {
+			p[i] = p[i].Sub(cp.Vector{X: float64(b.Min.X), Y: float64(b.Min.Y)})
+		}
+		o.Pieces = append(o.Pieces, p)
+	}
+	return o, nil
+}
+
+// march returns the loop that encloses the most pixels of img whose alpha
+// is above threshold, counter-clockwise and without the closing vertex.
+func march(img image.Image, threshold float64) []cp.Vector {
+	b := img.Bounds()
+	// one sample at the centre of every pixel and a transparent border, so
+	// that every line closes
+	bb := cp.BB{
+		L: float64(b.Min.X) - 0.5,
+		B: float64(b.Min.Y) - 0.5,
+		R: float64(b.Max.X) + 0.5,
+		T: float64(b.Max.Y) + 0.5,
+	}
+	sample := func(p cp.Vector) float64 {
+		pt := image.Pt(int(math.Floor(p.X)), int(math.Floor(p.Y)))
+		if !pt.In(b) {
+			return 0
+		}
+		_, _, _, a := img.At(pt.X, pt.Y).RGBA()
+		return float64(a) / 0xffff
+	}
+	set := cp.MarchSoft(bb, int64(b.Dx()+2), int64(b.Dy()+2), threshold, cp.PolyLineCollectSegment, sample)
+
+	var ring []cp.Vector
+	best := 0.0
+	for _, l := range set.Lines {
+		v := l.Verts
+		if len(v) > 1 && v[0] == v[len(v)-1] {
+			v = v[:len(v)-1]
+		}
+		v = clean(v)
+		if len(v) < 3 {
+			continue
+		}
+		if a := math.Abs(area(v)); a > best {
+			best = a
+			ring = v
+		}
+	}
+	if area(ring) < 0 {
+		reverse(ring)
+	}
+	return ring
+}
+
+// Centered returns the pieces moved so that the centre of the image is at
+// the origin, then scaled.
+func (o *Outline) Centered(scale float64) [][]cp.Vector {
+	c := cp.Vector{X: float64(o.Width) / 2, Y: float64(o.Height) / 2}
+	pieces := make([][]cp.Vector, len(o.Pieces))
+	for i, p := range o.Pieces {
+		pieces[i] = make([]cp.Vector, len(p))
+		for j, v := range p {
+			pieces[i][j] = v.Sub(c).Mult(scale)
+		}
+	}
+	return pieces
+}
+
+// Area returns the area of the pieces.
+func (o *Outline) Area() float64 {
+	a := 0.0
+	for _, p := range o.Pieces {
+		a += area(p)
+	}
+	return a
+}
+
+// Coverage returns the share of the pixels of img whose alpha is above
+// threshold that have their centre in one of the pieces.
+func (o *Outline) Coverage(img image.Image, threshold float64) float64 {
+	b := img.Bounds()
+	opaque, covered := 0, 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			_, _, _, a := img.At(x, y).RGBA()
+			if float64(a)/0xffff <= threshold {
+				continue
+			}
+			opaque++
+			p := cp.Vector{X: float64(x-b.Min.X) + 0.5, Y: float64(y-b.Min.Y) + 0.5}
+			for _, piece := range o.Pieces {
+				if inConvex(p, piece) {
+					covered++
+					break
+				}
+			}
+		}
+	}
+	if opaque == 0 {
+		return 0
+	}
+	return float64(covered) / float64(opaque)
+}
@@ -0,0 +1,228 @@
+package outline
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// minCoverage is the share of the opaque pixels that the pieces have to
+// cover with the default tolerance.
+const minCoverage = 0.95
+
+// shape draws an image of w by h pixels, opaque where in is true.
+func shape(w, h int, in func(x, y float64) bool) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if in(float64(x)+0.5, float64(y)+0.5) {
+				img.Set(x, y, color.NRGBA{0xff, 0x80, 0x00, 0xff})
+			}
+		}
+	}
+	return img
+}
+
+func shapes() map[string]image.Image {
+	return map[string]image.Image{
+		"disc": shape(64, 64, func(x, y float64) bool {
+			return math.Hypot(x-32, y-32) < 28
+		}),
+		"box": shape(40, 30, func(x, y float64) bool {
+			return x > 5 && x < 35 && y > 5 && y < 25
+		}),
+		// concave: the convex hull would fill in the gap
+		"crescent": shape(80, 80, func(x, y float64) bool {
+			return math.Hypot(x-40, y-40) < 35 && math.Hypot(x-55, y-40) > 25
+		}),
+		"star": shape(100, 100, func(x, y float64) bool {
+			a := math.Atan2(y-50, x-50)
+			return math.Hypot(x-50, y-50) < 30+15*math.Cos(5*a)
+		}),
+		"l": shape(50, 50, func(x, y float64) bool {
+			return x > 5 && x < 20 && y > 5 && y < 45 || x > 5 && x < 45 && y > 30 && y < 45
+		}),
+		"offset": func() image.Image {
+			img := image.NewNRGBA(image.Rect(10, 20, 50, 60))
+			for y := 25; y < 55; y++ {
+				for x := 15; x < 45; x++ {
+					img.Set(x, y, color.White)
+				}
+			}
+			return img
+		}(),
+	}
+}
+
+func TestTrace(t *testing.T) {
+	for name, img := range shapes() {
+		o, err := Trace(img, Options{})
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if len(o.Pieces) == 0 {
+			t.Errorf("%s: no piece", name)
+			continue
+		}
+		for i, p := range o.Pieces {
+			if !CCW(p) {
+				t.Errorf("%s: piece %d is not counter-clockwise: %v", name, i, p)
+			}
+			if !Convex(p) {
+				t.Errorf("%s: piece %d is not convex: %v", name, i, p)
+			}
+		}
+		if c := o.Coverage(img, 0.5); c < minCoverage {
+			t.Errorf("%s: %.1f%% of the opaque pixels covered, want %.0f%%", name, c*100, minCoverage*100)
+		}
+		if b := img.Bounds(); o.Width != b.Dx() || o.Height != b.Dy() {
+			t.Errorf("%s: %dx%d, want %dx%d", name, o.Width, o.Height, b.Dx(), b.Dy())
+		}
+	}
+}
+
+func TestTraceConcave(t *testing.T) {
+	img := shapes()["crescent"]
+	o, err := Trace(img, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(o.Pieces) < 2 {
+		t.Fatalf("%d pieces, want a concave outline cut up", len(o.Pieces))
+	}
+	// the bite out of the disc stays empty
+	p := cp.Vector{X: 60, Y: 40}
+	for i, piece := range o.Pieces {
+		if inConvex(p, piece) {
+			t.Errorf("piece %d covers %v", i, p)
+		}
+	}
+}
+
+func TestTraceIslands(t *testing.T) {
+	big := func(x, y float64) bool { return math.Hypot(x-15, y-15) < 10 }
+	img := shape(60, 30, func(x, y float64) bool {
+		return big(x, y) || math.Hypot(x-45, y-15) < 5
+	})
+	o, err := Trace(img, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the bigger island wins, the smaller one is left out
+	if c := o.Coverage(shape(60, 30, big), 0.5); c < minCoverage {
+		t.Errorf("%.1f%% of the bigger island covered", c*100)
+	}
+	for i, p := range o.Pieces {
+		if inConvex(cp.Vector{X: 45, Y: 15}, p) {
+			t.Errorf("piece %d covers the smaller island", i)
+		}
+	}
+}
+
+func TestTraceTolerance(t *testing.T) {
+	img := shapes()["disc"]
+	count := func(tol float64) int {
+		o, err := Trace(img, Options{Tolerance: tol})
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for _, p := range o.Pieces {
+			n += len(p)
+		}
+		return n
+	}
+	if fine, coarse := count(0.1), count(3); coarse >= fine {
+		t.Errorf("%d vertices with a tolerance of 3, %d with 0.1", coarse, fine)
+	}
+}
+
+func TestTraceEmpty(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	if _, err := Trace(img, Options{}); !errors.Is(err, ErrEmpty) {
+		t.Errorf("got %v, want ErrEmpty", err)
+	}
+}
+
+func TestCentered(t *testing.T) {
+	o := &Outline{
+		Width:  10,
+		Height: 20,
+		Pieces: [][]cp.Vector{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 20}}},
+	}
+	got := o.Centered(2)[0]
+	want := []cp.Vector{{X: -10, Y: -20}, {X: 10, Y: -20}, {X: 10, Y: 20}}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("vertex %d is %v, want %v", i, got[i], want[i])
+		}
+	}
+	if o.Pieces[0][0] != (cp.Vector{}) {
+		t.Errorf("Centered changed the outline")
+	}
+	if a := o.Area(); a != 100 {
+		t.Errorf("area %v, want 100", a)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	outlines := map[string]*Outline{}
+	for name, img := range shapes() {
+		o, err := Trace(img, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		outlines[name] = o
+	}
+	data, err := Marshal(outlines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, o := range outlines {
+		l, ok := loaded[name]
+		if !ok {
+			t.Errorf("%s is missing", name)
+			continue
+		}
+		if l.Width != o.Width || l.Height != o.Height || len(l.Pieces) != len(o.Pieces) {
+			t.Errorf("%s: %dx%d in %d pieces, want %dx%d in %d", name,
+				l.Width, l.Height, len(l.Pieces), o.Width, o.Height, len(o.Pieces))
+			continue
+		}
+		for i := range o.Pieces {
+			for j := range o.Pieces[i] {
+				if l.Pieces[i][j] != o.Pieces[i][j] {
+					t.Errorf("%s: piece %d vertex %d is %v, want %v", name, i, j, l.Pieces[i][j], o.Pieces[i][j])
+				}
+			}
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"json", `{`},
+		{"size", `{"a":{"width":0,"height":10,"pieces":[[[0,0],[1,0],[0,1]]]}}`},
+		{"no piece", `{"a":{"width":10,"height":10,"pieces":[]}}`},
+		{"clockwise", `{"a":{"width":10,"height":10,"pieces":[[[0,0],[0,1],[1,0]]]}}`},
+		{"concave", `{"a":{"width":10,"height":10,"pieces":[[[0,0],[4,0],[2,1],[4,4],[0,4]]]}}`},
+		{"two vertices", `{"a":{"width":10,"height":10,"pieces":[[[0,0],[1,0]]]}}`},
+	}
+	for _, tt := range tests {
+		if _, err := Load([]byte(tt.data)); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
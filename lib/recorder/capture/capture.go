@@ -0,0 +1,108 @@
+// Package capture turns RGBA frames into numbered PNGs or an animated GIF.
+// It doesn't depend on ebiten; the recorder package reads the frames back
+// from the GPU and feeds them here.
+package capture
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Clock picks which ticks of a game running at TPS become frames of a
+// recording at FPS. The recording plays at the speed of the game however
+// slow the capture is, because it counts ticks and not wall time.
+type Clock struct {
+	TPS int
+	FPS int
+
+	acc    int
+	frames int
+}
+
+// Tick advances the game by one tick and reports whether a frame should be
+// captured after it. The first tick is always captured, and every tick when
+// FPS is above TPS.
+func (c *Clock) Tick() bool {
+	if c.FPS <= 0 || c.TPS <= 0 {
+		return false
+	}
+	capture := c.acc <= 0
+	if capture {
+		c.acc += c.TPS
+		c.frames++
+	}
+	c.acc = max(c.acc-c.FPS, -c.FPS)
+	return capture
+}
+
+// Frames returns the number of captured frames.
+func (c *Clock) Frames() int {
+	return c.frames
+}
+
+// Duration returns the play time of the captured frames.
+func (c *Clock) Duration() time.Duration {
+	if c.FPS <= 0 {
+		return 0
+	}
+	return time.Duration(c.frames) * time.Second / time.Duration(c.FPS)
+}
+
+// Sink receives the frames of a recording.
+type Sink interface {
+	WriteFrame(img *image.RGBA) error
+	Close() error
+}
+
+// PNGSequence writes every frame to Dir as frame_00000.png, frame_00001.png
+// and so on.
+type PNGSequence struct {
+	Dir string
+
+	n int
+}
+
+func NewPNGSequence(dir string) (*PNGSequence, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &PNGSequence{Dir: dir}, nil
+}
+
+func (s *PNGSequence) Path(i int) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("frame_%05d.png", i))
+}
+
+func (s *PNGSequence) WriteFrame(img *image.RGBA) error {
+	f, err := os.Create(s.Path(s.n))
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	s.n++
+	return f.Close()
+}
+
+func (s *PNGSequence) Close() error {
+	return nil
+}
+
+// Paletted maps img to pal, with Floyd-Steinberg dithering if dither is true.
+func Paletted(img image.Image, pal color.Palette, dither bool) *image.Paletted {
+	p := image.NewPaletted(img.Bounds(), pal)
+	if dither {
+		draw.FloydSteinberg.Draw(p, p.Rect, img, img.Bounds().Min)
+	} else {
+		draw.Draw(p, p.Rect, img, img.Bounds().Min, draw.Src)
+	}
+	return p
+}
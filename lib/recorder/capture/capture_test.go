@@ -0,0 +1,284 @@
+package capture
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	tests := []struct {
+		tps, fps int
+		ticks    int
+		want     string
+	}{
+		{60, 20, 9, "x..x..x.."},
+		{60, 30, 6, "x.x.x."},
+		{60, 60, 3, "xxx"},
+		{60, 120, 3, "xxx"},
+		{60, 25, 12, "x..x.x..x.x."},
+		{60, 0, 3, "..."},
+	}
+	for _, tt := range tests {
+		c := Clock{TPS: tt.tps, FPS: tt.fps}
+		var got []byte
+		for i := 0; i < tt.ticks; i++ {
+			if c.Tick() {
+				got = append(got, 'x')
+			} else {
+				got = append(got, '.')
+			}
+		}
+		if string(got) != tt.want {
+			t.Errorf("Clock{%d, %d} ticks = %s, want %s", tt.tps, tt.fps, got, tt.want)
+		}
+	}
+
+	// one second of the game is one second of the recording
+	c := Clock{TPS: 60, FPS: 25}
+	for i := 0; i < 60*4; i++ {
+		c.Tick()
+	}
+	if c.Frames() != 25*4 || c.Duration() != 4*time.Second {
+		t.Errorf("after 4 s: Frames() = %d, Duration() = %v", c.Frames(), c.Duration())
+	}
+}
+
+// stripes returns an image with vertical stripes of the colors.
+func stripes(w, h int, colors ...color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, colors[x*len(colors)/w])
+		}
+	}
+	return img
+}
+
+func gradient(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(x * 255 / (w - 1)), uint8(y * 255 / (h - 1)), 0x80, 0xff})
+		}
+	}
+	return img
+}
+
+func TestQuantizeFewColors(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	green := color.RGBA{0, 0xff, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	img := stripes(30, 10, blue, red, green)
+
+	pal := Quantize(img, 16)
+	want := color.Palette{blue, green, red}
+	if len(pal) != len(want) {
+		t.Fatalf("Quantize() = %v, want %v", pal, want)
+	}
+	for i := range want {
+		if pal[i] != want[i] {
+			t.Errorf("Quantize()[%d] = %v, want %v", i, pal[i], want[i])
+		}
+	}
+
+	// exact colors map to themselves
+	p := Paletted(img, pal, false)
+	for x := 0; x < 30; x++ {
+		if got, want := p.At(x, 0), img.At(x, 0); got != want {
+			t.Fatalf("Paletted().At(%d, 0) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestQuantizeGradient(t *testing.T) {
+	img := gradient(64, 64)
+	for _, n := range []int{2, 16, 256} {
+		pal := Quantize(img, n)
+		if len(pal) != n {
+			t.Errorf("Quantize(gradient, %d) has %d colors", n, len(pal))
+		}
+	}
+
+	// more colors give a closer image
+	prev := -1
+	for _, n := range []int{4, 16, 64} {
+		p := Paletted(img, Quantize(img, n), false)
+		e := errorSum(img, p)
+		if prev >= 0 && e >= prev {
+			t.Errorf("error with %d colors = %d, not below %d", n, e, prev)
+		}
+		prev = e
+	}
+
+	// the palette doesn't depend on the pixel order
+	a := Quantize(img, 8)
+	b := Quantize(gradient(64, 64), 8)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("Quantize is not deterministic: %v, %v", a, b)
+		}
+	}
+}
+
+func errorSum(a *image.RGBA, b *image.Paletted) int {
+	sum := 0
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c0 := a.RGBAAt(x, y)
+			c1 := b.At(x, y).(color.RGBA)
+			sum += abs(int(c0.R)-int(c1.R)) + abs(int(c0.G)-int(c1.G)) + abs(int(c0.B)-int(c1.B))
+		}
+	}
+	return sum
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func TestQuantizeLargeImage(t *testing.T) {
+	// larger than maxSamples, so the pixels are sampled
+	img := gradient(400, 400)
+	if pal := Quantize(img, 32); len(pal) != 32 {
+		t.Errorf("got %d colors, want 32", len(pal))
+	}
+}
+
+func TestGIFWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewGIFWriter(&buf, 30)
+	w.Colors = 4
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	black := color.RGBA{0, 0, 0, 0xff}
+	frames := []*image.RGBA{
+		stripes(8, 4, red, black),
+		stripes(8, 4, black, red),
+		gradient(8, 4),
+	}
+	for _, f := range frames {
+		if err := w.WriteFrame(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) != len(frames) {
+		t.Fatalf("decoded %d frames, want %d", len(g.Image), len(frames))
+	}
+	// 30 fps is 3.33 hundredths per frame
+	if want := []int{3, 3, 4}; g.Delay[0] != want[0] || g.Delay[1] != want[1] || g.Delay[2] != want[2] {
+		t.Errorf("Delay = %v, want %v", g.Delay, want)
+	}
+	if g.Image[0].At(0, 0) != red || g.Image[1].At(0, 0) != black || g.Image[1].At(7, 0) != red {
+		t.Errorf("decoded pixels don't match the frames")
+	}
+	if len(g.Image[2].Palette) > 4 {
+		t.Errorf("frame 2 has %d colors, want at most 4", len(g.Image[2].Palette))
+	}
+}
+
+func TestGIFWriterStreams(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewGIFWriter(&buf, 30)
+	prev := 0
+	for i := 0; i < 3; i++ {
+		if err := w.WriteFrame(gradient(64, 64)); err != nil {
+			t.Fatal(err)
+		}
+		// each frame is written out before the next comes
+		if buf.Len() <= prev {
+			t.Fatalf("frame %d left %d bytes, no more than %d", i, buf.Len(), prev)
+		}
+		prev = buf.Len()
+	}
+	if err := w.WriteFrame(gradient(32, 64)); err == nil {
+		t.Errorf("a frame of another size was written")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) != 3 || g.LoopCount != 0 {
+		t.Errorf("decoded %d frames looping %d times, want 3 looping forever", len(g.Image), g.LoopCount)
+	}
+	if g.Config.Width != 64 || g.Config.Height != 64 {
+		t.Errorf("decoded a GIF of %dx%d, want 64x64", g.Config.Width, g.Config.Height)
+	}
+}
+
+func TestGIFWriterDelays(t *testing.T) {
+	for _, fps := range []int{7, 24, 25, 30, 60} {
+		w := NewGIFWriter(nil, fps)
+		sum := 0
+		for i := 0; i < fps; i++ {
+			sum += w.delay(i)
+		}
+		if sum != 100 {
+			t.Errorf("%d fps: delays of one second sum to %d", fps, sum)
+		}
+	}
+}
+
+func TestPNGSequence(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewPNGSequence(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := []*image.RGBA{
+		gradient(5, 5),
+		stripes(5, 5, color.RGBA{0x10, 0x20, 0x30, 0xff}),
+	}
+	for _, f := range frames {
+		if err := s.WriteFrame(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range frames {
+		f, err := os.Open(s.Path(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for y := 0; y < 5; y++ {
+			for x := 0; x < 5; x++ {
+				r0, g0, b0, a0 := img.At(x, y).RGBA()
+				r1, g1, b1, a1 := want.At(x, y).RGBA()
+				if r0 != r1 || g0 != g1 || b0 != b1 || a0 != a1 {
+					t.Fatalf("frame %d pixel (%d, %d) differs", i, x, y)
+				}
+			}
+		}
+	}
+	if _, err := os.Stat(s.Path(len(frames))); !os.IsNotExist(err) {
+		t.Errorf("an extra frame was written")
+	}
+}
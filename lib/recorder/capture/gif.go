@@ -0,0 +1,164 @@
+package capture
+
+import (
+	"bufio"
+	"compress/lzw"
+	"fmt"
+	"image"
+	"io"
+)
+
+// GIFWriter encodes frames as a looping animated GIF to W as they come, so
+// a long recording doesn't pile up in memory. Each frame gets its own
+// palette of up to Colors colors. Close ends the GIF.
+type GIFWriter struct {
+	W      io.Writer
+	FPS    int
+	Colors int
+	Dither bool
+
+	// bw holds back the small writes of a frame until it is done. Its
+	// errors stick, so they are only checked on Flush.
+	bw   *bufio.Writer
+	size image.Point
+	n    int
+}
+
+func NewGIFWriter(w io.Writer, fps int) *GIFWriter {
+	return &GIFWriter{
+		W:      w,
+		FPS:    fps,
+		Colors: 256,
+	}
+}
+
+// delay returns the delay of the frame i in 1/100 s. GIF delays are integers,
+// so they are rounded in a way that keeps the total in step with FPS.
+func (w *GIFWriter) delay(i int) int {
+	return (i+1)*100/w.FPS - i*100/w.FPS
+}
+
+// WriteFrame encodes img to W. Every frame must be the size of the first.
+func (w *GIFWriter) WriteFrame(img *image.RGBA) error {
+	if w.FPS <= 0 {
+		return fmt.Errorf("capture: invalid FPS %d", w.FPS)
+	}
+	size := img.Bounds().Size()
+	if w.n == 0 {
+		w.bw = bufio.NewWriter(w.W)
+		w.size = size
+		w.header()
+	} else if size != w.size {
+		return fmt.Errorf("capture: frame of %v, want %v", size, w.size)
+	}
+	if err := w.frame(Paletted(img, Quantize(img, w.Colors), w.Dither), w.delay(w.n)); err != nil {
+		return err
+	}
+	w.n++
+	return w.bw.Flush()
+}
+
+// Close writes the trailer of the GIF. Nothing is written without frames.
+func (w *GIFWriter) Close() error {
+	if w.n == 0 {
+		return nil
+	}
+	w.bw.WriteByte(0x3b)
+	return w.bw.Flush()
+}
+
+func (w *GIFWriter) header() {
+	w.bw.WriteString("GIF89a")
+	w.uint16(w.size.X)
+	w.uint16(w.size.Y)
+	// no global color table, the frames have their own
+	w.bw.Write([]byte{0x00, 0x00, 0x00})
+	// loop forever
+	w.bw.Write([]byte{0x21, 0xff, 0x0b})
+	w.bw.WriteString("NETSCAPE2.0")
+	w.bw.Write([]byte{0x03, 0x01, 0x00, 0x00, 0x00})
+}
+
+func (w *GIFWriter) frame(p *image.Paletted, delay int) error {
+	bits := 1
+	for 1<<bits < len(p.Palette) {
+		bits++
+	}
+
+	// graphic control extension, for the delay
+	w.bw.Write([]byte{0x21, 0xf9, 0x04, 0x00})
+	w.uint16(delay)
+	w.bw.Write([]byte{0x00, 0x00})
+
+	// image descriptor and local color table, padded to a power of two
+	w.bw.WriteByte(0x2c)
+	w.uint16(0)
+	w.uint16(0)
+	w.uint16(w.size.X)
+	w.uint16(w.size.Y)
+	w.bw.WriteByte(0x80 | byte(bits-1))
+	for i := 0; i < 1<<bits; i++ {
+		var r, g, b uint32
+		if i < len(p.Palette) {
+			r, g, b, _ = p.Palette[i].RGBA()
+		}
+		w.bw.Write([]byte{byte(r >> 8), byte(g >> 8), byte(b >> 8)})
+	}
+
+	litWidth := max(bits, 2)
+	w.bw.WriteByte(byte(litWidth))
+	blocks := &blockWriter{w: w.bw}
+	lw := lzw.NewWriter(blocks, lzw.LSB, litWidth)
+	for y := p.Rect.Min.Y; y < p.Rect.Max.Y; y++ {
+		i := p.PixOffset(p.Rect.Min.X, y)
+		if _, err := lw.Write(p.Pix[i : i+w.size.X]); err != nil {
+			return err
+		}
+	}
+	if err := lw.Close(); err != nil {
+		return err
+	}
+	blocks.close()
+	return nil
+}
+
+func (w *GIFWriter) uint16(v int) {
+	w.bw.Write([]byte{byte(v), byte(v >> 8)})
+}
+
+// blockWriter splits the image data into the sub-blocks of up to 255 bytes
+// GIF wants.
+type blockWriter struct {
+	w *bufio.Writer
+	// buf is the length of the block followed by its data.
+	buf [256]byte
+	n   int
+}
+
+func (b *blockWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		c := copy(b.buf[1+b.n:], p)
+		b.n += c
+		p = p[c:]
+		if b.n == 255 {
+			b.flush()
+		}
+	}
+	return n, nil
+}
+
+func (b *blockWriter) flush() {
+	if b.n == 0 {
+		return
+	}
+	b.buf[0] = byte(b.n)
+	b.w.Write(b.buf[:1+b.n])
+	b.n = 0
+}
+
+// close writes what is left and the empty block that ends the data.
+func (b *blockWriter) close() {
+	b.flush()
+	b.w.WriteByte(0x00)
+}
@@ -0,0 +1,141 @@
+package capture
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// maxSamples bounds the pixels looked at by Quantize. Larger images are
+// sampled evenly.
+const maxSamples = 1 << 16
+
+type colorBox struct {
+	pixels [][3]uint8
+}
+
+// longestAxis returns the channel with the largest range and the range.
+func (b *colorBox) longestAxis() (int, int) {
+	lo := [3]uint8{255, 255, 255}
+	var hi [3]uint8
+	for _, p := range b.pixels {
+		for c := 0; c < 3; c++ {
+			lo[c] = min(lo[c], p[c])
+			hi[c] = max(hi[c], p[c])
+		}
+	}
+	axis := 0
+	for c := 1; c < 3; c++ {
+		if int(hi[c])-int(lo[c]) > int(hi[axis])-int(lo[axis]) {
+			axis = c
+		}
+	}
+	return axis, int(hi[axis]) - int(lo[axis])
+}
+
+func (b *colorBox) mean() color.RGBA {
+	var sum [3]int
+	for _, p := range b.pixels {
+		for c := 0; c < 3; c++ {
+			sum[c] += int(p[c])
+		}
+	}
+	n := len(b.pixels)
+	return color.RGBA{uint8((sum[0] + n/2) / n), uint8((sum[1] + n/2) / n), uint8((sum[2] + n/2) / n), 0xff}
+}
+
+// Quantize returns a palette of at most n opaque colors for img by median
+// cut: the box of colors with the widest channel range is split at its
+// median until there are n boxes, and each box becomes its mean color.
+// An image with n colors or fewer gets exactly its colors.
+func Quantize(img *image.RGBA, n int) color.Palette {
+	n = min(max(n, 1), 256)
+	b := img.Bounds()
+	step := 1
+	if b.Dx()*b.Dy() > maxSamples {
+		step = b.Dx() * b.Dy() / maxSamples
+	}
+
+	unique := map[[3]uint8]struct{}{}
+	var pixels [][3]uint8
+	i := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if i%step == 0 {
+				// GIF frames are opaque; the pixels are premultiplied, so
+				// translucent ones are as if drawn over black
+				o := img.PixOffset(x, y)
+				p := [3]uint8{img.Pix[o], img.Pix[o+1], img.Pix[o+2]}
+				pixels = append(pixels, p)
+				unique[p] = struct{}{}
+			}
+			i++
+		}
+	}
+	if len(pixels) == 0 {
+		return color.Palette{color.Black}
+	}
+
+	if len(unique) <= n {
+		pal := make(color.Palette, 0, len(unique))
+		for p := range unique {
+			pal = append(pal, color.RGBA{p[0], p[1], p[2], 0xff})
+		}
+		sortPalette(pal)
+		return pal
+	}
+
+	boxes := []*colorBox{{pixels: pixels}}
+	for len(boxes) < n {
+		best, bestAxis, bestRange := -1, 0, 0
+		for i, bx := range boxes {
+			if len(bx.pixels) < 2 {
+				continue
+			}
+			axis, r := bx.longestAxis()
+			if r > bestRange {
+				best, bestAxis, bestRange = i, axis, r
+			}
+		}
+		if best < 0 {
+			break
+		}
+		bx := boxes[best]
+		sort.Slice(bx.pixels, func(i, j int) bool {
+			return bx.pixels[i][bestAxis] < bx.pixels[j][bestAxis]
+		})
+		mid := len(bx.pixels) / 2
+		// keep equal values in one box so that both halves differ
+		for mid > 1 && bx.pixels[mid-1][bestAxis] == bx.pixels[mid][bestAxis] {
+			mid--
+		}
+		if bx.pixels[mid-1][bestAxis] == bx.pixels[mid][bestAxis] {
+			for mid < len(bx.pixels)-1 && bx.pixels[mid-1][bestAxis] == bx.pixels[mid][bestAxis] {
+				mid++
+			}
+		}
+		boxes[best] = &colorBox{pixels: bx.pixels[:mid]}
+		boxes = append(boxes, &colorBox{pixels: bx.pixels[mid:]})
+	}
+
+	pal := make(color.Palette, 0, len(boxes))
+	for _, bx := range boxes {
+		pal = append(pal, bx.mean())
+	}
+	sortPalette(pal)
+	return pal
+}
+
+// sortPalette makes the palette independent of the order of the pixels.
+func sortPalette(pal color.Palette) {
+	sort.Slice(pal, func(i, j int) bool {
+		a, b := pal[i].(color.RGBA), pal[j].(color.RGBA)
+		if a.R != b.R {
+			return a.R < b.R
+		}
+		if a.G != b.G {
+			return a.G < b.G
+		}
+		return a.B < b.B
+	})
+}
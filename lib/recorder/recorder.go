@@ -0,0 +1,202 @@
+// Package recorder wraps an ebiten.Game and records what it draws to
+// numbered PNGs or an animated GIF.
+//
+// Frames are drawn to an offscreen image in Update at a fixed step of game
+// ticks, so the recording keeps the game's speed even when capturing is
+// slower than real time. Recording needs a file system and is not
+// available in browsers.
+package recorder
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/demouth/ebitengine-sketch/lib/recorder/capture"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+type Format int
+
+const (
+	FormatGIF Format = iota
+	FormatPNG
+)
+
+type Options struct {
+	// Key starts and stops a recording. The default is F9.
+	Key ebiten.Key
+	// Dir is where recordings are written. The default is the current
+	// directory.
+	Dir    string
+	Format Format
+	// FPS is the frame rate of the recording. The default is 30.
+	FPS int
+	// Colors and Dither are used for GIFs. The default is 256 colors.
+	Colors int
+	Dither bool
+}
+
+// Recorder is an ebiten.Game that runs Game and records it while Key is
+// toggled on. A recording still going on when the game ends, by an error
+// or by closing the window, is finished first.
+type Recorder struct {
+	Game    ebiten.Game
+	Options Options
+
+	width, height int
+
+	recording bool
+	clock     capture.Clock
+	sink      capture.Sink
+	file      *os.File
+	path      string
+	offscreen *ebiten.Image
+	frame     *image.RGBA
+}
+
+func New(game ebiten.Game, options Options) *Recorder {
+	if options.Key == 0 {
+		options.Key = ebiten.KeyF9
+	}
+	if options.FPS == 0 {
+		options.FPS = 30
+	}
+	if options.Colors == 0 {
+		options.Colors = 256
+	}
+	// the window is closed in Update, once the recording is written
+	ebiten.SetWindowClosingHandled(true)
+	return &Recorder{
+		Game:    game,
+		Options: options,
+	}
+}
+
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+func (r *Recorder) Update() error {
+	if ebiten.IsWindowBeingClosed() {
+		if r.recording {
+			r.stop()
+		}
+		return ebiten.Termination
+	}
+
+	if inpututil.IsKeyJustPressed(r.Options.Key) {
+		if r.recording {
+			r.stop()
+		} else if err := r.start(); err != nil {
+			log.Printf("recorder: %v", err)
+		}
+	}
+
+	if err := r.Game.Update(); err != nil {
+		if r.recording {
+			r.stop()
+		}
+		return err
+	}
+
+	if r.recording && r.clock.Tick() {
+		if err := r.capture(); err != nil {
+			log.Printf("recorder: %v", err)
+			r.stop()
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) Draw(screen *ebiten.Image) {
+	r.Game.Draw(screen)
+	if r.recording {
+		// the indicator is only on the screen, not in the recording
+		vector.DrawFilledCircle(screen, float32(screen.Bounds().Dx()-12), 12, 6, color.RGBA{0xff, 0, 0, 0xff}, true)
+	}
+}
+
+func (r *Recorder) Layout(outsideWidth, outsideHeight int) (int, int) {
+	r.width, r.height = r.Game.Layout(outsideWidth, outsideHeight)
+	return r.width, r.height
+}
+
+func (r *Recorder) start() error {
+	if r.width <= 0 || r.height <= 0 {
+		return fmt.Errorf("the screen size is not known yet")
+	}
+	name := "recording-" + time.Now().Format("20060102-150405")
+	r.clock = capture.Clock{TPS: ebiten.TPS(), FPS: r.Options.FPS}
+
+	switch r.Options.Format {
+	case FormatPNG:
+		r.path = filepath.Join(r.Options.Dir, name)
+		s, err := capture.NewPNGSequence(r.path)
+		if err != nil {
+			return err
+		}
+		r.sink = s
+	default:
+		r.path = filepath.Join(r.Options.Dir, name+".gif")
+		f, err := os.Create(r.path)
+		if err != nil {
+			return err
+		}
+		w := capture.NewGIFWriter(f, r.Options.FPS)
+		w.Colors = r.Options.Colors
+		w.Dither = r.Options.Dither
+		r.file = f
+		r.sink = w
+	}
+
+	// the size is fixed for the whole recording even if the window is resized
+	if r.offscreen == nil || r.offscreen.Bounds().Dx() != r.width || r.offscreen.Bounds().Dy() != r.height {
+		r.offscreen = ebiten.NewImage(r.width, r.height)
+		r.frame = image.NewRGBA(image.Rect(0, 0, r.width, r.height))
+	}
+	r.recording = true
+	log.Printf("recorder: recording to %s", r.path)
+	return nil
+}
+
+func (r *Recorder) capture() error {
+	r.offscreen.Clear()
+	r.Game.Draw(r.offscreen)
+	r.offscreen.ReadPixels(r.frame.Pix)
+	return r.sink.WriteFrame(r.frame)
+}
+
+func (r *Recorder) stop() {
+	r.recording = false
+	err := r.sink.Close()
+	if r.file != nil {
+		if cerr := r.file.Close(); err == nil {
+			err = cerr
+		}
+		r.file = nil
+	}
+	r.sink = nil
+	if err != nil {
+		log.Printf("recorder: %v", err)
+		return
+	}
+	log.Printf("recorder: wrote %d frames (%v) to %s", r.clock.Frames(), r.clock.Duration(), r.path)
+}
+
+// ParseFormat parses "gif" or "png", e.g. from a flag.
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "gif":
+		return FormatGIF, nil
+	case "png":
+		return FormatPNG, nil
+	}
+	return 0, fmt.Errorf("recorder: unknown format %q", s)
+}
@@ -0,0 +1,65 @@
+// Package seed picks the random seed of a sketch, so that an interesting run
+// can be reproduced. The seed comes from the -seed flag on desktop and the
+// ?seed= query parameter in browsers. Without one, a seed is picked from the
+// time; show it on the screen so that it can be passed back.
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	once  sync.Once
+	value int64
+)
+
+// Get returns the seed of this run. Every call returns the same seed.
+func Get() int64 {
+	once.Do(func() {
+		s, ok := lookup()
+		if !ok {
+			// small enough to be typed back
+			s = time.Now().UnixNano() % 1000000
+		}
+		value = s
+	})
+	return value
+}
+
+// New returns a generator seeded with Get.
+func New() *rand.Rand {
+	return rand.New(rand.NewSource(Get()))
+}
+
+// Label returns the seed formatted to be shown on the screen.
+func Label() string {
+	return fmt.Sprintf("seed: %d", Get())
+}
+
+func Parse(s string) (int64, error) {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("seed: invalid seed %q", s)
+	}
+	return n, nil
+}
+
+// FromQuery returns the seed parameter of a URL query such as
+// location.search.
+func FromQuery(query string) (int64, bool) {
+	values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil || !values.Has("seed") {
+		return 0, false
+	}
+	n, err := Parse(values.Get("seed"))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
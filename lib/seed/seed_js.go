@@ -0,0 +1,9 @@
+//go:build js
+
+package seed
+
+import "syscall/js"
+
+func lookup() (int64, bool) {
+	return FromQuery(js.Global().Get("location").Get("search").String())
+}
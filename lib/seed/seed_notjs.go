@@ -0,0 +1,25 @@
+//go:build !js
+
+package seed
+
+import (
+	"flag"
+	"log"
+)
+
+var flagSeed = flag.String("seed", "", "the random seed (default: picked from the time)")
+
+func lookup() (int64, bool) {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	if *flagSeed == "" {
+		return 0, false
+	}
+	n, err := Parse(*flagSeed)
+	if err != nil {
+		log.Print(err)
+		return 0, false
+	}
+	return n, true
+}
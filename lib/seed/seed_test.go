@@ -0,0 +1,40 @@
+package seed
+
+import "testing"
+
+func TestFromQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int64
+		ok    bool
+	}{
+		{"?seed=42", 42, true},
+		{"seed=42", 42, true},
+		{"?foo=bar&seed=-7", -7, true},
+		{"?seed=%2012", 12, true},
+		{"", 0, false},
+		{"?foo=bar", 0, false},
+		{"?seed=", 0, false},
+		{"?seed=abc", 0, false},
+		{"?seed=1e3", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := FromQuery(tt.query)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("FromQuery(%q) = %d, %v, want %d, %v", tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	s := Get()
+	if Get() != s {
+		t.Errorf("Get() changed between calls")
+	}
+	a, b := New(), New()
+	for i := 0; i < 10; i++ {
+		if a.Int63() != b.Int63() {
+			t.Fatalf("two generators from New() differ")
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package shaderfile loads Kage sources from disk, reloads them when they
+// change and keeps a library of named presets.
+package shaderfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Ext is the extension of Kage source files.
+const Ext = ".kage"
+
+// Clock returns the current time. Tests replace it with a fake one.
+type Clock func() time.Time
+
+// Load reads a Kage source file.
+func Load(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// Watcher polls the modification time of a file and reports when it
+// changes. Call Poll every frame; the file is only checked every Interval.
+type Watcher struct {
+	Path     string
+	Interval time.Duration
+	Clock    Clock
+
+	checked time.Time
+	modTime time.Time
+	size    int64
+}
+
+func NewWatcher(path string, interval time.Duration) *Watcher {
+	return &Watcher{
+		Path:     path,
+		Interval: interval,
+		Clock:    time.Now,
+	}
+}
+
+// Poll returns the content of the file when it was modified since the last
+// call that returned changed. The first successful call always reports a
+// change. A file that can't be read is reported as err and retried at the
+// next interval.
+func (w *Watcher) Poll() (src []byte, changed bool, err error) {
+	now := w.Clock()
+	if !w.checked.IsZero() && now.Sub(w.checked) < w.Interval {
+		return nil, false, nil
+	}
+	w.checked = now
+
+	fi, err := os.Stat(w.Path)
+	if err != nil {
+		return nil, false, err
+	}
+	if fi.ModTime().Equal(w.modTime) && fi.Size() == w.size {
+		return nil, false, nil
+	}
+	src, err = os.ReadFile(w.Path)
+	if err != nil {
+		return nil, false, err
+	}
+	w.modTime = fi.ModTime()
+	w.size = fi.Size()
+	return src, true, nil
+}
+
+// Preset is a named shader source in a PresetStore.
+type Preset struct {
+	Name    string
+	ModTime time.Time
+}
+
+// PresetStore keeps presets as <name>.kage files in Dir.
+type PresetStore struct {
+	Dir   string
+	Clock Clock
+}
+
+// DefaultPresetDir is the library shared by all the shader sketches.
+func DefaultPresetDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ebitengine-sketch", "presets"), nil
+}
+
+func NewPresetStore(dir string) *PresetStore {
+	return &PresetStore{
+		Dir:   dir,
+		Clock: time.Now,
+	}
+}
+
+var ErrInvalidName = errors.New("shaderfile: invalid preset name")
+
+func (s *PresetStore) path(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", ErrInvalidName
+	}
+	return filepath.Join(s.Dir, name+Ext), nil
+}
+
+// Save writes src as the preset name, replacing an existing one.
+func (s *PresetStore) Save(name string, src []byte) error {
+	p, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	// write to a temporary file first so that a crash never leaves a half
+	// written preset behind
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, src, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		return err
+	}
+	now := s.Clock()
+	return os.Chtimes(p, now, now)
+}
+
+// Load reads the preset name.
+func (s *PresetStore) Load(name string) ([]byte, error) {
+	p, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+// Delete removes the preset name.
+func (s *PresetStore) Delete(name string) error {
+	p, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
+}
+
+// List returns the presets sorted by name. A missing Dir is an empty library.
+func (s *PresetStore) List() ([]Preset, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var presets []Preset
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != Ext {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, Preset{
+			Name:    strings.TrimSuffix(e.Name(), Ext),
+			ModTime: fi.ModTime(),
+		})
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+	return presets, nil
+}
@@ -0,0 +1,175 @@
+package shaderfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func writeFile(t *testing.T, path, src string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherPoll(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "shader.kage")
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeFile(t, path, "package main // v1", base)
+
+	clock := &fakeClock{now: base}
+	w := NewWatcher(path, 500*time.Millisecond)
+	w.Clock = clock.Now
+
+	poll := func(wantChanged bool, wantSrc string) {
+		t.Helper()
+		src, changed, err := w.Poll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed != wantChanged || string(src) != wantSrc {
+			t.Fatalf("Poll() = %q, %v, want %q, %v", src, changed, wantSrc, wantChanged)
+		}
+	}
+
+	// the first poll loads the file
+	poll(true, "package main // v1")
+	clock.Advance(time.Second)
+	poll(false, "")
+
+	// a change is not noticed before the interval elapses
+	writeFile(t, path, "package main // v2", base.Add(time.Minute))
+	clock.Advance(100 * time.Millisecond)
+	poll(false, "")
+	clock.Advance(400 * time.Millisecond)
+	poll(true, "package main // v2")
+
+	// a change of size with the same modification time still counts
+	clock.Advance(time.Second)
+	writeFile(t, path, "package main // v3 longer", base.Add(time.Minute))
+	poll(true, "package main // v3 longer")
+}
+
+func TestWatcherMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "missing.kage")
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	w := NewWatcher(path, time.Second)
+	w.Clock = clock.Now
+
+	if _, changed, err := w.Poll(); !errors.Is(err, os.ErrNotExist) || changed {
+		t.Fatalf("Poll() on a missing file = %v, %v", changed, err)
+	}
+
+	// the file appears later
+	writeFile(t, path, "package main", clock.now)
+	if _, changed, err := w.Poll(); err != nil || changed {
+		t.Fatalf("Poll() within the interval = %v, %v, want no check", changed, err)
+	}
+	clock.Advance(time.Second)
+	if src, changed, err := w.Poll(); err != nil || !changed || string(src) != "package main" {
+		t.Fatalf("Poll() = %q, %v, %v", src, changed, err)
+	}
+}
+
+func TestPresetStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "presets")
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewPresetStore(dir)
+	s.Clock = clock.Now
+
+	// an empty library
+	presets, err := s.List()
+	if err != nil || len(presets) != 0 {
+		t.Fatalf("List() on a missing dir = %v, %v", presets, err)
+	}
+
+	if err := s.Save("waves", []byte("package main // waves")); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Hour)
+	if err := s.Save("galaxy", []byte("package main // galaxy")); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := s.Load("waves")
+	if err != nil || string(src) != "package main // waves" {
+		t.Fatalf("Load(waves) = %q, %v", src, err)
+	}
+
+	presets, err = s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Preset{
+		{Name: "galaxy", ModTime: clock.now},
+		{Name: "waves", ModTime: clock.now.Add(-time.Hour)},
+	}
+	if len(presets) != len(want) {
+		t.Fatalf("List() = %v, want %v", presets, want)
+	}
+	for i := range want {
+		if presets[i].Name != want[i].Name || !presets[i].ModTime.Equal(want[i].ModTime) {
+			t.Errorf("List()[%d] = %v, want %v", i, presets[i], want[i])
+		}
+	}
+
+	// overwrite
+	clock.Advance(time.Hour)
+	if err := s.Save("waves", []byte("package main // waves 2")); err != nil {
+		t.Fatal(err)
+	}
+	if src, _ := s.Load("waves"); string(src) != "package main // waves 2" {
+		t.Errorf("Load after overwrite = %q", src)
+	}
+
+	if err := s.Delete("galaxy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load("galaxy"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load of a deleted preset: err = %v", err)
+	}
+	if presets, _ := s.List(); len(presets) != 1 || presets[0].Name != "waves" {
+		t.Errorf("List() after Delete = %v", presets)
+	}
+}
+
+func TestPresetStoreInvalidNames(t *testing.T) {
+	s := NewPresetStore(t.TempDir())
+	for _, name := range []string{"", ".", "..", "../escape", `a\b`, "a/b"} {
+		if err := s.Save(name, nil); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Save(%q): err = %v, want ErrInvalidName", name, err)
+		}
+		if _, err := s.Load(name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Load(%q): err = %v, want ErrInvalidName", name, err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.kage")
+	writeFile(t, path, "package main", time.Now())
+	src, err := Load(path)
+	if err != nil || string(src) != "package main" {
+		t.Errorf("Load() = %q, %v", src, err)
+	}
+}